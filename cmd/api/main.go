@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"go-server/internal/company"
 	"go-server/internal/config"
 	"go-server/internal/db"
+	"go-server/internal/employee"
+	"go-server/internal/mailer"
+	"go-server/internal/payroll"
+	"go-server/internal/scheduler"
 	"go-server/internal/server"
+	"go-server/internal/signing"
 	"go-server/internal/support"
 	"log"
+	_ "time/tzdata"
 )
 func main(){
 	config,err := config.Load()
@@ -15,6 +23,10 @@ func main(){
 	}
 	fmt.Println("Config loaded successfully:", config)
 
+	if _, err := signing.Default(); err != nil {
+		log.Fatalf("Failed to load document signing key: %v", err)
+	}
+
 	database,err := db.Connect(config)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
@@ -31,6 +43,29 @@ func main(){
 		db.Migrate(database,&support.Support{})
 	}()
 
+	// Daily jobs run at their time in the company time zone
+	location, err := company.NewRepo(database).Location(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to load company time zone: %v", err)
+	}
+	mail, err := mailer.FromEnv()
+	if err != nil {
+		log.Printf("Reconciliation alerts will not be emailed: %v", err)
+	}
+	scheduler.Start(context.Background(), location, scheduler.Job{
+		Name: "payroll reconciliation",
+		Hour: 2,
+		Run:  payroll.NewReconciliationJob(database, mail, location).Run,
+	}, scheduler.Job{
+		Name: "employee document expiry",
+		Hour: 7,
+		Run:  employee.NewDocumentExpiryJob(database, location).Run,
+	}, scheduler.Job{
+		Name: "employee contracts",
+		Hour: 7,
+		Run:  employee.NewContractJob(database, location).Run,
+	})
+
 	router := server.NewRouter(database)
 
 	addr := fmt.Sprintf(":%s", config.ServerPort)
@@ -11,7 +11,9 @@ import (
 	"go-server/internal/config"
 	"go-server/internal/db"
 	"go-server/internal/employee"
+	"go-server/internal/organization"
 	"go-server/internal/payroll"
+	"go-server/internal/taxrules"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -273,6 +275,9 @@ func seedEmployees(database *gorm.DB) error {
 		result := database.Where("first_name = ? AND last_name = ?", emp.FirstName, emp.LastName).First(&existingEmployee)
 		if result.Error != nil {
 			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				if err := seedAssignment(database, &emp); err != nil {
+					return err
+				}
 				if err := database.Create(&emp).Error; err != nil {
 					return fmt.Errorf("failed to create employee %s %s: %w", emp.FirstName, emp.LastName, err)
 				}
@@ -289,14 +294,30 @@ func seedEmployees(database *gorm.DB) error {
 	return nil
 }
 
+// seedAssignment finds or creates the department and position named on a default employee and references them
+func seedAssignment(database *gorm.DB, emp *employee.Employee) error {
+	department := organization.Department{Name: emp.Department, IsActive: true}
+	if err := database.Where("LOWER(name) = LOWER(?)", department.Name).FirstOrCreate(&department).Error; err != nil {
+		return fmt.Errorf("failed to seed department %s: %w", department.Name, err)
+	}
+	position := organization.Position{Title: emp.Position, DepartmentID: &department.ID, IsActive: true}
+	if err := database.Where("LOWER(title) = LOWER(?)", position.Title).FirstOrCreate(&position).Error; err != nil {
+		return fmt.Errorf("failed to seed position %s: %w", position.Title, err)
+	}
+	emp.DepartmentID = &department.ID
+	emp.PositionID = &position.ID
+	return nil
+}
+
 func seedIRSATaxBrackets(database *gorm.DB) error {
 	fmt.Println("Seeding default IRSA tax brackets...")
 
 	// Default system user ID for created_by
 	systemUserID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
 
-	// IRSA tax brackets for Madagascar (example values - adjust according to actual regulations)
-	taxBrackets := []payroll.IRSATaxBracket{
+	// IRSA tax brackets for Madagascar (example values - adjust according to actual regulations).
+	// Each bracket starts where the previous one ends, as the tax rules require.
+	taxBrackets := []taxrules.IRSATaxBracket{
 		{
 			MinIncome:   0,
 			MaxIncome:   float64Ptr(350000),
@@ -308,7 +329,7 @@ func seedIRSATaxBrackets(database *gorm.DB) error {
 			CreatedBy:   systemUserID,
 		},
 		{
-			MinIncome:   350001,
+			MinIncome:   350000,
 			MaxIncome:   float64Ptr(400000),
 			TaxRate:     0.05,
 			MinTax:      0,
@@ -318,7 +339,7 @@ func seedIRSATaxBrackets(database *gorm.DB) error {
 			CreatedBy:   systemUserID,
 		},
 		{
-			MinIncome:   400001,
+			MinIncome:   400000,
 			MaxIncome:   float64Ptr(500000),
 			TaxRate:     0.10,
 			MinTax:      2500,
@@ -328,7 +349,7 @@ func seedIRSATaxBrackets(database *gorm.DB) error {
 			CreatedBy:   systemUserID,
 		},
 		{
-			MinIncome:   500001,
+			MinIncome:   500000,
 			MaxIncome:   float64Ptr(600000),
 			TaxRate:     0.15,
 			MinTax:      12500,
@@ -338,7 +359,7 @@ func seedIRSATaxBrackets(database *gorm.DB) error {
 			CreatedBy:   systemUserID,
 		},
 		{
-			MinIncome:   600001,
+			MinIncome:   600000,
 			MaxIncome:   nil, // No upper limit
 			TaxRate:     0.20,
 			MinTax:      27500,
@@ -350,7 +371,7 @@ func seedIRSATaxBrackets(database *gorm.DB) error {
 	}
 
 	for _, bracket := range taxBrackets {
-		var existingBracket payroll.IRSATaxBracket
+		var existingBracket taxrules.IRSATaxBracket
 		var result *gorm.DB
 		if bracket.MaxIncome == nil {
 			result = database.Where("min_income = ? AND max_income IS NULL", bracket.MinIncome).First(&existingBracket)
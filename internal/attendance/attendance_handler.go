@@ -219,11 +219,19 @@ func (h *Handler) UpdateAttendance(c *gin.Context) {
 		attendance.TotalHours = &hours
 
 		// Calculate overtime (> 8 hours)
+		previousOvertime := attendance.OvertimeHours
 		if hours > 8 {
 			attendance.OvertimeHours = hours - 8
 		} else {
 			attendance.OvertimeHours = 0
 		}
+
+		// Corrected overtime must be approved again before it is paid
+		if attendance.OvertimeHours != previousOvertime {
+			attendance.OvertimeApproved = false
+			attendance.OvertimeApprovedBy = nil
+			attendance.OvertimeApprovedAt = nil
+		}
 	}
 
 	if err := h.repo.Update(c.Request.Context(), attendance); err != nil {
@@ -234,6 +242,41 @@ func (h *Handler) UpdateAttendance(c *gin.Context) {
 	c.JSON(http.StatusOK, attendance)
 }
 
+// ApproveOvertime handles approval of an attendance record's overtime for payroll (HR/Admin only)
+func (h *Handler) ApproveOvertime(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attendance ID"})
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "hr" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can approve overtime"})
+		return
+	}
+
+	if _, err := h.repo.GetByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attendance not found"})
+		return
+	}
+
+	attendance, err := h.repo.ApproveOvertime(c.Request.Context(), id, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attendance)
+}
+
 // GetStats retrieves attendance statistics for an employee
 func (h *Handler) GetStats(c *gin.Context) {
 	employeeIDParam := c.Param("employee_id")
@@ -19,6 +19,9 @@ type Attendance struct {
 	Status          string         `gorm:"type:varchar(20);default:'present';check:status IN ('present', 'absent', 'late', 'overtime', 'half_day')" json:"status"`
 	TotalHours      *float64       `gorm:"type:numeric(5,2)" json:"total_hours,omitempty"`
 	OvertimeHours   float64        `gorm:"type:numeric(5,2);default:0" json:"overtime_hours"`
+	OvertimeApproved   bool        `gorm:"default:false" json:"overtime_approved"`
+	OvertimeApprovedBy *uuid.UUID  `gorm:"type:uuid" json:"overtime_approved_by,omitempty"`
+	OvertimeApprovedAt *time.Time  `json:"overtime_approved_at,omitempty"`
 	Notes           string         `gorm:"type:text" json:"notes,omitempty"`
 	CreatedAt       time.Time      `gorm:"default:now()" json:"created_at"`
 	UpdatedAt       time.Time      `gorm:"default:now()" json:"updated_at"`
@@ -41,6 +44,7 @@ type AttendanceListQuery struct {
 	StartDate  *time.Time `form:"start_date"`
 	EndDate    *time.Time `form:"end_date"`
 	Status     string     `form:"status" binding:"omitempty,oneof=present absent late overtime half_day"`
+	OvertimeApproved *bool `form:"overtime_approved"`
 	Limit      int        `form:"limit" binding:"omitempty,min=1,max=100"`
 	Offset     int        `form:"offset" binding:"omitempty,min=0"`
 }
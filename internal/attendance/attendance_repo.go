@@ -111,6 +111,27 @@ func (r *Repo) Update(ctx context.Context, attendance *Attendance) error {
 	return nil
 }
 
+// ApproveOvertime marks an attendance record's overtime as approved for payroll
+func (r *Repo) ApproveOvertime(ctx context.Context, id uuid.UUID, approverID uuid.UUID) (*Attendance, error) {
+	attendance, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attendance.OvertimeHours <= 0 {
+		return nil, fmt.Errorf("attendance record has no overtime to approve")
+	}
+
+	now := time.Now()
+	attendance.OvertimeApproved = true
+	attendance.OvertimeApprovedBy = &approverID
+	attendance.OvertimeApprovedAt = &now
+
+	if err := r.Update(ctx, attendance); err != nil {
+		return nil, err
+	}
+	return attendance, nil
+}
+
 // List retrieves attendance records with filtering
 func (r *Repo) List(ctx context.Context, query AttendanceListQuery) ([]Attendance, int64, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -138,6 +159,10 @@ func (r *Repo) List(ctx context.Context, query AttendanceListQuery) ([]Attendanc
 		db = db.Where("status = ?", query.Status)
 	}
 
+	if query.OvertimeApproved != nil {
+		db = db.Where("overtime_hours > 0 AND overtime_approved = ?", *query.OvertimeApproved)
+	}
+
 	// Count total
 	if err := db.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("count attendance: %w", err)
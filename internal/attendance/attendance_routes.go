@@ -31,6 +31,9 @@ func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
 		// Update attendance (HR/Admin only)
 		attendance.PUT("/:id", middleware.RequireRole("admin", "hr"), handler.UpdateAttendance)
 
+		// Approve overtime for payroll (HR/Admin only)
+		attendance.PUT("/:id/approve-overtime", middleware.RequireRole("admin", "hr"), handler.ApproveOvertime)
+
 		// Get attendance statistics
 		attendance.GET("/stats/:employee_id", handler.GetStats)
 	}
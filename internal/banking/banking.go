@@ -0,0 +1,193 @@
+// Package banking validates and masks the bank accounts and mobile money numbers salaries are paid to:
+// Malagasy RIBs and IBANs, BICs, and MVola, Orange Money and Airtel Money numbers.
+package banking
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Mobile money operators
+const (
+	OperatorMVola       = "mvola"
+	OperatorOrangeMoney = "orange_money"
+	OperatorAirtelMoney = "airtel_money"
+)
+
+// operatorPrefixes maps the mobile prefixes of Madagascar to their operator
+var operatorPrefixes = map[string]string{
+	"32": OperatorOrangeMoney,
+	"37": OperatorOrangeMoney,
+	"33": OperatorAirtelMoney,
+	"34": OperatorMVola,
+	"38": OperatorMVola,
+}
+
+// NormalizeAccount validates a bank account given as a 23-digit Malagasy RIB or as an IBAN and returns it as an IBAN
+func NormalizeAccount(account string) (string, error) {
+	compact := compactAccount(account)
+	if compact == "" {
+		return "", fmt.Errorf("account number is required")
+	}
+	if isDigits(compact) {
+		if err := validateRIB(compact); err != nil {
+			return "", err
+		}
+		return ibanFromRIB(compact), nil
+	}
+	if err := validateIBAN(compact); err != nil {
+		return "", err
+	}
+	return compact, nil
+}
+
+// RIB returns the domestic RIB of a Malagasy IBAN, or "" for a foreign one
+func RIB(iban string) string {
+	if len(iban) == 27 && strings.HasPrefix(iban, "MG") {
+		return iban[4:]
+	}
+	return ""
+}
+
+// NormalizeBIC validates a BIC (SWIFT code) of 8 or 11 characters and returns it in upper case
+func NormalizeBIC(bic string) (string, error) {
+	bic = strings.ToUpper(strings.TrimSpace(bic))
+	if len(bic) != 8 && len(bic) != 11 {
+		return "", fmt.Errorf("BIC must have 8 or 11 characters")
+	}
+	for i, r := range bic {
+		isLetter := r >= 'A' && r <= 'Z'
+		isDigit := r >= '0' && r <= '9'
+		if (i < 6 && !isLetter) || (i >= 6 && !isLetter && !isDigit) {
+			return "", fmt.Errorf("invalid BIC %s", bic)
+		}
+	}
+	return bic, nil
+}
+
+// NormalizeMSISDN validates a Malagasy mobile number, local (034 12 345 67) or international (+261 34 12 345 67),
+// and returns it in international form without the plus sign, e.g. 261341234567
+func NormalizeMSISDN(number string) (string, error) {
+	compact := strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(strings.TrimSpace(number))
+	compact = strings.TrimPrefix(compact, "+")
+	compact = strings.TrimPrefix(compact, "00")
+	switch {
+	case len(compact) == 10 && strings.HasPrefix(compact, "0"):
+		compact = "261" + compact[1:]
+	case len(compact) == 9:
+		compact = "261" + compact
+	}
+	if len(compact) != 12 || !strings.HasPrefix(compact, "261") || !isDigits(compact) {
+		return "", fmt.Errorf("invalid Malagasy mobile number %s", number)
+	}
+	if MobileOperator(compact) == "" {
+		return "", fmt.Errorf("mobile number %s is not on a mobile money network", number)
+	}
+	return compact, nil
+}
+
+// MobileOperator returns the operator of a normalized mobile number from its prefix, or "" when unknown
+func MobileOperator(msisdn string) string {
+	if len(msisdn) < 5 {
+		return ""
+	}
+	return operatorPrefixes[msisdn[3:5]]
+}
+
+// LocalMSISDN formats a normalized mobile number as dialled in Madagascar, e.g. 0341234567
+func LocalMSISDN(msisdn string) string {
+	return "0" + strings.TrimPrefix(msisdn, "261")
+}
+
+// MaskAccount hides all but the last four characters of an account number, e.g. MG46 **** 5678
+func MaskAccount(iban string) string {
+	if len(iban) <= 8 {
+		return strings.Repeat("*", len(iban))
+	}
+	return iban[:4] + " **** " + iban[len(iban)-4:]
+}
+
+// MaskMSISDN hides the middle of a normalized mobile number, e.g. +261 34 ** *** 67
+func MaskMSISDN(msisdn string) string {
+	if len(msisdn) != 12 {
+		return strings.Repeat("*", len(msisdn))
+	}
+	return "+261 " + msisdn[3:5] + " ** *** " + msisdn[10:]
+}
+
+// validateRIB checks the length and key of a RIB: 5-digit bank code, 5-digit branch code, 11-digit account number
+// and a 2-digit key equal to 97 - ((89 × bank + 15 × branch + 3 × account) mod 97)
+func validateRIB(rib string) error {
+	if len(rib) != 23 {
+		return fmt.Errorf("RIB must have 23 digits")
+	}
+	bank, _ := strconv.ParseInt(rib[0:5], 10, 64)
+	branch, _ := strconv.ParseInt(rib[5:10], 10, 64)
+	account, _ := strconv.ParseInt(rib[10:21], 10, 64)
+	key, _ := strconv.ParseInt(rib[21:23], 10, 64)
+	if 97-(89*bank+15*branch+3*(account%97))%97 != key {
+		return fmt.Errorf("invalid RIB key")
+	}
+	return nil
+}
+
+// validateIBAN checks the length and ISO 13616 check digits of an IBAN
+func validateIBAN(iban string) error {
+	if len(iban) < 15 || len(iban) > 34 {
+		return fmt.Errorf("IBAN must have between 15 and 34 characters")
+	}
+	if strings.HasPrefix(iban, "MG") && len(iban) != 27 {
+		return fmt.Errorf("a Malagasy IBAN must have 27 characters")
+	}
+	for i, r := range iban {
+		isLetter := r >= 'A' && r <= 'Z'
+		isDigit := r >= '0' && r <= '9'
+		if (i < 2 && !isLetter) || (i >= 2 && i < 4 && !isDigit) || (!isLetter && !isDigit) {
+			return fmt.Errorf("invalid IBAN %s", iban)
+		}
+	}
+	if ibanRemainder(iban[4:]+iban[:4]) != 1 {
+		return fmt.Errorf("invalid IBAN check digits")
+	}
+	return nil
+}
+
+// ibanFromRIB builds the Malagasy IBAN of a RIB by computing its check digits
+func ibanFromRIB(rib string) string {
+	check := 98 - ibanRemainder(rib+"MG00")
+	return fmt.Sprintf("MG%02d%s", check, rib)
+}
+
+// ibanRemainder converts letters to numbers (A = 10 … Z = 35) and returns the result modulo 97
+func ibanRemainder(s string) int64 {
+	var digits strings.Builder
+	for _, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(r-'A') + 10))
+			continue
+		}
+		digits.WriteRune(r)
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64()
+}
+
+// compactAccount strips the spaces and dashes account numbers are often written with
+func compactAccount(account string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(account)))
+}
+
+// isDigits reports whether s is a non-empty string of digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
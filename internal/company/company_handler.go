@@ -2,11 +2,11 @@ package company
 
 import (
 	"net/http"
-	"path/filepath"
-	"strings"
 	"time"
 
+	"go-server/internal/banking"
 	"go-server/internal/middleware"
+	"go-server/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -125,6 +125,34 @@ func (h *Handler) Update(c *gin.Context) {
 	if input.MinimumSalary != nil {
 		settings.MinimumSalary = *input.MinimumSalary
 	}
+	if input.PayrollBankName != nil {
+		settings.PayrollBankName = input.PayrollBankName
+	}
+	if input.PayrollBankIBAN != nil {
+		// Accept the account as a RIB or an IBAN and store it as an IBAN; an empty value clears it
+		if *input.PayrollBankIBAN == "" {
+			settings.PayrollBankIBAN = nil
+		} else {
+			iban, err := banking.NormalizeAccount(*input.PayrollBankIBAN)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			settings.PayrollBankIBAN = &iban
+		}
+	}
+	if input.PayrollBankBIC != nil {
+		if *input.PayrollBankBIC == "" {
+			settings.PayrollBankBIC = nil
+		} else {
+			bic, err := banking.NormalizeBIC(*input.PayrollBankBIC)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			settings.PayrollBankBIC = &bic
+		}
+	}
 
 	// Set updated by
 	settings.UpdatedBy = &userID
@@ -165,19 +193,23 @@ func (h *Handler) UploadLogo(c *gin.Context) {
 		return
 	}
 
-	// Validate file type
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".gif" {
+	// Validate file type from its content
+	content, contentType, err := storage.ReadUpload(file)
+	if err == storage.ErrTooLarge {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File exceeds the maximum upload size"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	if _, ok := logoTypes[contentType]; !ok {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type. Only JPG, PNG, and GIF are allowed"})
 		return
 	}
 
-	// For now, we'll just return a mock URL
-	// In a real implementation, you would upload to a cloud storage service
-	logoURL := "https://example.com/logos/company-logo-" + uuid.New().String() + ext
-
-	// Update logo URL in database
-	if err := h.repo.UpdateLogo(c.Request.Context(), logoURL, userID); err != nil {
+	logoURL, err := h.repo.StoreLogo(c.Request.Context(), content, contentType, userID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update logo"})
 		return
 	}
@@ -189,6 +221,23 @@ func (h *Handler) UploadLogo(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetLogo serves a stored company logo (public, so it can be embedded in pages and emails)
+func (h *Handler) GetLogo(c *gin.Context) {
+	content, contentType, err := h.repo.GetLogo(c.Request.Context(), c.Param("file_name"))
+	if err == storage.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Logo not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read logo"})
+		return
+	}
+
+	// Logo file names are never reused
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Data(http.StatusOK, contentType, content)
+}
+
 // ListHolidays retrieves company holidays
 func (h *Handler) ListHolidays(c *gin.Context) {
 	var query HolidaysListQuery
@@ -0,0 +1,82 @@
+package company
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-server/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// logoTypes are the image types accepted for the company logo, with the extension they are stored under
+var logoTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+// logoNamePattern matches the file names logos are stored under
+var logoNamePattern = regexp.MustCompile(`^logo-[0-9a-f-]{36}\.(png|jpg|gif)$`)
+
+// logoURLPrefix is the public path logos are served from
+const logoURLPrefix = "/api/v1/company/logo/"
+
+// StoreLogo stores a new company logo under a fresh name and points the company settings at it, returning its
+// URL. Earlier logos stay available, as documents already issued may link to them.
+func (r *Repo) StoreLogo(ctx context.Context, content []byte, contentType string, updatedBy uuid.UUID) (string, error) {
+	ext, ok := logoTypes[contentType]
+	if !ok {
+		return "", fmt.Errorf("unsupported logo type %s", contentType)
+	}
+	files, err := storage.Default()
+	if err != nil {
+		return "", fmt.Errorf("open file storage: %w", err)
+	}
+
+	name := "logo-" + uuid.New().String() + ext
+	storeCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if err := files.Put(storeCtx, "company/"+name, content, contentType); err != nil {
+		return "", fmt.Errorf("store company logo: %w", err)
+	}
+
+	logoURL := logoURLPrefix + name
+	if err := r.UpdateLogo(ctx, logoURL, updatedBy); err != nil {
+		if err := files.Delete(storeCtx, "company/"+name); err != nil {
+			log.Printf("company: failed to remove unrecorded logo %s: %v", name, err)
+		}
+		return "", err
+	}
+	return logoURL, nil
+}
+
+// GetLogo retrieves a stored company logo by file name, with its content type
+func (r *Repo) GetLogo(ctx context.Context, name string) ([]byte, string, error) {
+	if !logoNamePattern.MatchString(name) {
+		return nil, "", storage.ErrNotFound
+	}
+	files, err := storage.Default()
+	if err != nil {
+		return nil, "", fmt.Errorf("open file storage: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	content, err := files.Get(ctx, "company/"+name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := "application/octet-stream"
+	for t, ext := range logoTypes {
+		if strings.HasSuffix(name, ext) {
+			contentType = t
+		}
+	}
+	return content, contentType, nil
+}
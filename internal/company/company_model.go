@@ -28,6 +28,9 @@ type CompanySettings struct {
 	OvertimeSundayRate   float64    `gorm:"type:decimal(4,2);default:2.00" json:"overtime_sunday_rate"`
 	AnnualLeaveDays      int        `gorm:"default:30" json:"annual_leave_days"`
 	MinimumSalary        float64    `gorm:"type:decimal(15,2);default:200000" json:"minimum_salary"`
+	PayrollBankName      *string    `gorm:"type:varchar(100)" json:"payroll_bank_name,omitempty"`
+	PayrollBankIBAN      *string    `gorm:"type:varchar(34)" json:"payroll_bank_iban,omitempty"`
+	PayrollBankBIC       *string    `gorm:"type:varchar(11)" json:"payroll_bank_bic,omitempty"`
 	UpdatedAt            time.Time  `gorm:"default:now()" json:"updated_at"`
 	UpdatedBy            *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
 }
@@ -53,6 +56,9 @@ type UpdateCompanySettingsRequest struct {
 	OvertimeSundayRate   *float64 `json:"overtime_sunday_rate,omitempty"`
 	AnnualLeaveDays      *int     `json:"annual_leave_days,omitempty"`
 	MinimumSalary        *float64 `json:"minimum_salary,omitempty"`
+	PayrollBankName      *string  `json:"payroll_bank_name,omitempty"`
+	PayrollBankIBAN      *string  `json:"payroll_bank_iban,omitempty"`
+	PayrollBankBIC       *string  `json:"payroll_bank_bic,omitempty"`
 }
 
 // UploadLogoResponse represents the response for logo upload
@@ -34,6 +34,27 @@ func (r *Repo) Get(ctx context.Context) (*CompanySettings, error) {
 	return &settings, nil
 }
 
+// defaultTimezone is the time zone used when company settings do not name a valid one
+const defaultTimezone = "Indian/Antananarivo"
+
+// Location returns the company time zone, or Indian/Antananarivo when settings are missing or name an unknown zone
+func (r *Repo) Location(ctx context.Context) (*time.Location, error) {
+	name := defaultTimezone
+	settings, err := r.Get(ctx)
+	if err == nil && settings.Timezone != "" {
+		name = settings.Timezone
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		if name == defaultTimezone {
+			return nil, fmt.Errorf("load time zone %s: %w", name, err)
+		}
+		return time.LoadLocation(defaultTimezone)
+	}
+	return loc, nil
+}
+
 // Update updates company settings
 func (r *Repo) Update(ctx context.Context, settings *CompanySettings) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
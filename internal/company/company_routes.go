@@ -12,6 +12,9 @@ func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
 	repo := NewRepo(gormDB)
 	handler := NewHandler(repo)
 
+	// Public company logo, embedded in pages and emails (no authentication)
+	rg.GET("/company/logo/:file_name", handler.GetLogo)
+
 	company := rg.Group("/company")
 	company.Use(middleware.AuthMiddleware())
 	{
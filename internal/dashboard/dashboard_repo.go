@@ -3,7 +3,9 @@ package dashboard
 import (
 	"context"
 	"fmt"
+	"math"
 	"go-server/internal/auth"
+	"go-server/internal/declarations"
 	"sync"
 	"time"
 
@@ -13,12 +15,13 @@ import (
 
 // Repo handles database operations for dashboard
 type Repo struct {
-	db *gorm.DB
+	db           *gorm.DB
+	declarations *declarations.Repo
 }
 
 // NewRepo creates a new dashboard repository
 func NewRepo(database *gorm.DB) *Repo {
-	return &Repo{db: database}
+	return &Repo{db: database, declarations: declarations.NewRepo(database)}
 }
 
 // GetDashboardStats retrieves aggregated dashboard statistics
@@ -494,43 +497,38 @@ func (r *Repo) GetComplianceStatus(ctx context.Context) (map[string]interface{},
 	// Get total employees
 	var totalEmployees int64
 	if err := r.db.WithContext(ctx).Table("employees").
-		Where("status = ?", "active").
+		Where("status = ? AND deleted_at IS NULL", "active").
 		Count(&totalEmployees).Error; err != nil {
 		return nil, fmt.Errorf("count total employees: %w", err)
 	}
 
-	// CNAPS compliance
-	var cnapsCompliant int64
-	if err := r.db.WithContext(ctx).Table("employees").
-		Where("status = ? AND cnaps_number IS NOT NULL AND cnaps_number != ''", "active").
-		Count(&cnapsCompliant).Error; err != nil {
-		return nil, fmt.Errorf("count CNAPS compliant employees: %w", err)
-	}
-
-	cnapsRate := float64(cnapsCompliant) / float64(totalEmployees) * 100
-	result["cnaps"].(map[string]interface{})["compliance_rate"] = cnapsRate
-	result["cnaps"].(map[string]interface{})["compliant_employees"] = int(cnapsCompliant)
-	result["cnaps"].(map[string]interface{})["total_employees"] = int(totalEmployees)
-
-	// OSTIE compliance
-	var ostieCompliant int64
-	if err := r.db.WithContext(ctx).Table("employees").
-		Where("status = ? AND ostie_number IS NOT NULL AND ostie_number != ''", "active").
-		Count(&ostieCompliant).Error; err != nil {
-		return nil, fmt.Errorf("count OSTIE compliant employees: %w", err)
+	// Identifier compliance: active employees missing the identifier each declaration needs.
+	// CNAPS and OSTIE declarations need the employee's registration number, the IRSA state their NIF,
+	// and every declaration their CIN.
+	identifierChecks := []struct {
+		module string
+		column string
+		label  string
+	}{
+		{"cnaps", "cnaps_number", "CNAPS number"},
+		{"ostie", "ostie_number", "OSTIE affiliation number"},
+		{"irsa", "nif", "NIF"},
+		{"cin", "national_id", "CIN"},
+	}
+	for _, check := range identifierChecks {
+		missing, err := r.getEmployeesMissing(ctx, check.column, check.label)
+		if err != nil {
+			return nil, err
+		}
+		compliant := int(totalEmployees) - len(missing)
+		result[check.module] = map[string]interface{}{
+			"compliance_rate":     complianceRate(compliant, int(totalEmployees)),
+			"compliant_employees": compliant,
+			"total_employees":     int(totalEmployees),
+			"issues":              missing,
+		}
 	}
 
-	ostieRate := float64(ostieCompliant) / float64(totalEmployees) * 100
-	result["ostie"].(map[string]interface{})["compliance_rate"] = ostieRate
-	result["ostie"].(map[string]interface{})["compliant_employees"] = int(ostieCompliant)
-	result["ostie"].(map[string]interface{})["total_employees"] = int(totalEmployees)
-
-	// IRSA compliance (assuming all employees are compliant for now)
-	irsaRate := 100.0
-	result["irsa"].(map[string]interface{})["compliance_rate"] = irsaRate
-	result["irsa"].(map[string]interface{})["compliant_employees"] = int(totalEmployees)
-	result["irsa"].(map[string]interface{})["total_employees"] = int(totalEmployees)
-
 	// Attendance compliance (current month)
 	now := time.Now()
 	currentMonth := now.Format("2006-01")
@@ -554,9 +552,73 @@ func (r *Repo) GetComplianceStatus(ctx context.Context) (map[string]interface{},
 	result["leave_balance"].(map[string]interface{})["employees_within_limits"] = int(totalEmployees)
 	result["leave_balance"].(map[string]interface{})["total_employees"] = int(totalEmployees)
 
+	// Declarations past their due date and the late penalties they accrue
+	overdue, err := r.declarations.ListOverdueDeclarations(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("list overdue declarations: %w", err)
+	}
+
+	var totalBalance, totalPenalties float64
+	for _, declaration := range overdue {
+		totalBalance += declaration.Balance
+		if declaration.Penalty != nil {
+			totalPenalties += declaration.Penalty.Total
+		}
+	}
+	result["declarations"] = map[string]interface{}{
+		"overdue_count":   len(overdue),
+		"overdue_balance": math.Round(totalBalance*100) / 100,
+		"total_penalties": math.Round(totalPenalties*100) / 100,
+		"overdue":         overdue,
+	}
+
 	return result, nil
 }
 
+// missingIdentifier is an active employee with no value for an identifier
+type missingIdentifier struct {
+	EmployeeID   uuid.UUID `json:"employee_id"`
+	EmployeeName string    `json:"employee_name"`
+	Department   string    `json:"department,omitempty"`
+	Issue        string    `json:"issue"`
+}
+
+// getEmployeesMissing lists the active employees with no value in an identifier column
+func (r *Repo) getEmployeesMissing(ctx context.Context, column, label string) ([]missingIdentifier, error) {
+	var rows []struct {
+		ID         uuid.UUID
+		FirstName  string
+		LastName   string
+		Department string
+	}
+	if err := r.db.WithContext(ctx).Table("employees").
+		Select("id, first_name, last_name, COALESCE(department, '') AS department").
+		Where("status = ? AND deleted_at IS NULL AND COALESCE("+column+", '') = ''", "active").
+		Order("last_name ASC, first_name ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list employees missing %s: %w", label, err)
+	}
+
+	missing := make([]missingIdentifier, len(rows))
+	for i, row := range rows {
+		missing[i] = missingIdentifier{
+			EmployeeID:   row.ID,
+			EmployeeName: row.FirstName + " " + row.LastName,
+			Department:   row.Department,
+			Issue:        "Missing " + label,
+		}
+	}
+	return missing, nil
+}
+
+// complianceRate returns the percentage of compliant employees, 100 when there are none to check
+func complianceRate(compliant, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(compliant) / float64(total) * 100
+}
+
 // GetWeeklyAttendanceSummary retrieves weekly attendance summary
 func (r *Repo) GetWeeklyAttendanceSummary(ctx context.Context, employeeID *uuid.UUID, startDate time.Time) (map[string]interface{}, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -0,0 +1,306 @@
+package declarations
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-server/internal/employee"
+	"go-server/internal/xlsx"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Export failures the caller can fix: an unknown format, or a declaration or server configuration the export
+// cannot be produced from yet
+var (
+	errExportFormat      = errors.New("unsupported export format")
+	errExportUnavailable = errors.New("cannot export declaration")
+)
+
+// employeeIdentity holds the employee details the authorities' templates ask for beyond the breakdown
+type employeeIdentity struct {
+	NationalID      string
+	NIF             string
+	CNAPSNumber     string
+	OSTIENumber     string
+	Position        string
+	HireDate        time.Time
+	TerminationDate *time.Time
+}
+
+// declarationTable is a declaration laid out in the fixed columns of its authority's template:
+// the DNS for CNAPS, the nominative list for OSTIE and the nominative state for IRSA
+type declarationTable struct {
+	sheet   string
+	headers []string
+	widths  []float64
+	rows    [][]interface{}
+}
+
+// ExportDeclaration renders a declaration as a CSV or Excel file in its template's layout, or as a signed PDF summary
+func (r *Repo) ExportDeclaration(ctx context.Context, id uuid.UUID, format string, exportedBy uuid.UUID) (*DeclarationExport, error) {
+	form, err := r.GenerateDeclarationForm(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	export := &DeclarationExport{Filename: form.DeclarationNumber + "." + format}
+	switch format {
+	case ExportFormatPDF:
+		exporterName, err := r.getUserDisplayName(ctx, exportedBy)
+		if err != nil {
+			return nil, err
+		}
+		summary := &DeclarationSummaryExport{
+			ID:            uuid.New(),
+			DeclarationID: id,
+			ExportedBy:    exportedBy,
+			ExportedAt:    time.Now().Truncate(time.Second),
+		}
+		if err := signSummary(form, summary); err != nil {
+			return nil, fmt.Errorf("%w: %v", errExportUnavailable, err)
+		}
+		export.ContentType = "application/pdf"
+		export.Content, err = renderDeclarationPDF(form, summary, exporterName)
+		if err != nil {
+			return nil, fmt.Errorf("render declaration PDF: %w", err)
+		}
+		if err := r.db.WithContext(ctx).Create(summary).Error; err != nil {
+			return nil, fmt.Errorf("record declaration export: %w", err)
+		}
+		return export, nil
+	case ExportFormatCSV, ExportFormatXLSX:
+	default:
+		return nil, fmt.Errorf("%w %q", errExportFormat, format)
+	}
+	if err := checkEmployerNumber(form); err != nil {
+		return nil, err
+	}
+
+	identities, err := r.getEmployeeIdentities(ctx, form.EmployeeBreakdown)
+	if err != nil {
+		return nil, err
+	}
+	table := buildDeclarationTable(form, identities)
+
+	if format == ExportFormatCSV {
+		export.ContentType = "text/csv; charset=utf-8"
+		export.Content, err = table.csv()
+	} else {
+		export.ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		export.Content, err = table.xlsx()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("render declaration %s: %w", format, err)
+	}
+	return export, nil
+}
+
+// VerifySummaryExport checks a signed PDF summary of a declaration against the declaration as it stands and,
+// when given, the signature printed on the summary against the stored one
+func (r *Repo) VerifySummaryExport(ctx context.Context, declarationID, exportID uuid.UUID, presentedSignature string) (*DeclarationExportVerification, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var summary DeclarationSummaryExport
+	if err := r.db.WithContext(ctx).Where("id = ? AND declaration_id = ?", exportID, declarationID).First(&summary).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("declaration export not found")
+		}
+		return nil, fmt.Errorf("get declaration export: %w", err)
+	}
+
+	form, err := r.GenerateDeclarationForm(ctx, declarationID)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := verifySummary(form, &summary)
+	if presentedSignature != "" && presentedSignature != summary.Signature {
+		reasons = append(reasons, "presented signature does not match")
+	}
+	return &DeclarationExportVerification{
+		ExportID:          summary.ID,
+		DeclarationNumber: form.DeclarationNumber,
+		Valid:             len(reasons) == 0,
+		Reasons:           reasons,
+		ExportedAt:        &summary.ExportedAt,
+		ExportedBy:        &summary.ExportedBy,
+		SignatureKeyID:    summary.SignatureKeyID,
+	}, nil
+}
+
+// checkEmployerNumber refuses a template export of a declaration without the employer number every row starts with
+func checkEmployerNumber(form *DeclarationForm) error {
+	number, label := form.CNAPSNumber, "CNAPS number"
+	switch form.DeclarationType {
+	case DeclarationTypeOSTIE:
+		number, label = form.OSTIENumber, "OSTIE number"
+	case DeclarationTypeIRSA:
+		number, label = form.CompanyNIF, "NIF"
+	}
+	if number == "" {
+		return fmt.Errorf("%w: the declaration has no company %s; set it in company settings and rebuild the draft declaration", errExportUnavailable, label)
+	}
+	return nil
+}
+
+// getEmployeeIdentities loads the identity of every employee in a breakdown, including those who have since left
+func (r *Repo) getEmployeeIdentities(ctx context.Context, breakdown []EmployeeBreakdown) (map[uuid.UUID]employeeIdentity, error) {
+	identities := map[uuid.UUID]employeeIdentity{}
+	if len(breakdown) == 0 {
+		return identities, nil
+	}
+
+	ids := make([]uuid.UUID, len(breakdown))
+	for i, entry := range breakdown {
+		ids[i] = entry.EmployeeID
+	}
+
+	var employees []employee.Employee
+	if err := r.db.WithContext(ctx).Unscoped().
+		Select("id", "national_id", "nif", "cnaps_number", "ostie_number", "position", "hire_date", "termination_date").
+		Where("id IN ?", ids).Find(&employees).Error; err != nil {
+		return nil, fmt.Errorf("get declared employees: %w", err)
+	}
+	for _, emp := range employees {
+		identities[emp.ID] = employeeIdentity{
+			NationalID:      emp.NationalID,
+			NIF:             emp.NIF,
+			CNAPSNumber:     emp.CNAPSNumber,
+			OSTIENumber:     emp.OSTIENumber,
+			Position:        emp.Position,
+			HireDate:        emp.HireDate,
+			TerminationDate: emp.TerminationDate,
+		}
+	}
+	return identities, nil
+}
+
+// buildDeclarationTable lays out one row per declared employee in the columns of the declaration type's template
+func buildDeclarationTable(form *DeclarationForm, identities map[uuid.UUID]employeeIdentity) declarationTable {
+	period := form.DeclarationPeriodStart.Format("01/2006")
+	var table declarationTable
+
+	switch form.DeclarationType {
+	case DeclarationTypeCNAPS:
+		table = declarationTable{
+			sheet: "DNS",
+			headers: []string{"N° employeur", "Période", "N° ordre", "Nom et prénoms", "CIN", "N° CNAPS", "Date d'entrée", "Date de sortie",
+				"Salaire brut", "Salaire plafonné", "Cotisation salariale", "Cotisation patronale", "Total cotisations"},
+			widths: []float64{16, 10, 9, 32, 16, 16, 13, 13, 15, 16, 18, 18, 16},
+		}
+		for i, entry := range form.EmployeeBreakdown {
+			identity := identities[entry.EmployeeID]
+			table.rows = append(table.rows, []interface{}{
+				form.CNAPSNumber, period, i + 1, entry.EmployeeName, identity.NationalID, identity.CNAPSNumber,
+				formatDate(&identity.HireDate), formatDate(departureIn(identity, form.DeclarationPeriodEnd)),
+				entry.GrossSalary, entry.BaseAmount, entry.EmployeeContribution, entry.EmployerContribution,
+				roundAmount(entry.EmployeeContribution + entry.EmployerContribution),
+			})
+		}
+	case DeclarationTypeOSTIE:
+		table = declarationTable{
+			sheet: "Liste nominative",
+			headers: []string{"N° employeur", "Période", "N° ordre", "Nom et prénoms", "CIN", "N° affiliation", "Emploi",
+				"Salaire brut", "Salaire soumis", "Cotisation salariale", "Cotisation patronale", "Total cotisations"},
+			widths: []float64{16, 10, 9, 32, 16, 18, 24, 15, 15, 18, 18, 16},
+		}
+		for i, entry := range form.EmployeeBreakdown {
+			identity := identities[entry.EmployeeID]
+			table.rows = append(table.rows, []interface{}{
+				form.OSTIENumber, period, i + 1, entry.EmployeeName, identity.NationalID, identity.OSTIENumber, identity.Position,
+				entry.GrossSalary, entry.BaseAmount, entry.EmployeeContribution, entry.EmployerContribution,
+				roundAmount(entry.EmployeeContribution + entry.EmployerContribution),
+			})
+		}
+	case DeclarationTypeIRSA:
+		table = declarationTable{
+			sheet: "Etat IRSA",
+			headers: []string{"NIF employeur", "Période", "N° ordre", "Nom et prénoms", "CIN", "NIF", "Emploi",
+				"Salaire brut", "Cotisations sociales salariales", "Revenu imposable", "Personnes à charge",
+				"Réduction pour charges", "IRSA retenu"},
+			widths: []float64{16, 10, 9, 32, 16, 14, 24, 15, 18, 16, 12, 16, 14},
+		}
+		for i, entry := range form.EmployeeBreakdown {
+			identity := identities[entry.EmployeeID]
+			dependents, reduction := 0, 0.0
+			if entry.IRSABreakdown != nil {
+				dependents = entry.IRSABreakdown.Dependents
+				reduction = entry.IRSABreakdown.DependentReduction
+			}
+			table.rows = append(table.rows, []interface{}{
+				form.CompanyNIF, period, i + 1, entry.EmployeeName, identity.NationalID, identity.NIF, identity.Position,
+				entry.GrossSalary, roundAmount(entry.GrossSalary - entry.BaseAmount), entry.BaseAmount,
+				dependents, reduction, entry.EmployeeContribution,
+			})
+		}
+	}
+	return table
+}
+
+// departureIn returns an employee's termination date if they left by the end of the declared month
+func departureIn(identity employeeIdentity, periodEnd time.Time) *time.Time {
+	if identity.TerminationDate == nil || identity.TerminationDate.After(periodEnd) {
+		return nil
+	}
+	return identity.TerminationDate
+}
+
+// csv writes the table as semicolon-separated UTF-8 with a header line, amounts with two decimals and dates as DD/MM/YYYY
+func (t declarationTable) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	if err := w.Write(t.headers); err != nil {
+		return nil, err
+	}
+	for _, row := range t.rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			switch v := value.(type) {
+			case float64:
+				record[i] = strconv.FormatFloat(v, 'f', 2, 64)
+			case int:
+				record[i] = strconv.Itoa(v)
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// xlsx writes the table as a single-sheet workbook with the same columns as the CSV
+func (t declarationTable) xlsx() ([]byte, error) {
+	workbook := xlsx.New()
+	sheet := workbook.AddSheet(t.sheet)
+	sheet.SetColumnWidths(t.widths...)
+	sheet.AddHeaderRow(t.headers...)
+	for _, row := range t.rows {
+		sheet.AddRow(row...)
+	}
+	return workbook.Bytes()
+}
+
+// formatDate formats an optional date as DD/MM/YYYY, or "" when unset
+func formatDate(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("02/01/2006")
+}
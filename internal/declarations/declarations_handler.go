@@ -1,7 +1,7 @@
 package declarations
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
@@ -47,12 +47,8 @@ func (h *Handler) CreateDeclaration(c *gin.Context) {
 		DeclarationType:        input.DeclarationType,
 		DeclarationPeriodStart: input.DeclarationPeriodStart,
 		DeclarationPeriodEnd:   input.DeclarationPeriodEnd,
-		CompanyName:            input.CompanyName,
-		CompanyAddress:         input.CompanyAddress,
-		CompanyNIF:             input.CompanyNIF,
 		AccountantID:           accountantID,
-		Status:                 "draft",
-		DeclarationData:        "[]", // Empty JSON array for employee breakdown
+		Status:                 DeclarationStatusDraft,
 	}
 
 	if err := h.repo.CreateDeclaration(c.Request.Context(), declaration); err != nil {
@@ -63,8 +59,15 @@ func (h *Handler) CreateDeclaration(c *gin.Context) {
 	c.JSON(http.StatusCreated, declaration)
 }
 
-// GetDeclarationByID retrieves a declaration by ID
+// GetDeclarationByID retrieves a declaration by ID (Accountant only)
 func (h *Handler) GetDeclarationByID(c *gin.Context) {
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can view declarations"})
+		return
+	}
+
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -81,8 +84,15 @@ func (h *Handler) GetDeclarationByID(c *gin.Context) {
 	c.JSON(http.StatusOK, declaration)
 }
 
-// GetDeclarationByNumber retrieves a declaration by declaration number
+// GetDeclarationByNumber retrieves a declaration by declaration number (Accountant only)
 func (h *Handler) GetDeclarationByNumber(c *gin.Context) {
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can view declarations"})
+		return
+	}
+
 	declarationNumber := c.Param("declaration_number")
 
 	declaration, err := h.repo.GetDeclarationByNumber(c.Request.Context(), declarationNumber)
@@ -94,55 +104,22 @@ func (h *Handler) GetDeclarationByNumber(c *gin.Context) {
 	c.JSON(http.StatusOK, declaration)
 }
 
-// UpdateDeclaration handles update of a declaration (Accountant only)
+// UpdateDeclaration moves a declaration to the submitted or cancelled status (Accountant only)
 func (h *Handler) UpdateDeclaration(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid declaration ID"})
-		return
-	}
-
-	// Verify Accountant role
-	userRole, _ := middleware.GetUserRole(c)
-	if userRole != "accountant" && userRole != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can update declarations"})
-		return
-	}
-
 	var input UpdateDeclarationRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	declaration, err := h.repo.GetDeclarationByID(c.Request.Context(), id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Declaration not found"})
-		return
-	}
-
-	// Update fields if provided
-	if input.Status != nil {
-		declaration.Status = *input.Status
-		now := time.Now()
-		if *input.Status == "submitted" && declaration.SubmittedAt == nil {
-			declaration.SubmittedAt = &now
-		}
-		if *input.Status == "paid" && declaration.PaidAt == nil {
-			declaration.PaidAt = &now
-		}
-	}
-
-	if err := h.repo.UpdateDeclaration(c.Request.Context(), declaration); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update declaration"})
+	if input.Status == DeclarationStatusCancelled && input.Reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required to cancel a declaration"})
 		return
 	}
 
-	c.JSON(http.StatusOK, declaration)
+	h.transitionDeclaration(c, input.Status, input.SubmissionReference, input.Reason)
 }
 
-// DeleteDeclaration handles deletion of a declaration (Accountant only)
+// DeleteDeclaration handles deletion of a draft declaration (Accountant only)
 func (h *Handler) DeleteDeclaration(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
@@ -159,15 +136,26 @@ func (h *Handler) DeleteDeclaration(c *gin.Context) {
 	}
 
 	if err := h.repo.DeleteDeclaration(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err.Error() == "declaration not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Declaration not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Declaration deleted successfully"})
 }
 
-// ListDeclarations retrieves declarations with filtering
+// ListDeclarations retrieves declarations with filtering (Accountant only)
 func (h *Handler) ListDeclarations(c *gin.Context) {
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can view declarations"})
+		return
+	}
+
 	var query DeclarationListQuery
 	if err := c.ShouldBindQuery(&query); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -188,8 +176,15 @@ func (h *Handler) ListDeclarations(c *gin.Context) {
 	})
 }
 
-// GenerateDeclarationForm generates a declaration form for CNAPS, OSTIE, or IRSA
+// GenerateDeclarationForm generates a declaration form for CNAPS, OSTIE, or IRSA (Accountant only)
 func (h *Handler) GenerateDeclarationForm(c *gin.Context) {
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can view declarations"})
+		return
+	}
+
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -206,300 +201,120 @@ func (h *Handler) GenerateDeclarationForm(c *gin.Context) {
 	c.JSON(http.StatusOK, form)
 }
 
-// CreateIRSATaxBracket handles creation of a new IRSA tax bracket (Admin only)
-func (h *Handler) CreateIRSATaxBracket(c *gin.Context) {
-	var input CreateIRSATaxBracketRequest
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Get user info from context
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	// Verify Admin role
-	userRole, _ := middleware.GetUserRole(c)
-	if userRole != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only Admin can create IRSA tax brackets"})
-		return
-	}
-
-	bracket := &IRSATaxBracket{
-		MinIncome:     input.MinIncome,
-		MaxIncome:     input.MaxIncome,
-		TaxRate:       input.TaxRate,
-		MinTax:        input.MinTax,
-		EffectiveDate: input.EffectiveDate,
-		CreatedBy:     userID,
-		IsActive:      true,
-	}
-
-	if err := h.repo.CreateIRSATaxBracket(c.Request.Context(), bracket); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusCreated, bracket)
-}
-
-// GetIRSATaxBracketByID retrieves an IRSA tax bracket by ID
-func (h *Handler) GetIRSATaxBracketByID(c *gin.Context) {
+// ExportDeclaration downloads a declaration as a CSV or Excel file in its authority's template, or as a signed PDF summary (Accountant only)
+func (h *Handler) ExportDeclaration(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid IRSA tax bracket ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid declaration ID"})
 		return
 	}
 
-	bracket, err := h.repo.GetIRSATaxBracketByID(c.Request.Context(), id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "IRSA tax bracket not found"})
+	var query DeclarationExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format parameter must be csv, xlsx or pdf"})
 		return
 	}
 
-	c.JSON(http.StatusOK, bracket)
-}
-
-// UpdateIRSATaxBracket handles update of an IRSA tax bracket (Admin only)
-func (h *Handler) UpdateIRSATaxBracket(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid IRSA tax bracket ID"})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
 	}
 
-	// Verify Admin role
+	// Verify Accountant role
 	userRole, _ := middleware.GetUserRole(c)
-	if userRole != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only Admin can update IRSA tax brackets"})
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can export declarations"})
 		return
 	}
 
-	var input UpdateIRSATaxBracketRequest
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if _, err := h.repo.GetDeclarationByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Declaration not found"})
 		return
 	}
 
-	bracket, err := h.repo.GetIRSATaxBracketByID(c.Request.Context(), id)
+	export, err := h.repo.ExportDeclaration(c.Request.Context(), id, query.Format, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "IRSA tax bracket not found"})
-		return
-	}
-
-	// Update fields if provided
-	if input.MinIncome != nil {
-		bracket.MinIncome = *input.MinIncome
-	}
-	if input.MaxIncome != nil {
-		bracket.MaxIncome = input.MaxIncome
-	}
-	if input.TaxRate != nil {
-		bracket.TaxRate = *input.TaxRate
-	}
-	if input.MinTax != nil {
-		bracket.MinTax = *input.MinTax
-	}
-	if input.IsActive != nil {
-		bracket.IsActive = *input.IsActive
-	}
-	if input.EffectiveDate != nil {
-		bracket.EffectiveDate = *input.EffectiveDate
-	}
-
-	if err := h.repo.UpdateIRSATaxBracket(c.Request.Context(), bracket); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update IRSA tax bracket"})
+		switch {
+		case errors.Is(err, errExportFormat):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, errExportUnavailable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err.Error() == "declaration not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Declaration not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export declaration"})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, bracket)
+	c.Header("Content-Disposition", "attachment; filename="+export.Filename)
+	c.Data(http.StatusOK, export.ContentType, export.Content)
 }
 
-// DeleteIRSATaxBracket handles deletion of an IRSA tax bracket (Admin only)
-func (h *Handler) DeleteIRSATaxBracket(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+// VerifyDeclarationExport checks whether a signed PDF summary still matches its declaration (Accountant only)
+func (h *Handler) VerifyDeclarationExport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid IRSA tax bracket ID"})
-		return
-	}
-
-	// Verify Admin role
-	userRole, _ := middleware.GetUserRole(c)
-	if userRole != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only Admin can delete IRSA tax brackets"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid declaration ID"})
 		return
 	}
-
-	if err := h.repo.DeleteIRSATaxBracket(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	exportID, err := uuid.Parse(c.Param("export_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export ID"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "IRSA tax bracket deleted successfully"})
-}
-
-// ListIRSATaxBrackets retrieves IRSA tax brackets with filtering
-func (h *Handler) ListIRSATaxBrackets(c *gin.Context) {
-	var query IRSATaxBracketListQuery
+	var query DeclarationExportVerificationQuery
 	if err := c.ShouldBindQuery(&query); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	brackets, total, err := h.repo.ListIRSATaxBrackets(c.Request.Context(), query)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list IRSA tax brackets"})
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can verify declaration exports"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"brackets": brackets,
-		"total":    total,
-		"limit":    query.Limit,
-		"offset":   query.Offset,
-	})
-}
-
-// GenerateCNAPSDeclaration generates a CNAPS declaration form for a specific month
-func (h *Handler) GenerateCNAPSDeclaration(c *gin.Context) {
-	// Parse month from query params (format: YYYY-MM)
-	monthStr := c.Query("month")
-	if monthStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "month parameter is required (format: YYYY-MM)"})
+	if _, err := h.repo.GetDeclarationByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Declaration not found"})
 		return
 	}
 
-	monthTime, err := time.Parse("2006-01", monthStr)
+	verification, err := h.repo.VerifySummaryExport(c.Request.Context(), id, exportID, query.Signature)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month format (use YYYY-MM)"})
-		return
-	}
-
-	// Calculate period start and end
-	periodStart := time.Date(monthTime.Year(), monthTime.Month(), 1, 0, 0, 0, 0, monthTime.Location())
-	periodEnd := time.Date(monthTime.Year(), monthTime.Month()+1, 0, 0, 0, 0, 0, monthTime.Location())
-
-	// Get user info
-	accountantID, _ := middleware.GetUserID(c)
-
-	// Check if declaration already exists
-	var existing MonthlyDeclaration
-	err = h.repo.db.WithContext(c.Request.Context()).
-		Where("declaration_type = ? AND declaration_period_start = ? AND declaration_period_end = ?",
-			"cnaps", periodStart, periodEnd).First(&existing).Error
-
-	if err == nil {
-		// Declaration exists, return it
-		form, err := h.repo.GenerateDeclarationForm(c.Request.Context(), existing.ID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate declaration form"})
+		if err.Error() == "declaration export not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Declaration export not found"})
 			return
 		}
-		c.JSON(http.StatusOK, form)
-		return
-	}
-
-	// Create new declaration
-	declaration := &MonthlyDeclaration{
-		DeclarationType:        "cnaps",
-		DeclarationPeriodStart: periodStart,
-		DeclarationPeriodEnd:   periodEnd,
-		CompanyName:            "Company Name",    // TODO: Get from company settings
-		CompanyAddress:         "Company Address", // TODO: Get from company settings
-		CompanyNIF:             "NIF",             // TODO: Get from company settings
-		AccountantID:           accountantID,
-		Status:                 "draft",
-		DeclarationData:        "[]",
-	}
-
-	if err := h.repo.CreateDeclaration(c.Request.Context(), declaration); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create CNAPS declaration"})
-		return
-	}
-
-	form, err := h.repo.GenerateDeclarationForm(c.Request.Context(), declaration.ID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate declaration form"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify declaration export"})
 		return
 	}
+	c.JSON(http.StatusOK, verification)
+}
 
-	c.JSON(http.StatusOK, form)
+// GenerateCNAPSDeclaration generates a CNAPS declaration form for a specific month
+func (h *Handler) GenerateCNAPSDeclaration(c *gin.Context) {
+	h.generateMonthlyDeclaration(c, DeclarationTypeCNAPS)
 }
 
 // GenerateOSTIEDeclaration generates an OSTIE declaration form for a specific month
 func (h *Handler) GenerateOSTIEDeclaration(c *gin.Context) {
-	// Parse month from query params (format: YYYY-MM)
-	monthStr := c.Query("month")
-	if monthStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "month parameter is required (format: YYYY-MM)"})
-		return
-	}
-
-	monthTime, err := time.Parse("2006-01", monthStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month format (use YYYY-MM)"})
-		return
-	}
-
-	// Calculate period start and end
-	periodStart := time.Date(monthTime.Year(), monthTime.Month(), 1, 0, 0, 0, 0, monthTime.Location())
-	periodEnd := time.Date(monthTime.Year(), monthTime.Month()+1, 0, 0, 0, 0, 0, monthTime.Location())
-
-	// Get user info
-	accountantID, _ := middleware.GetUserID(c)
-
-	// Check if declaration already exists
-	var existing MonthlyDeclaration
-	err = h.repo.db.WithContext(c.Request.Context()).
-		Where("declaration_type = ? AND declaration_period_start = ? AND declaration_period_end = ?",
-			"ostie", periodStart, periodEnd).First(&existing).Error
-
-	if err == nil {
-		// Declaration exists, return it
-		form, err := h.repo.GenerateDeclarationForm(c.Request.Context(), existing.ID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate declaration form"})
-			return
-		}
-		c.JSON(http.StatusOK, form)
-		return
-	}
-
-	// Create new declaration
-	declaration := &MonthlyDeclaration{
-		DeclarationType:        "ostie",
-		DeclarationPeriodStart: periodStart,
-		DeclarationPeriodEnd:   periodEnd,
-		CompanyName:            "Company Name",    // TODO: Get from company settings
-		CompanyAddress:         "Company Address", // TODO: Get from company settings
-		CompanyNIF:             "NIF",             // TODO: Get from company settings
-		AccountantID:           accountantID,
-		Status:                 "draft",
-		DeclarationData:        "[]",
-	}
-
-	if err := h.repo.CreateDeclaration(c.Request.Context(), declaration); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create OSTIE declaration"})
-		return
-	}
-
-	form, err := h.repo.GenerateDeclarationForm(c.Request.Context(), declaration.ID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate declaration form"})
-		return
-	}
-
-	c.JSON(http.StatusOK, form)
+	h.generateMonthlyDeclaration(c, DeclarationTypeOSTIE)
 }
 
 // GenerateIRSADeclaration generates an IRSA declaration form for a specific month
 func (h *Handler) GenerateIRSADeclaration(c *gin.Context) {
+	h.generateMonthlyDeclaration(c, DeclarationTypeIRSA)
+}
+
+// generateMonthlyDeclaration returns the form of the declaration of a type for the month in the query,
+// creating the declaration from approved payroll when the month has not been declared yet
+func (h *Handler) generateMonthlyDeclaration(c *gin.Context, declarationType string) {
 	// Parse month from query params (format: YYYY-MM)
 	monthStr := c.Query("month")
 	if monthStr == "" {
@@ -507,51 +322,29 @@ func (h *Handler) GenerateIRSADeclaration(c *gin.Context) {
 		return
 	}
 
-	monthTime, err := time.Parse("2006-01", monthStr)
+	periodStart, err := time.Parse("2006-01", monthStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month format (use YYYY-MM)"})
 		return
 	}
 
-	// Calculate period start and end
-	periodStart := time.Date(monthTime.Year(), monthTime.Month(), 1, 0, 0, 0, 0, monthTime.Location())
-	periodEnd := time.Date(monthTime.Year(), monthTime.Month()+1, 0, 0, 0, 0, 0, monthTime.Location())
-
-	// Get user info
-	accountantID, _ := middleware.GetUserID(c)
-
-	// Check if declaration already exists
-	var existing MonthlyDeclaration
-	err = h.repo.db.WithContext(c.Request.Context()).
-		Where("declaration_type = ? AND declaration_period_start = ? AND declaration_period_end = ?",
-			"irsa", periodStart, periodEnd).First(&existing).Error
-
-	if err == nil {
-		// Declaration exists, return it
-		form, err := h.repo.GenerateDeclarationForm(c.Request.Context(), existing.ID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate declaration form"})
-			return
-		}
-		c.JSON(http.StatusOK, form)
+	// Get user info from context
+	accountantID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
 	}
 
-	// Create new declaration
-	declaration := &MonthlyDeclaration{
-		DeclarationType:        "irsa",
-		DeclarationPeriodStart: periodStart,
-		DeclarationPeriodEnd:   periodEnd,
-		CompanyName:            "Company Name",    // TODO: Get from company settings
-		CompanyAddress:         "Company Address", // TODO: Get from company settings
-		CompanyNIF:             "NIF",             // TODO: Get from company settings
-		AccountantID:           accountantID,
-		Status:                 "draft",
-		DeclarationData:        "[]",
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can generate declarations"})
+		return
 	}
 
-	if err := h.repo.CreateDeclaration(c.Request.Context(), declaration); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create IRSA declaration"})
+	declaration, err := h.repo.GenerateMonthlyDeclaration(c.Request.Context(), declarationType, periodStart, accountantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -564,7 +357,7 @@ func (h *Handler) GenerateIRSADeclaration(c *gin.Context) {
 	c.JSON(http.StatusOK, form)
 }
 
-// PopulateDeclarationData populates declaration data from approved payrolls
+// PopulateDeclarationData rebuilds a draft declaration from the approved payroll of its month (Accountant only)
 func (h *Handler) PopulateDeclarationData(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
@@ -580,28 +373,14 @@ func (h *Handler) PopulateDeclarationData(c *gin.Context) {
 		return
 	}
 
-	declaration, err := h.repo.GetDeclarationByID(c.Request.Context(), id)
-	if err != nil {
+	if _, err := h.repo.GetDeclarationByID(c.Request.Context(), id); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Declaration not found"})
 		return
 	}
 
-	// TODO: Query approved payrolls for the period and populate employee breakdown
-	// This is a placeholder implementation
-	employeeBreakdown := []EmployeeBreakdown{}
-
-	// Marshal to JSON
-	declarationData, err := json.Marshal(employeeBreakdown)
+	declaration, err := h.repo.PopulateDeclaration(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to marshal declaration data"})
-		return
-	}
-
-	declaration.DeclarationData = string(declarationData)
-	declaration.TotalEmployees = len(employeeBreakdown)
-
-	if err := h.repo.UpdateDeclaration(c.Request.Context(), declaration); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update declaration"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -3,6 +3,8 @@ package declarations
 import (
 	"time"
 
+	"go-server/internal/taxrules"
+
 	"github.com/google/uuid"
 )
 
@@ -16,6 +18,9 @@ type MonthlyDeclaration struct {
 	CompanyName                string     `gorm:"type:varchar(255);not null" json:"company_name"`
 	CompanyAddress             string     `gorm:"type:text" json:"company_address"`
 	CompanyNIF                 string     `gorm:"type:varchar(50)" json:"company_nif"`
+	CompanySTAT                string     `gorm:"type:varchar(50)" json:"company_stat"`
+	CNAPSNumber                string     `gorm:"type:varchar(50)" json:"cnaps_number"`
+	OSTIENumber                string     `gorm:"type:varchar(50)" json:"ostie_number"`
 	TotalEmployees             int        `gorm:"not null" json:"total_employees"`
 	TotalGrossSalary           float64    `gorm:"type:numeric(15,2);not null" json:"total_gross_salary"`
 	TotalEmployeeContributions float64    `gorm:"type:numeric(15,2);not null" json:"total_employee_contributions"`
@@ -23,40 +28,142 @@ type MonthlyDeclaration struct {
 	TotalAmountDue             float64    `gorm:"type:numeric(15,2);not null" json:"total_amount_due"`
 	DeclarationData            string     `gorm:"type:jsonb;not null" json:"declaration_data"`
 	Status                     string     `gorm:"type:varchar(20);default:'draft'" json:"status"`
+	DueDate                    time.Time  `gorm:"type:date;not null" json:"due_date"`
+	AmountPaid                 float64    `gorm:"type:numeric(15,2);not null;default:0" json:"amount_paid"`
 	AccountantID               uuid.UUID  `gorm:"type:uuid;not null" json:"accountant_id"`
 	SubmittedAt                *time.Time `json:"submitted_at"`
+	SubmittedBy                *uuid.UUID `gorm:"type:uuid" json:"submitted_by,omitempty"`
+	SubmissionReference        string     `gorm:"type:varchar(100)" json:"submission_reference,omitempty"`
 	PaidAt                     *time.Time `json:"paid_at"`
+	CancelledAt                *time.Time `json:"cancelled_at,omitempty"`
+	CancelledBy                *uuid.UUID `gorm:"type:uuid" json:"cancelled_by,omitempty"`
+	CancellationReason         string     `gorm:"type:text" json:"cancellation_reason,omitempty"`
 	CreatedAt                  time.Time  `gorm:"default:now()" json:"created_at"`
 	UpdatedAt                  time.Time  `gorm:"default:now()" json:"updated_at"`
 }
 
-// IRSATaxBracket represents an IRSA tax bracket configuration
-type IRSATaxBracket struct {
+// Declaration types
+const (
+	DeclarationTypeCNAPS = "cnaps"
+	DeclarationTypeOSTIE = "ostie"
+	DeclarationTypeIRSA  = "irsa"
+)
+
+// Declaration statuses
+const (
+	DeclarationStatusDraft     = "draft"
+	DeclarationStatusSubmitted = "submitted"
+	DeclarationStatusPaid      = "paid"
+	DeclarationStatusCancelled = "cancelled"
+)
+
+// declarationTransitions lists the statuses a declaration may move to from each status.
+// Paid and cancelled declarations are final.
+var declarationTransitions = map[string][]string{
+	DeclarationStatusDraft:     {DeclarationStatusSubmitted, DeclarationStatusCancelled},
+	DeclarationStatusSubmitted: {DeclarationStatusPaid, DeclarationStatusCancelled},
+}
+
+// canTransition reports whether a declaration may move from one status to another
+func canTransition(from, to string) bool {
+	for _, allowed := range declarationTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// employeeIdentifier is an employee column a declaration needs for every declared employee
+type employeeIdentifier struct {
+	column string
+	label  string
+}
+
+// requiredIdentifiers lists the employee identifiers each authority needs before a declaration can be filed:
+// every template identifies employees by CIN, and each adds its own registration or tax number
+var requiredIdentifiers = map[string][]employeeIdentifier{
+	DeclarationTypeCNAPS: {{"national_id", "CIN"}, {"cnaps_number", "CNAPS number"}},
+	DeclarationTypeOSTIE: {{"national_id", "CIN"}, {"ostie_number", "OSTIE affiliation number"}},
+	DeclarationTypeIRSA:  {{"national_id", "CIN"}, {"nif", "NIF"}},
+}
+
+// DeclarationPayment records a payment made to the authority against a submitted declaration
+type DeclarationPayment struct {
 	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	MinIncome     float64   `gorm:"type:numeric(15,2);not null" json:"min_income"`
-	MaxIncome     *float64  `gorm:"type:numeric(15,2)" json:"max_income"`
-	TaxRate       float64   `gorm:"type:numeric(5,2);not null" json:"tax_rate"`
-	MinTax        float64   `gorm:"type:numeric(15,2);default:0" json:"min_tax"`
-	IsActive      bool      `gorm:"default:true" json:"is_active"`
-	EffectiveDate time.Time `gorm:"type:date;not null" json:"effective_date"`
-	CreatedBy     uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	DeclarationID uuid.UUID `gorm:"type:uuid;not null;index" json:"declaration_id"`
+	Amount        float64   `gorm:"type:numeric(15,2);not null" json:"amount"`
+	PaidOn        time.Time `gorm:"type:date;not null" json:"paid_on"`
+	Reference     string    `gorm:"type:varchar(100);not null" json:"reference"`
+	PaymentMethod string    `gorm:"type:varchar(30)" json:"payment_method,omitempty"`
+	Notes         string    `gorm:"type:text" json:"notes,omitempty"`
+	RecordedBy    uuid.UUID `gorm:"type:uuid;not null" json:"recorded_by"`
 	CreatedAt     time.Time `gorm:"default:now()" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"default:now()" json:"updated_at"`
 }
 
-// CreateDeclarationRequest represents request to create a monthly declaration
+// DeclarationPenalty is what a declaration accrues when its amount due is not fully paid by the due date:
+// a one-off surcharge plus interest for every started month of delay, both on the amount unpaid at the due date
+type DeclarationPenalty struct {
+	LateAmount          float64   `json:"late_amount"`
+	LateUntil           time.Time `json:"late_until"`
+	DaysLate            int       `json:"days_late"`
+	MonthsLate          int       `json:"months_late"`
+	SurchargeRate       float64   `json:"surcharge_rate"`
+	MonthlyInterestRate float64   `json:"monthly_interest_rate"`
+	Surcharge           float64   `json:"surcharge"`
+	Interest            float64   `json:"interest"`
+	Total               float64   `json:"total"`
+}
+
+// DeclarationPaymentStatus summarizes what has been paid against a declaration and the penalty it accrued
+type DeclarationPaymentStatus struct {
+	DeclarationID     uuid.UUID            `json:"declaration_id"`
+	DeclarationNumber string               `json:"declaration_number"`
+	DeclarationType   string               `json:"declaration_type"`
+	PeriodStart       time.Time            `json:"declaration_period_start"`
+	Status            string               `json:"status"`
+	DueDate           time.Time            `json:"due_date"`
+	AmountDue         float64              `json:"amount_due"`
+	AmountPaid        float64              `json:"amount_paid"`
+	Balance           float64              `json:"balance"`
+	Overdue           bool                 `json:"overdue"`
+	Penalty           *DeclarationPenalty  `json:"penalty,omitempty"`
+	Payments          []DeclarationPayment `json:"payments"`
+}
+
+// CreateDeclarationRequest represents request to create a monthly declaration.
+// The period must be a whole calendar month; company identity and amounts come from company settings and approved payroll.
 type CreateDeclarationRequest struct {
 	DeclarationType        string    `json:"declaration_type" binding:"required,oneof=cnaps ostie irsa"`
 	DeclarationPeriodStart time.Time `json:"declaration_period_start" binding:"required"`
 	DeclarationPeriodEnd   time.Time `json:"declaration_period_end" binding:"required"`
-	CompanyName            string    `json:"company_name" binding:"required"`
-	CompanyAddress         string    `json:"company_address"`
-	CompanyNIF             string    `json:"company_nif"`
 }
 
-// UpdateDeclarationRequest represents request to update a monthly declaration
+// UpdateDeclarationRequest represents request to move a monthly declaration to another status.
+// Declarations become paid by recording payments, not through this request.
 type UpdateDeclarationRequest struct {
-	Status *string `json:"status" binding:"omitempty,oneof=draft submitted paid cancelled"`
+	Status              string `json:"status" binding:"required,oneof=submitted cancelled"`
+	SubmissionReference string `json:"submission_reference" binding:"omitempty,max=100"`
+	Reason              string `json:"reason"`
+}
+
+// SubmitDeclarationRequest represents request to record that a declaration was filed with the authority
+type SubmitDeclarationRequest struct {
+	SubmissionReference string `json:"submission_reference" binding:"omitempty,max=100"`
+}
+
+// CancelDeclarationRequest represents request to cancel a declaration
+type CancelDeclarationRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// RecordDeclarationPaymentRequest represents request to record a payment against a submitted declaration
+type RecordDeclarationPaymentRequest struct {
+	Amount        float64    `json:"amount" binding:"required,gt=0"`
+	Reference     string     `json:"reference" binding:"required,max=100"`
+	PaidOn        *time.Time `json:"paid_on"`
+	PaymentMethod string     `json:"payment_method" binding:"omitempty,oneof=bank_transfer cheque cash mobile_money"`
+	Notes         string     `json:"notes"`
 }
 
 // DeclarationListQuery represents query parameters for listing declarations
@@ -70,31 +177,50 @@ type DeclarationListQuery struct {
 	Offset                 int        `form:"offset" binding:"omitempty,min=0"`
 }
 
-// CreateIRSATaxBracketRequest represents request to create an IRSA tax bracket
-type CreateIRSATaxBracketRequest struct {
-	MinIncome     float64   `json:"min_income" binding:"required,min=0"`
-	MaxIncome     *float64  `json:"max_income" binding:"omitempty,min=0"`
-	TaxRate       float64   `json:"tax_rate" binding:"required,min=0,max=100"`
-	MinTax        float64   `json:"min_tax" binding:"omitempty,min=0"`
-	EffectiveDate time.Time `json:"effective_date" binding:"required"`
+// Export formats of a declaration
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatPDF  = "pdf"
+)
+
+// DeclarationExportQuery selects the file format of a declaration export
+type DeclarationExportQuery struct {
+	Format string `form:"format" binding:"required,oneof=csv xlsx pdf"`
+}
+
+// DeclarationExport is a declaration rendered as a file for the authorities
+type DeclarationExport struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// DeclarationSummaryExport records a signed PDF summary of a declaration, so the summary can be verified later
+type DeclarationSummaryExport struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	DeclarationID  uuid.UUID `gorm:"type:uuid;not null;index" json:"declaration_id"`
+	PayloadHash    string    `gorm:"type:varchar(64);not null" json:"payload_hash"`
+	Signature      string    `gorm:"type:text;not null" json:"signature"`
+	SignatureKeyID string    `gorm:"type:varchar(32);not null" json:"signature_key_id"`
+	ExportedBy     uuid.UUID `gorm:"type:uuid;not null" json:"exported_by"`
+	ExportedAt     time.Time `gorm:"not null" json:"exported_at"`
 }
 
-// UpdateIRSATaxBracketRequest represents request to update an IRSA tax bracket
-type UpdateIRSATaxBracketRequest struct {
-	MinIncome     *float64   `json:"min_income" binding:"omitempty,min=0"`
-	MaxIncome     *float64   `json:"max_income" binding:"omitempty,min=0"`
-	TaxRate       *float64   `json:"tax_rate" binding:"omitempty,min=0,max=100"`
-	MinTax        *float64   `json:"min_tax" binding:"omitempty,min=0"`
-	IsActive      *bool      `json:"is_active"`
-	EffectiveDate *time.Time `json:"effective_date"`
+// DeclarationExportVerificationQuery holds the signature printed on a summary, compared with the stored one when given
+type DeclarationExportVerificationQuery struct {
+	Signature string `form:"signature"`
 }
 
-// IRSATaxBracketListQuery represents query parameters for listing IRSA tax brackets
-type IRSATaxBracketListQuery struct {
-	IsActive      *bool      `form:"is_active"`
-	EffectiveDate *time.Time `form:"effective_date"`
-	Limit         int        `form:"limit" binding:"omitempty,min=1,max=100"`
-	Offset        int        `form:"offset" binding:"omitempty,min=0"`
+// DeclarationExportVerification is the result of checking a signed summary against its declaration
+type DeclarationExportVerification struct {
+	ExportID          uuid.UUID  `json:"export_id"`
+	DeclarationNumber string     `json:"declaration_number,omitempty"`
+	Valid             bool       `json:"valid"`
+	Reasons           []string   `json:"reasons,omitempty"`
+	ExportedAt        *time.Time `json:"exported_at,omitempty"`
+	ExportedBy        *uuid.UUID `json:"exported_by,omitempty"`
+	SignatureKeyID    string     `json:"signature_key_id,omitempty"`
 }
 
 // DeclarationForm represents a declaration form for CNAPS, OSTIE, or IRSA
@@ -106,13 +232,20 @@ type DeclarationForm struct {
 	CompanyName                string              `json:"company_name"`
 	CompanyAddress             string              `json:"company_address"`
 	CompanyNIF                 string              `json:"company_nif"`
+	CompanySTAT                string              `json:"company_stat"`
+	CNAPSNumber                string              `json:"cnaps_number"`
+	OSTIENumber                string              `json:"ostie_number"`
 	TotalEmployees             int                 `json:"total_employees"`
 	TotalGrossSalary           float64             `json:"total_gross_salary"`
 	TotalEmployeeContributions float64             `json:"total_employee_contributions"`
 	TotalEmployerContributions float64             `json:"total_employer_contributions"`
 	TotalAmountDue             float64             `json:"total_amount_due"`
 	EmployeeBreakdown          []EmployeeBreakdown `json:"employee_breakdown"`
+	IRSABrackets               []IRSABracketTotal  `json:"irsa_brackets,omitempty"`
+	IRSADependentReductions    float64             `json:"irsa_dependent_reductions,omitempty"`
 	Status                     string              `json:"status"`
+	DueDate                    time.Time           `json:"due_date"`
+	AmountPaid                 float64             `json:"amount_paid"`
 	AccountantName             string              `json:"accountant_name"`
 	CreatedAt                  time.Time           `json:"created_at"`
 	SubmittedAt                *time.Time          `json:"submitted_at"`
@@ -127,6 +260,19 @@ type EmployeeBreakdown struct {
 	BaseAmount           float64   `json:"base_amount"`
 	EmployeeContribution float64   `json:"employee_contribution"`
 	EmployerContribution float64   `json:"employer_contribution"`
+	// FichePaieNumbers are the approved payslips the amounts come from
+	FichePaieNumbers []string `json:"fiche_paie_numbers,omitempty"`
+	// IRSABreakdown is the employee's progressive IRSA computation, on IRSA declarations only
+	IRSABreakdown *taxrules.IRSABreakdown `json:"irsa_breakdown,omitempty"`
+}
+
+// IRSABracketTotal sums the income taxed and the tax due in one IRSA bracket across a declaration's employees
+type IRSABracketTotal struct {
+	BracketName   string  `json:"bracket_name"`
+	Rate          float64 `json:"rate"`
+	Employees     int     `json:"employees"`
+	TaxableAmount float64 `json:"taxable_amount"`
+	Tax           float64 `json:"tax"`
 }
 
 // TableName specifies the table name for MonthlyDeclaration model
@@ -134,7 +280,12 @@ func (MonthlyDeclaration) TableName() string {
 	return "monthly_declarations"
 }
 
-// TableName specifies the table name for IRSATaxBracket model
-func (IRSATaxBracket) TableName() string {
-	return "irsa_tax_brackets"
+// TableName specifies the table name for DeclarationPayment model
+func (DeclarationPayment) TableName() string {
+	return "declaration_payments"
+}
+
+// TableName specifies the table name for DeclarationSummaryExport model
+func (DeclarationSummaryExport) TableName() string {
+	return "declaration_exports"
 }
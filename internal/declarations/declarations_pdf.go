@@ -0,0 +1,284 @@
+package declarations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-server/internal/pdf"
+	"go-server/internal/signing"
+
+	"github.com/google/uuid"
+)
+
+// summaryPayloadVersion is bumped whenever the canonical summary payload layout changes
+const summaryPayloadVersion = "v1"
+
+// Layout of the declaration summary, in points from the left edge
+const (
+	summaryLeft   = 40.0
+	summaryRight  = 555.0
+	summaryColNo  = 62.0
+	summaryCol1   = 330.0
+	summaryCol2   = 405.0
+	summaryCol3   = 480.0
+	summaryBottom = 790.0
+)
+
+// declarationTitles are the names of the forms summarized, by declaration type
+var declarationTitles = map[string]string{
+	DeclarationTypeCNAPS: "Déclaration nominative des salaires - CNAPS",
+	DeclarationTypeOSTIE: "Liste nominative des salariés - OSTIE",
+	DeclarationTypeIRSA:  "État nominatif des salaires - IRSA",
+}
+
+// summaryWatermarks mark summaries of declarations that were not filed
+var summaryWatermarks = map[string]string{
+	"draft":     "BROUILLON - NON DÉPOSÉE",
+	"cancelled": "DÉCLARATION ANNULÉE",
+}
+
+// renderDeclarationPDF lays out a declaration summary on A4 pages: company identity, totals, one line per employee
+// and, for IRSA, the totals per bracket. It ends with the export's signature and the address to verify it at.
+func renderDeclarationPDF(form *DeclarationForm, export *DeclarationSummaryExport, exporterName string) ([]byte, error) {
+	doc := pdf.New()
+	doc.AddPage()
+	doc.SetTitle("Déclaration " + form.DeclarationNumber)
+	if watermark := summaryWatermarks[form.Status]; watermark != "" {
+		doc.SetWatermark(watermark)
+	}
+
+	// Company identity and declaration reference
+	y := 55.0
+	doc.Text(summaryLeft, y, 16, true, form.CompanyName)
+	doc.TextRight(summaryRight, y, 11, true, "N° "+form.DeclarationNumber)
+	y += 15
+	if form.CompanyAddress != "" {
+		doc.Text(summaryLeft, y, 9, false, form.CompanyAddress)
+	}
+	doc.TextRight(summaryRight, y, 9, false, fmt.Sprintf("Période du %s au %s",
+		form.DeclarationPeriodStart.Format("02/01/2006"), form.DeclarationPeriodEnd.Format("02/01/2006")))
+	y += 12
+	doc.Text(summaryLeft, y, 9, false, joinNonEmpty("   ", labelled("NIF", form.CompanyNIF), labelled("STAT", form.CompanySTAT)))
+	doc.TextRight(summaryRight, y, 9, false, "Statut : "+form.Status)
+	y += 12
+	doc.Text(summaryLeft, y, 9, false, joinNonEmpty("   ", labelled("CNAPS", form.CNAPSNumber), labelled("OSTIE", form.OSTIENumber)))
+	y += 12
+	doc.Line(summaryLeft, y, summaryRight, y)
+
+	y += 24
+	doc.Text(summaryLeft, y, 13, true, declarationTitles[form.DeclarationType])
+
+	// Totals
+	y += 22
+	totals := []struct {
+		label  string
+		amount string
+	}{
+		{"Salariés déclarés", fmt.Sprintf("%d", form.TotalEmployees)},
+		{"Total des salaires bruts", pdf.FormatAmount(form.TotalGrossSalary)},
+		{"Total des parts salariales", pdf.FormatAmount(form.TotalEmployeeContributions)},
+		{"Total des parts patronales", pdf.FormatAmount(form.TotalEmployerContributions)},
+	}
+	if form.DeclarationType == DeclarationTypeIRSA {
+		totals[2].label = "Total de l'IRSA retenu"
+		totals = totals[:3]
+	}
+	for _, total := range totals {
+		doc.Text(summaryLeft, y, 10, false, total.label)
+		doc.TextRight(summaryCol2, y, 10, false, total.amount)
+		y += 14
+	}
+	y += 4
+	doc.FillRect(summaryLeft, y-13, summaryCol2-summaryLeft+5, 20, 0.9)
+	doc.Text(summaryLeft+5, y, 11, true, "MONTANT À PAYER")
+	doc.TextRight(summaryCol2, y, 11, true, pdf.FormatAmount(form.TotalAmountDue))
+
+	// Employee lines, repeating the header on each new page
+	headers := []string{"Base", "Part salariale", "Part patronale", "Total"}
+	if form.DeclarationType == DeclarationTypeIRSA {
+		headers = []string{"Salaire brut", "Revenu imposable", "Réduction", "IRSA retenu"}
+	}
+	tableHeader := func() {
+		doc.FillRect(summaryLeft, y-12, summaryRight-summaryLeft, 17, 0.9)
+		doc.Text(summaryLeft+5, y, 8, true, "N°")
+		doc.Text(summaryColNo, y, 8, true, "Nom et prénoms")
+		for i, x := range []float64{summaryCol1, summaryCol2, summaryCol3, summaryRight - 5} {
+			doc.TextRight(x, y, 8, true, headers[i])
+		}
+	}
+
+	y += 34
+	tableHeader()
+	for i, entry := range form.EmployeeBreakdown {
+		y += 14
+		if y > summaryBottom {
+			doc.AddPage()
+			y = 55
+			tableHeader()
+			y += 14
+		}
+
+		amounts := []float64{entry.BaseAmount, entry.EmployeeContribution, entry.EmployerContribution,
+			entry.EmployeeContribution + entry.EmployerContribution}
+		if form.DeclarationType == DeclarationTypeIRSA {
+			reduction := 0.0
+			if entry.IRSABreakdown != nil {
+				reduction = entry.IRSABreakdown.DependentReduction
+			}
+			amounts = []float64{entry.GrossSalary, entry.BaseAmount, reduction, entry.EmployeeContribution}
+		}
+
+		doc.Text(summaryLeft+5, y, 8, false, fmt.Sprintf("%d", i+1))
+		doc.Text(summaryColNo, y, 8, false, fitText(entry.EmployeeName, summaryCol1-summaryColNo-70, 8))
+		for j, x := range []float64{summaryCol1, summaryCol2, summaryCol3, summaryRight - 5} {
+			doc.TextRight(x, y, 8, false, pdf.FormatAmount(amounts[j]))
+		}
+	}
+	y += 8
+	doc.Line(summaryLeft, y, summaryRight, y)
+
+	// IRSA totals per bracket
+	if len(form.IRSABrackets) > 0 {
+		if y+40+14*float64(len(form.IRSABrackets)) > summaryBottom {
+			doc.AddPage()
+			y = 40
+		}
+		y += 26
+		doc.Text(summaryLeft, y, 10, true, "Répartition par tranche")
+		for _, bracket := range form.IRSABrackets {
+			y += 14
+			doc.Text(summaryLeft+5, y, 8, false, fmt.Sprintf("%s (%s %%, %d salarié(s))",
+				bracket.BracketName, strings.TrimSuffix(pdf.FormatAmount(bracket.Rate*100), ",00"), bracket.Employees))
+			doc.TextRight(summaryCol2, y, 8, false, pdf.FormatAmount(bracket.TaxableAmount))
+			doc.TextRight(summaryRight-5, y, 8, false, pdf.FormatAmount(bracket.Tax))
+		}
+		if form.IRSADependentReductions > 0 {
+			y += 14
+			doc.Text(summaryLeft+5, y, 8, false, "Réductions pour personnes à charge")
+			doc.TextRight(summaryRight-5, y, 8, false, "-"+pdf.FormatAmount(form.IRSADependentReductions))
+		}
+	}
+
+	// Signature
+	if y+70 > summaryBottom {
+		doc.AddPage()
+		y = 40
+	}
+	y += 36
+	doc.Text(summaryLeft, y, 9, false, fmt.Sprintf("Établi par %s le %s", exporterName, export.ExportedAt.Format("02/01/2006 15:04")))
+	y += 14
+	doc.Text(summaryLeft, y, 8, true, "Signature numérique (Ed25519, clé "+export.SignatureKeyID+")")
+	y += 11
+	doc.Text(summaryLeft, y, 7, false, export.Signature)
+	y += 12
+	doc.Text(summaryLeft, y, 7, false, "Horodatage signé : "+export.ExportedAt.UTC().Format(time.RFC3339)+"   Auteur : "+export.ExportedBy.String())
+	y += 12
+	doc.Text(summaryLeft, y, 7, false, fmt.Sprintf("Vérification : /api/v1/declarations/%s/exports/%s/verify", export.DeclarationID, export.ID))
+
+	return doc.Bytes()
+}
+
+// signSummary signs the canonical summary payload of a declaration as of an export, filling in the export's
+// payload hash, signature and key ID
+func signSummary(form *DeclarationForm, export *DeclarationSummaryExport) error {
+	s, err := signing.Default()
+	if err != nil {
+		return err
+	}
+
+	payload := canonicalSummaryPayload(form, export.ExportedBy, export.ExportedAt)
+	export.PayloadHash = summaryPayloadHash(payload)
+	export.Signature, export.SignatureKeyID = s.Sign(payload)
+	return nil
+}
+
+// verifySummary checks a stored summary export against the declaration as it stands, returning the reasons it fails
+func verifySummary(form *DeclarationForm, export *DeclarationSummaryExport) []string {
+	s, err := signing.Default()
+	if err != nil {
+		return []string{"signing key is not configured"}
+	}
+
+	payload := canonicalSummaryPayload(form, export.ExportedBy, export.ExportedAt)
+	if summaryPayloadHash(payload) != export.PayloadHash {
+		return []string{"declaration has changed since the summary was exported"}
+	}
+	switch err := s.Verify(export.SignatureKeyID, payload, export.Signature); err {
+	case nil:
+		return nil
+	case signing.ErrUnknownKey:
+		return []string{"summary was signed with an unknown key"}
+	case signing.ErrMalformedSignature:
+		return []string{"signature is malformed"}
+	default:
+		return []string{"declaration data does not match its signature"}
+	}
+}
+
+// summaryPayloadHash returns the hex SHA-256 of a canonical summary payload
+func summaryPayloadHash(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalSummaryPayload serializes the signed declaration fields in a fixed order and format
+func canonicalSummaryPayload(form *DeclarationForm, exportedBy uuid.UUID, exportedAt time.Time) []byte {
+	fields := []string{
+		"version=" + summaryPayloadVersion,
+		"declaration_number=" + form.DeclarationNumber,
+		"declaration_type=" + form.DeclarationType,
+		"period_start=" + form.DeclarationPeriodStart.Format("2006-01-02"),
+		"period_end=" + form.DeclarationPeriodEnd.Format("2006-01-02"),
+		"company_nif=" + form.CompanyNIF,
+		"company_stat=" + form.CompanySTAT,
+		"cnaps_number=" + form.CNAPSNumber,
+		"ostie_number=" + form.OSTIENumber,
+		"status=" + form.Status,
+		fmt.Sprintf("total_employees=%d", form.TotalEmployees),
+		fmt.Sprintf("total_gross_salary=%.2f", form.TotalGrossSalary),
+		fmt.Sprintf("total_employee_contributions=%.2f", form.TotalEmployeeContributions),
+		fmt.Sprintf("total_employer_contributions=%.2f", form.TotalEmployerContributions),
+		fmt.Sprintf("total_amount_due=%.2f", form.TotalAmountDue),
+	}
+	for _, entry := range form.EmployeeBreakdown {
+		fields = append(fields, fmt.Sprintf("employee=%s|%.2f|%.2f|%.2f|%.2f", entry.EmployeeID,
+			entry.GrossSalary, entry.BaseAmount, entry.EmployeeContribution, entry.EmployerContribution))
+	}
+	fields = append(fields,
+		"exported_by="+exportedBy.String(),
+		"exported_at="+exportedAt.UTC().Format(time.RFC3339),
+	)
+	return []byte(strings.Join(fields, "\n"))
+}
+
+// fitText shortens s with an ellipsis so it fits in width points
+func fitText(s string, width, size float64) string {
+	if pdf.TextWidth(s, size, false) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"...", size, false) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+func labelled(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + " : " + value
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,103 @@
+package declarations
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// penaltyRates are the late-payment rates of a declaration type in force on a due date
+type penaltyRates struct {
+	surcharge       float64
+	monthlyInterest float64
+}
+
+// dueDate returns the day a declaration of a type for the month starting at periodStart must be paid by:
+// the configured day (<type>_declaration_due_day) of the following month, or its last day when shorter
+func (r *Repo) dueDate(ctx context.Context, declarationType string, periodStart time.Time) (time.Time, error) {
+	nextMonth := time.Date(periodStart.Year(), periodStart.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	day, err := r.configRepo.GetConfigValueAsInt(ctx, declarationType+"_declaration_due_day", nextMonth)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	lastDay := nextMonth.AddDate(0, 1, -1).Day()
+	if day < 1 {
+		day = 1
+	}
+	if day > lastDay {
+		day = lastDay
+	}
+	return nextMonth.AddDate(0, 0, day-1), nil
+}
+
+// penaltyRatesAt resolves the surcharge and monthly interest rates of a declaration type in force on its due date
+func (r *Repo) penaltyRatesAt(ctx context.Context, declarationType string, dueDate time.Time) (penaltyRates, error) {
+	surcharge, err := r.configRepo.GetConfigValueAsFloat(ctx, declarationType+"_late_penalty_rate", dueDate)
+	if err != nil {
+		return penaltyRates{}, err
+	}
+	interest, err := r.configRepo.GetConfigValueAsFloat(ctx, declarationType+"_late_interest_rate", dueDate)
+	if err != nil {
+		return penaltyRates{}, err
+	}
+	return penaltyRates{surcharge: surcharge, monthlyInterest: interest}, nil
+}
+
+// computePenalty works out the late penalty of an amount due on dueDate given the payments made against it.
+// The penalty applies to the amount still unpaid at the end of the due date and runs until the payment
+// that settles the declaration, or until asOf while it is unsettled. It returns nil when nothing was late.
+func computePenalty(amountDue float64, dueDate time.Time, payments []DeclarationPayment, asOf time.Time, rates penaltyRates) *DeclarationPenalty {
+	dueDate = dateOnly(dueDate)
+	sorted := append([]DeclarationPayment{}, payments...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PaidOn.Before(sorted[j].PaidOn) })
+
+	var paidByDueDate float64
+	for _, payment := range sorted {
+		if !dateOnly(payment.PaidOn).After(dueDate) {
+			paidByDueDate += payment.Amount
+		}
+	}
+	lateAmount := roundAmount(amountDue - paidByDueDate)
+	if lateAmount <= 0 {
+		return nil
+	}
+
+	lateUntil := dateOnly(asOf)
+	var paid float64
+	for _, payment := range sorted {
+		paid += payment.Amount
+		if roundAmount(paid) >= roundAmount(amountDue) {
+			lateUntil = dateOnly(payment.PaidOn)
+			break
+		}
+	}
+	if !lateUntil.After(dueDate) {
+		return nil
+	}
+
+	// Every started month of delay counts in full
+	months := 0
+	for dueDate.AddDate(0, months, 0).Before(lateUntil) {
+		months++
+	}
+
+	surcharge := roundAmount(lateAmount * rates.surcharge)
+	interest := roundAmount(lateAmount * rates.monthlyInterest * float64(months))
+	return &DeclarationPenalty{
+		LateAmount:          lateAmount,
+		LateUntil:           lateUntil,
+		DaysLate:            int(lateUntil.Sub(dueDate).Hours() / 24),
+		MonthsLate:          months,
+		SurchargeRate:       rates.surcharge,
+		MonthlyInterestRate: rates.monthlyInterest,
+		Surcharge:           surcharge,
+		Interest:            interest,
+		Total:               roundAmount(surcharge + interest),
+	}
+}
+
+// dateOnly truncates a time to midnight UTC of its calendar day
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
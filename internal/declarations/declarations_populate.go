@@ -0,0 +1,207 @@
+package declarations
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"go-server/internal/company"
+	"go-server/internal/taxrules"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// approvedPayrollRow is one approved payslip with the employee it was issued to
+type approvedPayrollRow struct {
+	DraftID         uuid.UUID
+	FichePaieNumber string
+	EmployeeID      uuid.UUID
+	FirstName       string
+	LastName        string
+	NIF             string
+	GrossSalary     float64
+	CNAPSBase       float64
+	CNAPSEmployee   float64
+	CNAPSEmployer   float64
+	OSTIEBase       float64
+	OSTIEEmployee   float64
+	OSTIEEmployer   float64
+	IRSA            float64
+	IRSABreakdown   *taxrules.IRSABreakdown
+}
+
+// monthPeriod checks that a declaration period is one whole calendar month and returns its bounds as dates
+func monthPeriod(start, end time.Time) (time.Time, time.Time, error) {
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	monthEnd := time.Date(start.Year(), start.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	if start.Day() != 1 || !end.Equal(monthEnd) {
+		return time.Time{}, time.Time{}, fmt.Errorf("declaration period must be a whole calendar month, from %s to %s",
+			time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02"), monthEnd.Format("2006-01-02"))
+	}
+	return start, end, nil
+}
+
+// applyCompanyIdentity copies the company identity from company settings onto a declaration
+func applyCompanyIdentity(tx *gorm.DB, declaration *MonthlyDeclaration) error {
+	var settings company.CompanySettings
+	if err := tx.First(&settings).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("company settings not found: configure the company before declaring")
+		}
+		return fmt.Errorf("get company settings: %w", err)
+	}
+
+	declaration.CompanyName = settings.CompanyName
+	declaration.CompanyAddress = derefString(settings.CompanyAddress)
+	declaration.CompanyNIF = derefString(settings.CompanyNIF)
+	declaration.CompanySTAT = derefString(settings.CompanySTAT)
+	declaration.CNAPSNumber = derefString(settings.CNAPSNumber)
+	declaration.OSTIENumber = derefString(settings.OSTIENumber)
+	return nil
+}
+
+// approvedPayroll retrieves the approved payslips whose period ends in the declared month, including
+// those of employees who have since left
+func approvedPayroll(tx *gorm.DB, periodStart, periodEnd time.Time) ([]approvedPayrollRow, error) {
+	var rows []approvedPayrollRow
+	if err := tx.Table("payroll_approved a").
+		Select(`d.id AS draft_id, a.fiche_paie_number, d.employee_id, e.first_name, e.last_name, COALESCE(e.nif, '') AS nif,
+			d.gross_salary, d.cnaps_base, d.cnaps_employee, d.cnaps_employer,
+			d.ostie_base, d.ostie_employee, d.ostie_employer, d.irsa, d.irsa_breakdown`).
+		Joins("JOIN payroll_drafts d ON d.id = a.draft_id AND d.deleted_at IS NULL").
+		Joins("JOIN employees e ON e.id = d.employee_id").
+		Where("d.status = ? AND d.period_end >= ? AND d.period_end <= ?", "approved", periodStart, periodEnd).
+		Order("e.last_name ASC, e.first_name ASC, d.period_start ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("get approved payroll: %w", err)
+	}
+	return rows, nil
+}
+
+// buildEmployeeBreakdown lists each employee once with the amounts a declaration type reports,
+// summing every approved payslip the employee received in the month
+func buildEmployeeBreakdown(declarationType string, rows []approvedPayrollRow) []EmployeeBreakdown {
+	breakdown := []EmployeeBreakdown{}
+	index := map[uuid.UUID]int{}
+
+	for _, row := range rows {
+		i, ok := index[row.EmployeeID]
+		if !ok {
+			i = len(breakdown)
+			index[row.EmployeeID] = i
+			breakdown = append(breakdown, EmployeeBreakdown{
+				EmployeeID:   row.EmployeeID,
+				EmployeeName: row.FirstName + " " + row.LastName,
+				EmployeeNIF:  row.NIF,
+			})
+		}
+
+		entry := &breakdown[i]
+		entry.GrossSalary += row.GrossSalary
+		entry.FichePaieNumbers = append(entry.FichePaieNumbers, row.FichePaieNumber)
+		switch declarationType {
+		case DeclarationTypeCNAPS:
+			entry.BaseAmount += row.CNAPSBase
+			entry.EmployeeContribution += row.CNAPSEmployee
+			entry.EmployerContribution += row.CNAPSEmployer
+		case DeclarationTypeOSTIE:
+			entry.BaseAmount += row.OSTIEBase
+			entry.EmployeeContribution += row.OSTIEEmployee
+			entry.EmployerContribution += row.OSTIEEmployer
+		case DeclarationTypeIRSA:
+			// IRSA is withheld from the employee only; its base is the taxable income
+			entry.BaseAmount += row.GrossSalary - row.CNAPSEmployee - row.OSTIEEmployee
+			entry.EmployeeContribution += row.IRSA
+			entry.IRSABreakdown = mergeIRSABreakdowns(entry.IRSABreakdown, row.IRSABreakdown)
+		}
+	}
+	return breakdown
+}
+
+// mergeIRSABreakdowns adds the IRSA computation of a second payslip in the month to the first
+func mergeIRSABreakdowns(total, next *taxrules.IRSABreakdown) *taxrules.IRSABreakdown {
+	if next == nil {
+		return total
+	}
+	if total == nil {
+		merged := *next
+		merged.Brackets = append([]taxrules.IRSABracketTax{}, next.Brackets...)
+		return &merged
+	}
+
+	total.TaxableIncome += next.TaxableIncome
+	total.GrossTax += next.GrossTax
+	total.DependentReduction += next.DependentReduction
+	total.MinimumTax += next.MinimumTax
+	total.Tax += next.Tax
+	if next.Dependents > total.Dependents {
+		total.Dependents = next.Dependents
+	}
+	for _, bracket := range next.Brackets {
+		found := false
+		for i := range total.Brackets {
+			if total.Brackets[i].BracketName == bracket.BracketName {
+				total.Brackets[i].TaxableAmount += bracket.TaxableAmount
+				total.Brackets[i].Tax += bracket.Tax
+				found = true
+				break
+			}
+		}
+		if !found {
+			total.Brackets = append(total.Brackets, bracket)
+		}
+	}
+	return total
+}
+
+// setDeclarationData stores the employee breakdown on a declaration and recomputes its totals from it
+func setDeclarationData(declaration *MonthlyDeclaration, breakdown []EmployeeBreakdown) error {
+	data, err := json.Marshal(breakdown)
+	if err != nil {
+		return fmt.Errorf("marshal declaration data: %w", err)
+	}
+
+	var gross, employee, employer float64
+	for _, entry := range breakdown {
+		gross += entry.GrossSalary
+		employee += entry.EmployeeContribution
+		employer += entry.EmployerContribution
+	}
+
+	declaration.DeclarationData = string(data)
+	declaration.TotalEmployees = len(breakdown)
+	declaration.TotalGrossSalary = roundAmount(gross)
+	declaration.TotalEmployeeContributions = roundAmount(employee)
+	declaration.TotalEmployerContributions = roundAmount(employer)
+	declaration.TotalAmountDue = roundAmount(employee + employer)
+	return nil
+}
+
+// populateFromPayroll refreshes a declaration's company identity, employee breakdown and totals
+func populateFromPayroll(tx *gorm.DB, declaration *MonthlyDeclaration) error {
+	if err := applyCompanyIdentity(tx, declaration); err != nil {
+		return err
+	}
+
+	rows, err := approvedPayroll(tx, declaration.DeclarationPeriodStart, declaration.DeclarationPeriodEnd)
+	if err != nil {
+		return err
+	}
+	return setDeclarationData(declaration, buildEmployeeBreakdown(declaration.DeclarationType, rows))
+}
+
+// roundAmount rounds an amount to the cent
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// derefString returns the value of an optional string, or "" when unset
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -3,48 +3,151 @@ package declarations
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"go-server/internal/auth"
+	"go-server/internal/employee"
+	"go-server/internal/payroll"
+	"go-server/internal/sequence"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // Repo handles database operations for declarations
 type Repo struct {
-	db *gorm.DB
+	db         *gorm.DB
+	configRepo *payroll.ConfigRepo
 }
 
 // NewRepo creates a new declarations repository
 func NewRepo(database *gorm.DB) *Repo {
-	return &Repo{db: database}
+	return &Repo{db: database, configRepo: payroll.NewConfigRepo(database)}
 }
 
-// CreateDeclaration creates a new monthly declaration
+// errDeclarationExists stops a creation when the month is already declared
+var errDeclarationExists = errors.New("declaration already exists for this type and period")
+
+// CreateDeclaration creates a monthly declaration populated from company settings and approved payroll.
+// Only one declaration of each type may exist for a month, unless the earlier one was cancelled.
 func (r *Repo) CreateDeclaration(ctx context.Context, declaration *MonthlyDeclaration) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	existing, err := r.createDeclaration(ctx, declaration)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%s declaration %s already covers this month", strings.ToUpper(existing.DeclarationType), existing.DeclarationNumber)
+	}
+	return nil
+}
+
+// GenerateMonthlyDeclaration returns the declaration of a type for the month starting at periodStart,
+// creating and populating it from approved payroll when the month has not been declared yet
+func (r *Repo) GenerateMonthlyDeclaration(ctx context.Context, declarationType string, periodStart time.Time, accountantID uuid.UUID) (*MonthlyDeclaration, error) {
+	declaration := &MonthlyDeclaration{
+		DeclarationType:        declarationType,
+		DeclarationPeriodStart: periodStart,
+		DeclarationPeriodEnd:   time.Date(periodStart.Year(), periodStart.Month()+1, 0, 0, 0, 0, 0, periodStart.Location()),
+		AccountantID:           accountantID,
+		Status:                 DeclarationStatusDraft,
+	}
+
+	existing, err := r.createDeclaration(ctx, declaration)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+	return declaration, nil
+}
+
+// createDeclaration creates a declaration unless one already covers its type and month, which it returns instead
+func (r *Repo) createDeclaration(ctx context.Context, declaration *MonthlyDeclaration) (*MonthlyDeclaration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	// Check if declaration already exists for this type and period
-	var existing MonthlyDeclaration
-	err := r.db.WithContext(ctx).Where("declaration_type = ? AND declaration_period_start = ? AND declaration_period_end = ?",
-		declaration.DeclarationType, declaration.DeclarationPeriodStart, declaration.DeclarationPeriodEnd).First(&existing).Error
-	if err == nil {
-		return fmt.Errorf("declaration already exists for this type and period")
+	periodStart, periodEnd, err := monthPeriod(declaration.DeclarationPeriodStart, declaration.DeclarationPeriodEnd)
+	if err != nil {
+		return nil, err
 	}
-	if err != gorm.ErrRecordNotFound {
-		return fmt.Errorf("check existing declaration: %w", err)
+	declaration.DeclarationPeriodStart = periodStart
+	declaration.DeclarationPeriodEnd = periodEnd
+	declaration.DueDate, err = r.dueDate(ctx, declaration.DeclarationType, periodStart)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate declaration number if not provided
-	if declaration.DeclarationNumber == "" {
-		declaration.DeclarationNumber = generateDeclarationNumber(declaration.DeclarationType, declaration.DeclarationPeriodStart)
+	var existing MonthlyDeclaration
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Allocating the number locks the type's sequence row until commit, so concurrent
+		// creations for the same type wait here and then see each other's declarations
+		number, err := generateDeclarationNumber(tx, declaration.DeclarationType, declaration.DeclarationPeriodStart)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("declaration_type = ? AND declaration_period_start = ? AND status <> ?",
+			declaration.DeclarationType, declaration.DeclarationPeriodStart, DeclarationStatusCancelled).First(&existing).Error
+		if err == nil {
+			return errDeclarationExists
+		}
+		if err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("check existing declaration: %w", err)
+		}
+
+		if err := populateFromPayroll(tx, declaration); err != nil {
+			return err
+		}
+
+		declaration.DeclarationNumber = number
+		if err := tx.Create(declaration).Error; err != nil {
+			return fmt.Errorf("create declaration: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, errDeclarationExists) {
+		return &existing, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
 
-	if err := r.db.WithContext(ctx).Create(declaration).Error; err != nil {
-		return fmt.Errorf("create declaration: %w", err)
+// PopulateDeclaration rebuilds a draft declaration from company settings and the approved payroll of its month
+func (r *Repo) PopulateDeclaration(ctx context.Context, id uuid.UUID) (*MonthlyDeclaration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var declaration *MonthlyDeclaration
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		declaration, err = lockDeclaration(tx, id)
+		if err != nil {
+			return err
+		}
+		if declaration.Status != DeclarationStatusDraft {
+			return fmt.Errorf("only draft declarations can be populated, this one is %s", declaration.Status)
+		}
+
+		if err := populateFromPayroll(tx, declaration); err != nil {
+			return err
+		}
+
+		declaration.UpdatedAt = time.Now()
+		if err := tx.Save(declaration).Error; err != nil {
+			return fmt.Errorf("update declaration: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return declaration, nil
 }
 
 // GetDeclarationByID retrieves a declaration by ID
@@ -77,31 +180,25 @@ func (r *Repo) GetDeclarationByNumber(ctx context.Context, declarationNumber str
 	return &declaration, nil
 }
 
-// UpdateDeclaration updates a declaration
-func (r *Repo) UpdateDeclaration(ctx context.Context, declaration *MonthlyDeclaration) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	declaration.UpdatedAt = time.Now()
-	if err := r.db.WithContext(ctx).Save(declaration).Error; err != nil {
-		return fmt.Errorf("update declaration: %w", err)
-	}
-	return nil
-}
-
-// DeleteDeclaration deletes a declaration
+// DeleteDeclaration deletes a draft declaration; filed declarations are cancelled instead
 func (r *Repo) DeleteDeclaration(ctx context.Context, id uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	result := r.db.WithContext(ctx).Delete(&MonthlyDeclaration{}, id)
-	if result.Error != nil {
-		return fmt.Errorf("delete declaration: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("declaration not found")
-	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		declaration, err := lockDeclaration(tx, id)
+		if err != nil {
+			return err
+		}
+		if declaration.Status != DeclarationStatusDraft {
+			return fmt.Errorf("only draft declarations can be deleted, this one is %s; cancel it instead", declaration.Status)
+		}
+
+		if err := tx.Delete(declaration).Error; err != nil {
+			return fmt.Errorf("delete declaration: %w", err)
+		}
+		return nil
+	})
 }
 
 // ListDeclarations retrieves declarations with filtering
@@ -149,115 +246,14 @@ func (r *Repo) ListDeclarations(ctx context.Context, query DeclarationListQuery)
 	return declarations, total, nil
 }
 
-// CreateIRSATaxBracket creates a new IRSA tax bracket
-func (r *Repo) CreateIRSATaxBracket(ctx context.Context, bracket *IRSATaxBracket) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	if err := r.db.WithContext(ctx).Create(bracket).Error; err != nil {
-		return fmt.Errorf("create IRSA tax bracket: %w", err)
-	}
-	return nil
-}
-
-// GetIRSATaxBracketByID retrieves an IRSA tax bracket by ID
-func (r *Repo) GetIRSATaxBracketByID(ctx context.Context, id uuid.UUID) (*IRSATaxBracket, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	var bracket IRSATaxBracket
-	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&bracket).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("IRSA tax bracket not found")
-		}
-		return nil, fmt.Errorf("get IRSA tax bracket: %w", err)
-	}
-	return &bracket, nil
-}
-
-// UpdateIRSATaxBracket updates an IRSA tax bracket
-func (r *Repo) UpdateIRSATaxBracket(ctx context.Context, bracket *IRSATaxBracket) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	bracket.UpdatedAt = time.Now()
-	if err := r.db.WithContext(ctx).Save(bracket).Error; err != nil {
-		return fmt.Errorf("update IRSA tax bracket: %w", err)
-	}
-	return nil
-}
-
-// DeleteIRSATaxBracket deletes an IRSA tax bracket
-func (r *Repo) DeleteIRSATaxBracket(ctx context.Context, id uuid.UUID) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	result := r.db.WithContext(ctx).Delete(&IRSATaxBracket{}, id)
-	if result.Error != nil {
-		return fmt.Errorf("delete IRSA tax bracket: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("IRSA tax bracket not found")
-	}
-	return nil
-}
-
-// ListIRSATaxBrackets retrieves IRSA tax brackets with filtering
-func (r *Repo) ListIRSATaxBrackets(ctx context.Context, query IRSATaxBracketListQuery) ([]IRSATaxBracket, int64, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	var brackets []IRSATaxBracket
-	var total int64
-
-	db := r.db.WithContext(ctx).Model(&IRSATaxBracket{})
-
-	// Apply filters
-	if query.IsActive != nil {
-		db = db.Where("is_active = ?", *query.IsActive)
-	}
-	if query.EffectiveDate != nil {
-		db = db.Where("effective_date <= ?", *query.EffectiveDate)
-	}
-
-	// Count total
-	if err := db.Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("count IRSA tax brackets: %w", err)
-	}
-
-	// Apply pagination
-	limit := query.Limit
-	if limit == 0 {
-		limit = 50
-	}
-
-	if err := db.Limit(limit).Offset(query.Offset).Order("min_income ASC").Find(&brackets).Error; err != nil {
-		return nil, 0, fmt.Errorf("list IRSA tax brackets: %w", err)
-	}
-
-	return brackets, total, nil
-}
-
-// GetActiveIRSATaxBrackets retrieves all active IRSA tax brackets for a given date
-func (r *Repo) GetActiveIRSATaxBrackets(ctx context.Context, effectiveDate time.Time) ([]IRSATaxBracket, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	var brackets []IRSATaxBracket
-	if err := r.db.WithContext(ctx).Where("is_active = ? AND effective_date <= ?", true, effectiveDate).
-		Order("min_income ASC").Find(&brackets).Error; err != nil {
-		return nil, fmt.Errorf("get active IRSA tax brackets: %w", err)
+// generateDeclarationNumber allocates the next gap-free number for the declaration type
+// in the fiscal year of the declared period, e.g. CNAPS-2026-00001
+func generateDeclarationNumber(tx *gorm.DB, declarationType string, periodStart time.Time) (string, error) {
+	fiscalYear, seq, err := sequence.Next(tx, sequence.Declaration(declarationType), periodStart)
+	if err != nil {
+		return "", err
 	}
-	return brackets, nil
-}
-
-// generateDeclarationNumber generates a unique declaration number
-func generateDeclarationNumber(declarationType string, periodStart time.Time) string {
-	// Format: TYPE-YYYYMM-XXXX
-	// e.g., CNAPS-202601-0001, OSTIE-202601-0001, IRSA-202601-0001
-	year := periodStart.Year()
-	month := int(periodStart.Month())
-	return fmt.Sprintf("%s-%04d%02d-%04d", declarationType, year, month, 1000+int(periodStart.Unix()%9000))
+	return sequence.Format(strings.ToUpper(declarationType), fiscalYear, seq), nil
 }
 
 // GenerateDeclarationForm generates a declaration form for CNAPS, OSTIE, or IRSA
@@ -270,6 +266,11 @@ func (r *Repo) GenerateDeclarationForm(ctx context.Context, declarationID uuid.U
 		return nil, err
 	}
 
+	accountantName, err := r.getUserDisplayName(ctx, declaration.AccountantID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse declaration data
 	var employeeBreakdown []EmployeeBreakdown
 	if declaration.DeclarationData != "" {
@@ -287,6 +288,9 @@ func (r *Repo) GenerateDeclarationForm(ctx context.Context, declarationID uuid.U
 		CompanyName:                declaration.CompanyName,
 		CompanyAddress:             declaration.CompanyAddress,
 		CompanyNIF:                 declaration.CompanyNIF,
+		CompanySTAT:                declaration.CompanySTAT,
+		CNAPSNumber:                declaration.CNAPSNumber,
+		OSTIENumber:                declaration.OSTIENumber,
 		TotalEmployees:             declaration.TotalEmployees,
 		TotalGrossSalary:           declaration.TotalGrossSalary,
 		TotalEmployeeContributions: declaration.TotalEmployeeContributions,
@@ -294,10 +298,65 @@ func (r *Repo) GenerateDeclarationForm(ctx context.Context, declarationID uuid.U
 		TotalAmountDue:             declaration.TotalAmountDue,
 		EmployeeBreakdown:          employeeBreakdown,
 		Status:                     declaration.Status,
-		AccountantName:             "Accountant Name", // TODO: Get from user service
+		DueDate:                    declaration.DueDate,
+		AmountPaid:                 declaration.AmountPaid,
+		AccountantName:             accountantName,
 		CreatedAt:                  declaration.CreatedAt,
 		SubmittedAt:                declaration.SubmittedAt,
 	}
 
+	if declaration.DeclarationType == DeclarationTypeIRSA {
+		form.IRSABrackets, form.IRSADependentReductions = summarizeIRSABrackets(employeeBreakdown)
+	}
+
 	return form, nil
 }
+
+// summarizeIRSABrackets totals the employees' IRSA breakdowns per bracket, in scale order
+func summarizeIRSABrackets(employees []EmployeeBreakdown) ([]IRSABracketTotal, float64) {
+	var totals []IRSABracketTotal
+	index := map[string]int{}
+	var dependentReductions float64
+
+	for _, employee := range employees {
+		if employee.IRSABreakdown == nil {
+			continue
+		}
+		dependentReductions += employee.IRSABreakdown.DependentReduction
+		for _, bracket := range employee.IRSABreakdown.Brackets {
+			i, ok := index[bracket.BracketName]
+			if !ok {
+				i = len(totals)
+				index[bracket.BracketName] = i
+				totals = append(totals, IRSABracketTotal{BracketName: bracket.BracketName, Rate: bracket.Rate})
+			}
+			totals[i].Employees++
+			totals[i].TaxableAmount += bracket.TaxableAmount
+			totals[i].Tax += bracket.Tax
+		}
+	}
+	return totals, dependentReductions
+}
+
+// getUserDisplayName returns the linked employee's full name for a user, falling back to the email
+func (r *Repo) getUserDisplayName(ctx context.Context, userID uuid.UUID) (string, error) {
+	var user auth.User
+	if err := r.db.WithContext(ctx).Unscoped().Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", fmt.Errorf("user not found")
+		}
+		return "", fmt.Errorf("get user: %w", err)
+	}
+
+	if user.EmployeeID != nil {
+		var emp employee.Employee
+		err := r.db.WithContext(ctx).Unscoped().Select("first_name", "last_name").Where("id = ?", *user.EmployeeID).First(&emp).Error
+		if err == nil {
+			return strings.TrimSpace(emp.FirstName + " " + emp.LastName), nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return "", fmt.Errorf("get employee: %w", err)
+		}
+	}
+	return user.Email, nil
+}
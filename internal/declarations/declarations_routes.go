@@ -15,16 +15,25 @@ func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
 	declarations := rg.Group("/declarations")
 	declarations.Use(middleware.AuthMiddleware())
 	{
-		// Monthly Declaration Routes (Accountant only for write, authenticated for read)
+		// Monthly Declaration Routes (Accountant only: declaration data carries each employee's pay)
 		declarations.POST("", middleware.RequireRole("admin", "accountant"), handler.CreateDeclaration)
-		declarations.GET("", handler.ListDeclarations)
-		declarations.GET("/:id", handler.GetDeclarationByID)
-		declarations.GET("/number/:declaration_number", handler.GetDeclarationByNumber)
+		declarations.GET("", middleware.RequireRole("admin", "accountant"), handler.ListDeclarations)
+		declarations.GET("/overdue", middleware.RequireRole("admin", "accountant"), handler.ListOverdueDeclarations)
+		declarations.GET("/:id", middleware.RequireRole("admin", "accountant"), handler.GetDeclarationByID)
+		declarations.GET("/number/:declaration_number", middleware.RequireRole("admin", "accountant"), handler.GetDeclarationByNumber)
 		declarations.PUT("/:id", middleware.RequireRole("admin", "accountant"), handler.UpdateDeclaration)
 		declarations.DELETE("/:id", middleware.RequireRole("admin", "accountant"), handler.DeleteDeclaration)
-		declarations.GET("/:id/form", handler.GenerateDeclarationForm)
+		declarations.GET("/:id/form", middleware.RequireRole("admin", "accountant"), handler.GenerateDeclarationForm)
+		declarations.GET("/:id/export", middleware.RequireRole("admin", "accountant"), handler.ExportDeclaration)
+		declarations.GET("/:id/exports/:export_id/verify", middleware.RequireRole("admin", "accountant"), handler.VerifyDeclarationExport)
 		declarations.POST("/:id/populate", middleware.RequireRole("admin", "accountant"), handler.PopulateDeclarationData)
 
+		// Declaration lifecycle: draft -> submitted -> paid, or cancelled (Accountant only)
+		declarations.POST("/:id/submit", middleware.RequireRole("admin", "accountant"), handler.SubmitDeclaration)
+		declarations.POST("/:id/cancel", middleware.RequireRole("admin", "accountant"), handler.CancelDeclaration)
+		declarations.POST("/:id/payments", middleware.RequireRole("admin", "accountant"), handler.RecordDeclarationPayment)
+		declarations.GET("/:id/payments", middleware.RequireRole("admin", "accountant"), handler.GetDeclarationPayments)
+
 		// CNAPS Declaration Generation (Accountant only)
 		declarations.GET("/cnaps/generate", middleware.RequireRole("admin", "accountant"), handler.GenerateCNAPSDeclaration)
 
@@ -33,12 +42,5 @@ func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
 
 		// IRSA Declaration Generation (Accountant only)
 		declarations.GET("/irsa/generate", middleware.RequireRole("admin", "accountant"), handler.GenerateIRSADeclaration)
-
-		// IRSA Tax Bracket Routes (Admin only)
-		declarations.POST("/irsa-brackets", middleware.RequireRole("admin"), handler.CreateIRSATaxBracket)
-		declarations.GET("/irsa-brackets", handler.ListIRSATaxBrackets)
-		declarations.GET("/irsa-brackets/:id", handler.GetIRSATaxBracketByID)
-		declarations.PUT("/irsa-brackets/:id", middleware.RequireRole("admin"), handler.UpdateIRSATaxBracket)
-		declarations.DELETE("/irsa-brackets/:id", middleware.RequireRole("admin"), handler.DeleteIRSATaxBracket)
 	}
 }
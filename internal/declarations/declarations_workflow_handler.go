@@ -0,0 +1,202 @@
+package declarations
+
+import (
+	"net/http"
+	"time"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SubmitDeclaration records that a draft declaration was filed with the authority (Accountant only)
+func (h *Handler) SubmitDeclaration(c *gin.Context) {
+	var input SubmitDeclarationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	h.transitionDeclaration(c, DeclarationStatusSubmitted, input.SubmissionReference, "")
+}
+
+// CancelDeclaration cancels a draft or submitted declaration without payments (Accountant only)
+func (h *Handler) CancelDeclaration(c *gin.Context) {
+	var input CancelDeclarationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.transitionDeclaration(c, DeclarationStatusCancelled, "", input.Reason)
+}
+
+// transitionDeclaration moves the declaration in the URL to the submitted or cancelled status
+func (h *Handler) transitionDeclaration(c *gin.Context, toStatus, reference, reason string) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid declaration ID"})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can update declarations"})
+		return
+	}
+
+	if _, err := h.repo.GetDeclarationByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Declaration not found"})
+		return
+	}
+
+	var declaration *MonthlyDeclaration
+	if toStatus == DeclarationStatusCancelled {
+		declaration, err = h.repo.CancelDeclaration(c.Request.Context(), id, userID, reason)
+	} else {
+		declaration, err = h.repo.SubmitDeclaration(c.Request.Context(), id, userID, reference)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, declaration)
+}
+
+// RecordDeclarationPayment records a payment made to the authority against a submitted declaration (Accountant only)
+func (h *Handler) RecordDeclarationPayment(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid declaration ID"})
+		return
+	}
+
+	var input RecordDeclarationPaymentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can record declaration payments"})
+		return
+	}
+
+	if _, err := h.repo.GetDeclarationByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Declaration not found"})
+		return
+	}
+
+	payment := &DeclarationPayment{
+		Amount:        input.Amount,
+		PaidOn:        time.Now(),
+		Reference:     input.Reference,
+		PaymentMethod: input.PaymentMethod,
+		Notes:         input.Notes,
+		RecordedBy:    userID,
+	}
+	if input.PaidOn != nil {
+		payment.PaidOn = *input.PaidOn
+	}
+
+	declaration, err := h.repo.RecordPayment(c.Request.Context(), id, payment)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"declaration": declaration,
+		"payment":     payment,
+	})
+}
+
+// GetDeclarationPayments retrieves a declaration's payments, balance and late penalty as of a date (default today)
+func (h *Handler) GetDeclarationPayments(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid declaration ID"})
+		return
+	}
+
+	asOf, ok := parseAsOf(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.GetDeclarationByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Declaration not found"})
+		return
+	}
+
+	status, err := h.repo.GetPaymentStatus(c.Request.Context(), id, asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// ListOverdueDeclarations lists declarations past their due date with the penalties they accrue (Accountant only)
+func (h *Handler) ListOverdueDeclarations(c *gin.Context) {
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can view overdue declarations"})
+		return
+	}
+
+	asOf, ok := parseAsOf(c)
+	if !ok {
+		return
+	}
+
+	overdue, err := h.repo.ListOverdueDeclarations(c.Request.Context(), asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"as_of":        dateOnly(asOf),
+		"declarations": overdue,
+		"total":        len(overdue),
+	})
+}
+
+// parseAsOf reads the optional as_of query date (YYYY-MM-DD), defaulting to today
+func parseAsOf(c *gin.Context) (time.Time, bool) {
+	asOfStr := c.Query("as_of")
+	if asOfStr == "" {
+		return time.Now(), true
+	}
+	asOf, err := time.Parse("2006-01-02", asOfStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid as_of format (use YYYY-MM-DD)"})
+		return time.Time{}, false
+	}
+	return asOf, true
+}
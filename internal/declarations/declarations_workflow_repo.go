@@ -0,0 +1,304 @@
+package declarations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockDeclaration loads a declaration and locks its row until the transaction ends
+func lockDeclaration(tx *gorm.DB, id uuid.UUID) (*MonthlyDeclaration, error) {
+	var declaration MonthlyDeclaration
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&declaration).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("declaration not found")
+		}
+		return nil, fmt.Errorf("get declaration: %w", err)
+	}
+	return &declaration, nil
+}
+
+// SubmitDeclaration records that a draft declaration was filed with the authority, locking its content.
+// A declaration with nothing to pay is settled as soon as it is filed.
+func (r *Repo) SubmitDeclaration(ctx context.Context, id uuid.UUID, actorID uuid.UUID, reference string) (*MonthlyDeclaration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var declaration *MonthlyDeclaration
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		declaration, err = lockDeclaration(tx, id)
+		if err != nil {
+			return err
+		}
+		if !canTransition(declaration.Status, DeclarationStatusSubmitted) {
+			return fmt.Errorf("cannot move declaration from %s to %s", declaration.Status, DeclarationStatusSubmitted)
+		}
+		if err := checkEmployeeIdentifiers(tx, declaration); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		declaration.Status = DeclarationStatusSubmitted
+		declaration.SubmittedAt = &now
+		declaration.SubmittedBy = &actorID
+		declaration.SubmissionReference = reference
+		if declaration.TotalAmountDue <= 0 {
+			declaration.Status = DeclarationStatusPaid
+			declaration.PaidAt = &now
+		}
+
+		declaration.UpdatedAt = now
+		if err := tx.Save(declaration).Error; err != nil {
+			return fmt.Errorf("update declaration: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return declaration, nil
+}
+
+// checkEmployeeIdentifiers refuses a declaration whose declared employees lack an identifier its authority needs,
+// naming the employees missing each one
+func checkEmployeeIdentifiers(tx *gorm.DB, declaration *MonthlyDeclaration) error {
+	var breakdown []EmployeeBreakdown
+	if err := json.Unmarshal([]byte(declaration.DeclarationData), &breakdown); err != nil {
+		return fmt.Errorf("parse declaration data: %w", err)
+	}
+	if len(breakdown) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(breakdown))
+	for i, entry := range breakdown {
+		ids[i] = entry.EmployeeID
+	}
+
+	var problems []string
+	for _, identifier := range requiredIdentifiers[declaration.DeclarationType] {
+		var names []string
+		if err := tx.Table("employees").
+			Where("id IN ? AND COALESCE("+identifier.column+", '') = ''", ids).
+			Order("last_name ASC, first_name ASC").
+			Pluck("first_name || ' ' || last_name", &names).Error; err != nil {
+			return fmt.Errorf("check employee %s: %w", identifier.label, err)
+		}
+		if len(names) > 0 {
+			problems = append(problems, fmt.Sprintf("%d employee(s) have no %s (%s)", len(names), identifier.label, strings.Join(names, ", ")))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("cannot submit declaration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// CancelDeclaration cancels a draft or submitted declaration, freeing its month for a new declaration.
+// Declarations with recorded payments cannot be cancelled.
+func (r *Repo) CancelDeclaration(ctx context.Context, id uuid.UUID, actorID uuid.UUID, reason string) (*MonthlyDeclaration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var declaration *MonthlyDeclaration
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		declaration, err = lockDeclaration(tx, id)
+		if err != nil {
+			return err
+		}
+		if !canTransition(declaration.Status, DeclarationStatusCancelled) {
+			return fmt.Errorf("cannot move declaration from %s to %s", declaration.Status, DeclarationStatusCancelled)
+		}
+		if declaration.AmountPaid > 0 {
+			return fmt.Errorf("declaration has %.2f in recorded payments and cannot be cancelled", declaration.AmountPaid)
+		}
+
+		now := time.Now()
+		declaration.Status = DeclarationStatusCancelled
+		declaration.CancelledAt = &now
+		declaration.CancelledBy = &actorID
+		declaration.CancellationReason = reason
+
+		declaration.UpdatedAt = now
+		if err := tx.Save(declaration).Error; err != nil {
+			return fmt.Errorf("update declaration: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return declaration, nil
+}
+
+// RecordPayment records a payment against a submitted declaration. Payments may not exceed the balance;
+// the declaration becomes paid, as of the payment date, once they cover the amount due.
+func (r *Repo) RecordPayment(ctx context.Context, id uuid.UUID, payment *DeclarationPayment) (*MonthlyDeclaration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	payment.PaidOn = dateOnly(payment.PaidOn)
+	if payment.PaidOn.After(dateOnly(time.Now())) {
+		return nil, fmt.Errorf("paid_on cannot be in the future")
+	}
+
+	var declaration *MonthlyDeclaration
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		declaration, err = lockDeclaration(tx, id)
+		if err != nil {
+			return err
+		}
+		if declaration.Status != DeclarationStatusSubmitted {
+			return fmt.Errorf("payments can only be recorded against submitted declarations, this one is %s", declaration.Status)
+		}
+		if payment.PaidOn.Before(dateOnly(declaration.DeclarationPeriodStart)) {
+			return fmt.Errorf("paid_on cannot be before the declared month")
+		}
+
+		balance := roundAmount(declaration.TotalAmountDue - declaration.AmountPaid)
+		if roundAmount(payment.Amount) > balance {
+			return fmt.Errorf("payment of %.2f exceeds the balance of %.2f", payment.Amount, balance)
+		}
+
+		var duplicates int64
+		if err := tx.Model(&DeclarationPayment{}).Where("declaration_id = ? AND reference = ?", declaration.ID, payment.Reference).
+			Count(&duplicates).Error; err != nil {
+			return fmt.Errorf("check payment reference: %w", err)
+		}
+		if duplicates > 0 {
+			return fmt.Errorf("payment %s is already recorded against this declaration", payment.Reference)
+		}
+
+		payment.DeclarationID = declaration.ID
+		payment.Amount = roundAmount(payment.Amount)
+		payment.CreatedAt = time.Now()
+		if err := tx.Create(payment).Error; err != nil {
+			return fmt.Errorf("create declaration payment: %w", err)
+		}
+
+		declaration.AmountPaid = roundAmount(declaration.AmountPaid + payment.Amount)
+		if declaration.AmountPaid >= roundAmount(declaration.TotalAmountDue) {
+			paidAt := payment.PaidOn
+			declaration.Status = DeclarationStatusPaid
+			declaration.PaidAt = &paidAt
+		}
+
+		declaration.UpdatedAt = time.Now()
+		if err := tx.Save(declaration).Error; err != nil {
+			return fmt.Errorf("update declaration: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return declaration, nil
+}
+
+// GetPaymentStatus lists a declaration's payments with its balance and the late penalty accrued as of a date
+func (r *Repo) GetPaymentStatus(ctx context.Context, id uuid.UUID, asOf time.Time) (*DeclarationPaymentStatus, error) {
+	declaration, err := r.GetDeclarationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	payments, err := r.getPayments(ctx, []uuid.UUID{declaration.ID})
+	if err != nil {
+		return nil, err
+	}
+	return r.paymentStatus(ctx, declaration, payments[declaration.ID], asOf)
+}
+
+// ListOverdueDeclarations lists the draft and submitted declarations whose due date passed before asOf,
+// oldest due first, with the penalty each has accrued
+func (r *Repo) ListOverdueDeclarations(ctx context.Context, asOf time.Time) ([]DeclarationPaymentStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var declarations []MonthlyDeclaration
+	if err := r.db.WithContext(ctx).
+		Where("status IN ? AND due_date < ?", []string{DeclarationStatusDraft, DeclarationStatusSubmitted}, dateOnly(asOf)).
+		Order("due_date ASC, declaration_type ASC").Find(&declarations).Error; err != nil {
+		return nil, fmt.Errorf("list overdue declarations: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(declarations))
+	for i, declaration := range declarations {
+		ids[i] = declaration.ID
+	}
+	payments, err := r.getPayments(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	overdue := make([]DeclarationPaymentStatus, 0, len(declarations))
+	for i := range declarations {
+		status, err := r.paymentStatus(ctx, &declarations[i], payments[declarations[i].ID], asOf)
+		if err != nil {
+			return nil, err
+		}
+		overdue = append(overdue, *status)
+	}
+	return overdue, nil
+}
+
+// getPayments retrieves the payments of declarations, oldest first, grouped by declaration
+func (r *Repo) getPayments(ctx context.Context, declarationIDs []uuid.UUID) (map[uuid.UUID][]DeclarationPayment, error) {
+	grouped := map[uuid.UUID][]DeclarationPayment{}
+	if len(declarationIDs) == 0 {
+		return grouped, nil
+	}
+
+	var payments []DeclarationPayment
+	if err := r.db.WithContext(ctx).Where("declaration_id IN ?", declarationIDs).
+		Order("paid_on ASC, created_at ASC").Find(&payments).Error; err != nil {
+		return nil, fmt.Errorf("get declaration payments: %w", err)
+	}
+	for _, payment := range payments {
+		grouped[payment.DeclarationID] = append(grouped[payment.DeclarationID], payment)
+	}
+	return grouped, nil
+}
+
+// paymentStatus summarizes a declaration's payments and penalty; cancelled declarations accrue none
+func (r *Repo) paymentStatus(ctx context.Context, declaration *MonthlyDeclaration, payments []DeclarationPayment, asOf time.Time) (*DeclarationPaymentStatus, error) {
+	if payments == nil {
+		payments = []DeclarationPayment{}
+	}
+
+	status := &DeclarationPaymentStatus{
+		DeclarationID:     declaration.ID,
+		DeclarationNumber: declaration.DeclarationNumber,
+		DeclarationType:   declaration.DeclarationType,
+		PeriodStart:       declaration.DeclarationPeriodStart,
+		Status:            declaration.Status,
+		DueDate:           declaration.DueDate,
+		AmountDue:         declaration.TotalAmountDue,
+		AmountPaid:        declaration.AmountPaid,
+		Balance:           roundAmount(declaration.TotalAmountDue - declaration.AmountPaid),
+		Payments:          payments,
+	}
+	if declaration.Status == DeclarationStatusCancelled {
+		return status, nil
+	}
+
+	status.Overdue = declaration.Status != DeclarationStatusPaid && dateOnly(asOf).After(dateOnly(declaration.DueDate))
+	rates, err := r.penaltyRatesAt(ctx, declaration.DeclarationType, declaration.DueDate)
+	if err != nil {
+		return nil, err
+	}
+	status.Penalty = computePenalty(declaration.TotalAmountDue, declaration.DueDate, payments, asOf, rates)
+	return status, nil
+}
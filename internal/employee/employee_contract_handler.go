@@ -0,0 +1,428 @@
+package employee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListContracts lists an employee's contracts with their amendments, newest first (HR/Admin only)
+func (h *Handler) ListContracts(c *gin.Context) {
+	employee, ok := h.contractEmployee(c)
+	if !ok {
+		return
+	}
+
+	contracts, err := h.repo.ListContracts(c.Request.Context(), employee.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list contracts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"contracts": contracts})
+}
+
+// CreateContract records the contract of an employee who has no active or upcoming one (HR/Admin only)
+func (h *Handler) CreateContract(c *gin.Context) {
+	employee, ok := h.contractEmployee(c)
+	if !ok {
+		return
+	}
+
+	var input CreateContractRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	contract := &Contract{
+		EmployeeID:       employee.ID,
+		ContractType:     input.ContractType,
+		StartDate:        input.StartDate,
+		EndDate:          input.EndDate,
+		ProbationEndDate: input.ProbationEndDate,
+		GrossSalary:      employee.GrossSalary,
+		PositionID:       employee.PositionID,
+		DocumentID:       optionalID(input.DocumentID),
+		SignedOn:         input.SignedOn,
+		Notes:            input.Notes,
+		CreatedBy:        userID,
+	}
+	if input.GrossSalary != nil {
+		contract.GrossSalary = *input.GrossSalary
+	}
+	if input.PositionID != nil {
+		contract.PositionID = optionalID(input.PositionID)
+	}
+	if err := h.checkContract(c.Request.Context(), employee, contract); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.CreateContract(c.Request.Context(), contract, dateOnly(time.Now())); err != nil {
+		if errors.Is(err, errActiveContract) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contract"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, contract)
+}
+
+// GetContract retrieves an employee's contract with its amendments (HR/Admin only)
+func (h *Handler) GetContract(c *gin.Context) {
+	employee, ok := h.contractEmployee(c)
+	if !ok {
+		return
+	}
+
+	contract, ok := h.employeeContract(c, employee.ID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, contract)
+}
+
+// UpdateContract updates a contract's signed document, signature date and notes (HR/Admin only)
+func (h *Handler) UpdateContract(c *gin.Context) {
+	employee, ok := h.contractEmployee(c)
+	if !ok {
+		return
+	}
+
+	var input UpdateContractRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contract, ok := h.employeeContract(c, employee.ID)
+	if !ok {
+		return
+	}
+
+	// Update fields if provided
+	if input.DocumentID != nil {
+		contract.DocumentID = optionalID(input.DocumentID)
+	}
+	if input.SignedOn != nil {
+		contract.SignedOn = input.SignedOn
+	}
+	if input.Notes != nil {
+		contract.Notes = *input.Notes
+	}
+
+	if err := checkContractTerms(contract); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.checkContractDocument(c.Request.Context(), employee.ID, contract.DocumentID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdateContract(c.Request.Context(), contract); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update contract"})
+		return
+	}
+
+	c.JSON(http.StatusOK, contract)
+}
+
+// RenewContract records the contract that renews an employee's active or last ended contract (HR/Admin only)
+func (h *Handler) RenewContract(c *gin.Context) {
+	employee, ok := h.contractEmployee(c)
+	if !ok {
+		return
+	}
+
+	var input RenewContractRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	renewed, ok := h.employeeContract(c, employee.ID)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	contract := &Contract{
+		EmployeeID:       employee.ID,
+		ContractType:     renewed.ContractType,
+		EndDate:          input.EndDate,
+		ProbationEndDate: input.ProbationEndDate,
+		GrossSalary:      renewed.GrossSalary,
+		PositionID:       renewed.PositionID,
+		DocumentID:       optionalID(input.DocumentID),
+		SignedOn:         input.SignedOn,
+		Notes:            input.Notes,
+		CreatedBy:        userID,
+	}
+	switch {
+	case input.StartDate != nil:
+		contract.StartDate = *input.StartDate
+	case renewed.EndDate != nil:
+		contract.StartDate = renewed.EndDate.AddDate(0, 0, 1)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date is required to renew a contract without an end_date"})
+		return
+	}
+	if input.ContractType != "" {
+		contract.ContractType = input.ContractType
+	}
+	if input.GrossSalary != nil {
+		contract.GrossSalary = *input.GrossSalary
+	}
+	if input.PositionID != nil {
+		contract.PositionID = optionalID(input.PositionID)
+	}
+
+	if err := h.checkContract(c.Request.Context(), employee, contract); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !contract.StartDate.After(renewed.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must be after the renewed contract's start_date"})
+		return
+	}
+	if renewed.Status == ContractStatusEnded && renewed.EndedOn != nil && !contract.StartDate.After(*renewed.EndedOn) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must be after the renewed contract ended"})
+		return
+	}
+
+	if err := h.repo.RenewContract(c.Request.Context(), renewed.ID, contract, dateOnly(time.Now())); err != nil {
+		if errors.Is(err, errActiveContract) || errors.Is(err, errContractRenewed) || errors.Is(err, errContractNotStarted) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to renew contract"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, contract)
+}
+
+// AddContractAmendment amends the terms of an employee's active contract from an effective date (HR/Admin only)
+func (h *Handler) AddContractAmendment(c *gin.Context) {
+	employee, ok := h.contractEmployee(c)
+	if !ok {
+		return
+	}
+
+	var input CreateAmendmentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.GrossSalary == nil && input.PositionID == nil && input.EndDate == nil && input.ProbationEndDate == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "an amendment must change gross_salary, position_id, end_date or probation_end_date"})
+		return
+	}
+
+	contract, ok := h.employeeContract(c, employee.ID)
+	if !ok {
+		return
+	}
+	if contract.Status != ContractStatusActive {
+		c.JSON(http.StatusConflict, gin.H{"error": errContractNotActive.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	amendment := &ContractAmendment{
+		EffectiveDate: dateOnly(input.EffectiveDate),
+		Reason:        strings.TrimSpace(input.Reason),
+		GrossSalary:   input.GrossSalary,
+		PositionID:    input.PositionID,
+		DocumentID:    optionalID(input.DocumentID),
+		CreatedBy:     userID,
+	}
+	today := dateOnly(time.Now())
+	if err := h.checkAmendment(c.Request.Context(), employee, contract, amendment, input, today); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	amended, err := h.repo.AddAmendment(c.Request.Context(), employee.ID, contract.ID, amendment, today)
+	if err != nil {
+		if errors.Is(err, errContractNotActive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to amend contract"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, amended)
+}
+
+// ListContractDeadlines lists the active contracts and probation periods that end soon (HR/Admin only)
+func (h *Handler) ListContractDeadlines(c *gin.Context) {
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" && userRole != "hr" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can view employee contracts"})
+		return
+	}
+
+	var query ContractDeadlinesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.Days == 0 {
+		query.Days = contractEndWarningDays
+	}
+
+	deadlines, err := h.repo.ListContractDeadlines(c.Request.Context(), time.Now(), query.Days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list contract deadlines"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deadlines": deadlines, "days": query.Days})
+}
+
+// contractEmployee checks the caller may manage employee contracts and returns the employee in the URL
+func (h *Handler) contractEmployee(c *gin.Context) (*Employee, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return nil, false
+	}
+
+	if _, err := middleware.GetUserID(c); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" && userRole != "hr" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can manage employee contracts"})
+		return nil, false
+	}
+
+	employee, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return nil, false
+	}
+	return employee, true
+}
+
+// employeeContract returns the employee's contract in the URL
+func (h *Handler) employeeContract(c *gin.Context, employeeID uuid.UUID) (*Contract, bool) {
+	contractID, err := uuid.Parse(c.Param("contract_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		return nil, false
+	}
+
+	contract, err := h.repo.GetContract(c.Request.Context(), employeeID, contractID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		return nil, false
+	}
+	return contract, true
+}
+
+// checkContract checks a new contract's dates, signed document, salary and position, and copies the position
+// title onto it
+func (h *Handler) checkContract(ctx context.Context, employee *Employee, contract *Contract) error {
+	if err := checkContractTerms(contract); err != nil {
+		return err
+	}
+	if err := h.checkContractDocument(ctx, employee.ID, contract.DocumentID); err != nil {
+		return err
+	}
+
+	terms := *employee
+	terms.GrossSalary, terms.PositionID = contract.GrossSalary, contract.PositionID
+	if err := h.repo.CheckAssignment(ctx, &terms, employee); err != nil {
+		return err
+	}
+	contract.Position = terms.Position
+	return nil
+}
+
+// checkAmendment checks an amendment against the contract it amends and copies the new position title onto it
+func (h *Handler) checkAmendment(ctx context.Context, employee *Employee, contract *Contract, amendment *ContractAmendment, input CreateAmendmentRequest, today time.Time) error {
+	amended := *contract
+	if input.EndDate != nil {
+		amended.EndDate = input.EndDate
+	}
+	if input.ProbationEndDate != nil {
+		amended.ProbationEndDate = input.ProbationEndDate
+	}
+	if err := checkContractTerms(&amended); err != nil {
+		return err
+	}
+	if input.EndDate != nil {
+		amendment.EndDate = amended.EndDate
+	}
+	if input.ProbationEndDate != nil {
+		amendment.ProbationEndDate = amended.ProbationEndDate
+	}
+
+	switch {
+	case amendment.EffectiveDate.Before(contract.StartDate):
+		return fmt.Errorf("effective_date cannot be before the contract's start_date")
+	case contract.EndDate != nil && amendment.EffectiveDate.After(*contract.EndDate):
+		return fmt.Errorf("effective_date cannot be after the contract's end_date")
+	case amendment.EndDate != nil && amendment.EndDate.Before(amendment.EffectiveDate):
+		return fmt.Errorf("end_date cannot be before effective_date")
+	case amendment.ProbationEndDate != nil && contract.ProbationEndDate != nil && contract.ProbationEndDate.Before(today):
+		return fmt.Errorf("the probation period has already ended")
+	}
+	if err := h.checkContractDocument(ctx, employee.ID, amendment.DocumentID); err != nil {
+		return err
+	}
+
+	if amendment.GrossSalary != nil || amendment.PositionID != nil {
+		terms := *employee
+		terms.GrossSalary, terms.PositionID = contract.GrossSalary, contract.PositionID
+		if amendment.GrossSalary != nil {
+			terms.GrossSalary = *amendment.GrossSalary
+		}
+		if amendment.PositionID != nil {
+			terms.PositionID = amendment.PositionID
+		}
+		if err := h.repo.CheckAssignment(ctx, &terms, employee); err != nil {
+			return err
+		}
+		if amendment.PositionID != nil {
+			amendment.Position = terms.Position
+		}
+	}
+	return nil
+}
+
+// checkContractDocument checks that a signed contract document belongs to the employee
+func (h *Handler) checkContractDocument(ctx context.Context, employeeID uuid.UUID, documentID *uuid.UUID) error {
+	if documentID == nil {
+		return nil
+	}
+	document, err := h.repo.GetDocument(ctx, employeeID, *documentID)
+	if err != nil {
+		return fmt.Errorf("document not found")
+	}
+	if document.Category != DocumentCategoryContract {
+		return fmt.Errorf("document_id must be a %s document", DocumentCategoryContract)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package employee
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-server/internal/notifications"
+
+	"gorm.io/gorm"
+)
+
+// ContractJob keeps employment contracts current every day: it starts contracts and renewals whose start date
+// has come, applies amendments that take effect, ends contracts past their end date, and tells HR once when a
+// contract or probation period nears its end and once when a contract ends without renewal
+type ContractJob struct {
+	repo          *Repo
+	notifications *notifications.Repo
+	location      *time.Location
+}
+
+// NewContractJob creates the daily contract job; loc is the company time zone that decides the day
+func NewContractJob(gormDB *gorm.DB, loc *time.Location) *ContractJob {
+	return &ContractJob{
+		repo:          NewRepo(gormDB),
+		notifications: notifications.NewRepo(gormDB),
+		location:      loc,
+	}
+}
+
+// Run starts due contracts, applies due amendments, ends lapsed contracts and notifies admins and HR of each
+// notice that is due
+func (j *ContractJob) Run(ctx context.Context) error {
+	now := time.Now().In(j.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if _, err := j.repo.StartContracts(ctx, today); err != nil {
+		return err
+	}
+	if _, err := j.repo.ApplyDueAmendments(ctx, today); err != nil {
+		return err
+	}
+	if err := j.repo.EndContracts(ctx, today); err != nil {
+		return err
+	}
+
+	lapsed, err := j.repo.ListLapsedContracts(ctx)
+	if err != nil {
+		return err
+	}
+	deadlines, err := j.repo.ListContractDeadlines(ctx, today, contractEndWarningDays)
+	if err != nil {
+		return err
+	}
+	if len(lapsed) == 0 && len(deadlines) == 0 {
+		return nil
+	}
+
+	recipients, err := j.repo.getHRAlertRecipients(ctx)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	notify := func(contract *ContractDeadline, column, notice string) {
+		if len(recipients) > 0 {
+			title, message := contractAlert(contract)
+			link := fmt.Sprintf("/employees/%s/contracts/%s", contract.EmployeeID, contract.ID)
+			if err := j.notifications.CreateForMultipleUsers(ctx, recipients, title, message, "warning", &link); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", contract.ID, err))
+				return
+			}
+		}
+		if err := j.repo.setContractNotice(ctx, contract.ID, column, notice); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", contract.ID, err))
+		}
+	}
+
+	for i := range lapsed {
+		notify(&lapsed[i], "end_notice", contractNoticeEnded)
+	}
+	for i := range deadlines {
+		deadline := &deadlines[i]
+		switch {
+		case deadline.Deadline == "contract_end" && deadline.EndNotice == "":
+			notify(deadline, "end_notice", contractNoticeExpiring)
+		case deadline.Deadline == "probation_end" && deadline.ProbationNotice == "" && deadline.DaysLeft <= probationEndWarningDays:
+			notify(deadline, "probation_notice", contractNoticeProbationEnding)
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("notify %d of %d contract deadlines failed: %s", len(failures), len(lapsed)+len(deadlines), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// contractAlert writes the title and message of a contract or probation notice
+func contractAlert(contract *ContractDeadline) (string, string) {
+	kind := contractTypeLabel(contract.ContractType)
+	date := contract.Date.Format("2006-01-02")
+
+	switch {
+	case contract.Status == ContractStatusEnded:
+		return fmt.Sprintf("Contract ended: %s", contract.EmployeeName),
+			fmt.Sprintf("%s's %s contract ended on %s without renewal. Renew it or record the end of employment.", contract.EmployeeName, kind, date)
+	case contract.Deadline == "probation_end" && contract.DaysLeft == 0:
+		return fmt.Sprintf("Probation ends today: %s", contract.EmployeeName),
+			fmt.Sprintf("%s's probation period ends today (%s). Confirm or end the employment.", contract.EmployeeName, date)
+	case contract.Deadline == "probation_end":
+		return fmt.Sprintf("Probation ending: %s", contract.EmployeeName),
+			fmt.Sprintf("%s's probation period ends on %s, in %d days. Confirm or end the employment.", contract.EmployeeName, date, contract.DaysLeft)
+	case contract.DaysLeft == 0:
+		return fmt.Sprintf("Contract ends today: %s", contract.EmployeeName),
+			fmt.Sprintf("%s's %s contract ends today (%s).", contract.EmployeeName, kind, date)
+	default:
+		return fmt.Sprintf("Contract expiring: %s", contract.EmployeeName),
+			fmt.Sprintf("%s's %s contract ends on %s, in %d days. Renew it or plan the end of employment.", contract.EmployeeName, kind, date, contract.DaysLeft)
+	}
+}
+
+// contractTypeLabel names a contract type in notices
+func contractTypeLabel(contractType string) string {
+	switch contractType {
+	case "fixed_term":
+		return "fixed-term"
+	case "intern":
+		return "internship"
+	default:
+		return contractType
+	}
+}
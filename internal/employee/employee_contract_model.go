@@ -0,0 +1,148 @@
+package employee
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Contract statuses
+const (
+	ContractStatusPending = "pending"
+	ContractStatusActive  = "active"
+	ContractStatusRenewed = "renewed"
+	ContractStatusEnded   = "ended"
+)
+
+// Notices sent about a contract's end and probation end
+const (
+	contractNoticeExpiring        = "expiring"
+	contractNoticeEnded           = "ended"
+	contractNoticeProbationEnding = "ending"
+)
+
+// contractEndWarningDays is how many days before a contract ends HR is told
+const contractEndWarningDays = 30
+
+// probationEndWarningDays is how many days before a probation period ends HR is told
+const probationEndWarningDays = 15
+
+// Contract is an employment contract of an employee. An employee has at most one active contract, and a contract
+// recorded before its start date is pending until then. A renewal is a new contract linked to the one it renews.
+// The terms are those of the contract as amended so far.
+type Contract struct {
+	ID               uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EmployeeID       uuid.UUID           `gorm:"type:uuid;not null;index" json:"employee_id"`
+	ContractType     string              `gorm:"type:varchar(50);not null" json:"contract_type"`
+	StartDate        time.Time           `gorm:"type:date;not null" json:"start_date"`
+	EndDate          *time.Time          `gorm:"type:date" json:"end_date,omitempty"`
+	ProbationEndDate *time.Time          `gorm:"type:date" json:"probation_end_date,omitempty"`
+	GrossSalary      float64             `gorm:"type:numeric(15,2);not null" json:"gross_salary"`
+	PositionID       *uuid.UUID          `gorm:"type:uuid" json:"position_id,omitempty"`
+	Position         string              `gorm:"type:varchar(100)" json:"position,omitempty"`
+	DocumentID       *uuid.UUID          `gorm:"type:uuid" json:"document_id,omitempty"`
+	SignedOn         *time.Time          `gorm:"type:date" json:"signed_on,omitempty"`
+	Status           string              `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	EndedOn          *time.Time          `gorm:"type:date" json:"ended_on,omitempty"`
+	RenewalOfID      *uuid.UUID          `gorm:"type:uuid" json:"renewal_of_id,omitempty"`
+	Notes            string              `gorm:"type:text" json:"notes,omitempty"`
+	EndNotice        string              `gorm:"type:varchar(20)" json:"-"`
+	ProbationNotice  string              `gorm:"type:varchar(20)" json:"-"`
+	CreatedBy        uuid.UUID           `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt        time.Time           `gorm:"default:now()" json:"created_at"`
+	UpdatedAt        time.Time           `gorm:"default:now()" json:"updated_at"`
+	Amendments       []ContractAmendment `gorm:"foreignKey:ContractID" json:"amendments,omitempty"`
+}
+
+// ContractAmendment changes the terms of a contract from its effective date. Only the terms it sets change.
+type ContractAmendment struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ContractID       uuid.UUID  `gorm:"type:uuid;not null" json:"contract_id"`
+	Number           int        `gorm:"not null" json:"number"`
+	EffectiveDate    time.Time  `gorm:"type:date;not null" json:"effective_date"`
+	Reason           string     `gorm:"type:text;not null" json:"reason"`
+	GrossSalary      *float64   `gorm:"type:numeric(15,2)" json:"gross_salary,omitempty"`
+	PositionID       *uuid.UUID `gorm:"type:uuid" json:"position_id,omitempty"`
+	Position         string     `gorm:"type:varchar(100)" json:"position,omitempty"`
+	EndDate          *time.Time `gorm:"type:date" json:"end_date,omitempty"`
+	ProbationEndDate *time.Time `gorm:"type:date" json:"probation_end_date,omitempty"`
+	DocumentID       *uuid.UUID `gorm:"type:uuid" json:"document_id,omitempty"`
+	AppliedAt        *time.Time `json:"applied_at,omitempty"`
+	CreatedBy        uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt        time.Time  `gorm:"default:now()" json:"created_at"`
+}
+
+// CreateContractRequest represents contract creation request. Salary and position default to the employee's.
+type CreateContractRequest struct {
+	ContractType     string     `json:"contract_type" binding:"required,oneof=permanent fixed_term intern contractor"`
+	StartDate        time.Time  `json:"start_date" binding:"required"`
+	EndDate          *time.Time `json:"end_date,omitempty"`
+	ProbationEndDate *time.Time `json:"probation_end_date,omitempty"`
+	GrossSalary      *float64   `json:"gross_salary,omitempty" binding:"omitempty,min=200000"`
+	PositionID       *uuid.UUID `json:"position_id,omitempty"`
+	DocumentID       *uuid.UUID `json:"document_id,omitempty"`
+	SignedOn         *time.Time `json:"signed_on,omitempty"`
+	Notes            string     `json:"notes,omitempty"`
+}
+
+// RenewContractRequest represents contract renewal request. The new contract starts the day after the renewed
+// one ends and keeps its type, salary and position unless given.
+type RenewContractRequest struct {
+	ContractType     string     `json:"contract_type,omitempty" binding:"omitempty,oneof=permanent fixed_term intern contractor"`
+	StartDate        *time.Time `json:"start_date,omitempty"`
+	EndDate          *time.Time `json:"end_date,omitempty"`
+	ProbationEndDate *time.Time `json:"probation_end_date,omitempty"`
+	GrossSalary      *float64   `json:"gross_salary,omitempty" binding:"omitempty,min=200000"`
+	PositionID       *uuid.UUID `json:"position_id,omitempty"`
+	DocumentID       *uuid.UUID `json:"document_id,omitempty"`
+	SignedOn         *time.Time `json:"signed_on,omitempty"`
+	Notes            string     `json:"notes,omitempty"`
+}
+
+// UpdateContractRequest represents contract update request; terms change through amendments
+type UpdateContractRequest struct {
+	DocumentID *uuid.UUID `json:"document_id,omitempty"`
+	SignedOn   *time.Time `json:"signed_on,omitempty"`
+	Notes      *string    `json:"notes,omitempty"`
+}
+
+// CreateAmendmentRequest represents contract amendment request; at least one term must be given
+type CreateAmendmentRequest struct {
+	EffectiveDate    time.Time  `json:"effective_date" binding:"required"`
+	Reason           string     `json:"reason" binding:"required"`
+	GrossSalary      *float64   `json:"gross_salary,omitempty" binding:"omitempty,min=200000"`
+	PositionID       *uuid.UUID `json:"position_id,omitempty"`
+	EndDate          *time.Time `json:"end_date,omitempty"`
+	ProbationEndDate *time.Time `json:"probation_end_date,omitempty"`
+	DocumentID       *uuid.UUID `json:"document_id,omitempty"`
+}
+
+// ContractDeadlinesQuery represents query parameters for listing contracts and probation periods about to end
+type ContractDeadlinesQuery struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=365"`
+}
+
+// ContractDeadline is an active contract whose end or probation end is near, with the employee it belongs to
+type ContractDeadline struct {
+	Contract
+	EmployeeName string    `json:"employee_name"`
+	Deadline     string    `json:"deadline"`
+	Date         time.Time `json:"date"`
+	DaysLeft     int       `json:"days_left"`
+}
+
+// EmployeeProfile is an employee with their contract history, newest first
+type EmployeeProfile struct {
+	*Employee
+	Contracts []Contract `json:"contracts"`
+}
+
+// TableName specifies the table name for Contract model
+func (Contract) TableName() string {
+	return "employee_contracts"
+}
+
+// TableName specifies the table name for ContractAmendment model
+func (ContractAmendment) TableName() string {
+	return "employee_contract_amendments"
+}
@@ -0,0 +1,508 @@
+package employee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errActiveContract is returned when a contract would become an employee's second active or upcoming contract
+var errActiveContract = errors.New("employee already has an active or upcoming contract: renew or amend it instead")
+
+// errContractRenewed is returned when renewing a contract that was already renewed
+var errContractRenewed = errors.New("contract was already renewed")
+
+// errContractNotStarted is returned when renewing a contract that has not started yet
+var errContractNotStarted = errors.New("contract has not started yet")
+
+// errContractNotActive is returned when amending a contract that is not active
+var errContractNotActive = errors.New("only active contracts can be amended")
+
+// errContractTerms is returned when editing the terms an active contract sets directly on the employee
+var errContractTerms = errors.New("employee has an active contract: change gross_salary, contract_type or position_id with a contract amendment")
+
+// CheckContractTerms refuses a change to an employee's gross salary, contract type or position while they have an
+// active contract: those terms come from the contract, whose next renewal or amendment would overwrite the change
+func (r *Repo) CheckContractTerms(ctx context.Context, e, previous *Employee) error {
+	if e.GrossSalary == previous.GrossSalary && e.ContractType == previous.ContractType && sameID(e.PositionID, previous.PositionID) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var contract Contract
+	err := r.db.WithContext(ctx).Select("id").Where("employee_id = ? AND status = ?", e.ID, ContractStatusActive).First(&contract).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get active contract: %w", err)
+	}
+	return fmt.Errorf("%w (POST /employees/%s/contracts/%s/amendments)", errContractTerms, e.ID, contract.ID)
+}
+
+// ListContracts retrieves an employee's contracts with their amendments, newest first
+func (r *Repo) ListContracts(ctx context.Context, employeeID uuid.UUID) ([]Contract, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var contracts []Contract
+	if err := r.db.WithContext(ctx).
+		Preload("Amendments", func(db *gorm.DB) *gorm.DB { return db.Order("number ASC") }).
+		Where("employee_id = ?", employeeID).Order("start_date DESC, created_at DESC").Find(&contracts).Error; err != nil {
+		return nil, fmt.Errorf("list contracts: %w", err)
+	}
+	return contracts, nil
+}
+
+// GetContract retrieves an employee's contract with its amendments
+func (r *Repo) GetContract(ctx context.Context, employeeID, contractID uuid.UUID) (*Contract, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var contract Contract
+	if err := r.db.WithContext(ctx).
+		Preload("Amendments", func(db *gorm.DB) *gorm.DB { return db.Order("number ASC") }).
+		Where("id = ? AND employee_id = ?", contractID, employeeID).First(&contract).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("contract not found")
+		}
+		return nil, fmt.Errorf("get contract: %w", err)
+	}
+	return &contract, nil
+}
+
+// CreateContract records a contract for an employee without an active or upcoming one. A contract starting
+// after today waits as pending; it becomes the active contract and gives the employee its type, salary and
+// position once it starts.
+func (r *Repo) CreateContract(ctx context.Context, contract *Contract, today time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockActiveContract(tx, contract.EmployeeID); err != nil {
+			return err
+		}
+		contract.Status = ContractStatusPending
+		if err := tx.Omit("Amendments").Create(contract).Error; err != nil {
+			return fmt.Errorf("create contract: %w", err)
+		}
+		if contract.StartDate.After(today) {
+			return nil
+		}
+		return activateContract(tx, contract)
+	})
+}
+
+// RenewContract records the contract that renews an active or ended contract. The renewed contract stays as it
+// is until its renewal starts; a renewal starting after today waits as pending.
+func (r *Repo) RenewContract(ctx context.Context, renewedID uuid.UUID, contract *Contract, today time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var renewed Contract
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND employee_id = ?", renewedID, contract.EmployeeID).First(&renewed).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("contract not found")
+			}
+			return fmt.Errorf("get contract: %w", err)
+		}
+
+		switch renewed.Status {
+		case ContractStatusRenewed:
+			return errContractRenewed
+		case ContractStatusPending:
+			return errContractNotStarted
+		case ContractStatusEnded:
+			if err := lockActiveContract(tx, contract.EmployeeID); err != nil {
+				return err
+			}
+		}
+
+		var renewals int64
+		if err := tx.Model(&Contract{}).Where("renewal_of_id = ?", renewed.ID).Count(&renewals).Error; err != nil {
+			return fmt.Errorf("count contract renewals: %w", err)
+		}
+		if renewals > 0 {
+			return errContractRenewed
+		}
+
+		contract.RenewalOfID = &renewed.ID
+		contract.Status = ContractStatusPending
+		if err := tx.Omit("Amendments").Create(contract).Error; err != nil {
+			return fmt.Errorf("create contract: %w", err)
+		}
+		if contract.StartDate.After(today) {
+			return nil
+		}
+		return activateContract(tx, contract)
+	})
+}
+
+// StartContracts activates the pending contracts whose start date has come, returning how many were started
+func (r *Repo) StartContracts(ctx context.Context, today time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var due []Contract
+	if err := r.db.WithContext(ctx).Where("status = ? AND start_date <= ?", ContractStatusPending, today).
+		Order("start_date ASC").Find(&due).Error; err != nil {
+		return 0, fmt.Errorf("get starting contracts: %w", err)
+	}
+
+	started := 0
+	for i := range due {
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var contract Contract
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", due[i].ID).First(&contract).Error; err != nil {
+				return fmt.Errorf("get contract: %w", err)
+			}
+			if contract.Status != ContractStatusPending {
+				return nil
+			}
+			return activateContract(tx, &contract)
+		})
+		if err != nil {
+			return started, fmt.Errorf("start contract %s: %w", due[i].ID, err)
+		}
+		started++
+	}
+	return started, nil
+}
+
+// UpdateContract updates a contract's signed document, signature date and notes
+func (r *Repo) UpdateContract(ctx context.Context, contract *Contract) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := r.db.WithContext(ctx).Model(contract).Updates(map[string]interface{}{
+		"document_id": contract.DocumentID,
+		"signed_on":   contract.SignedOn,
+		"notes":       contract.Notes,
+		"updated_at":  time.Now(),
+	}).Error; err != nil {
+		return fmt.Errorf("update contract: %w", err)
+	}
+	return nil
+}
+
+// AddAmendment records an amendment to an active contract, numbered after the previous ones. It takes effect
+// at once when its effective date is not after today, and otherwise on that date through the contract job.
+func (r *Repo) AddAmendment(ctx context.Context, employeeID, contractID uuid.UUID, amendment *ContractAmendment, today time.Time) (*Contract, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var contract Contract
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND employee_id = ?", contractID, employeeID).First(&contract).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("contract not found")
+			}
+			return fmt.Errorf("get contract: %w", err)
+		}
+		if contract.Status != ContractStatusActive {
+			return errContractNotActive
+		}
+
+		var last int
+		if err := tx.Model(&ContractAmendment{}).Where("contract_id = ?", contract.ID).
+			Select("COALESCE(MAX(number), 0)").Scan(&last).Error; err != nil {
+			return fmt.Errorf("number amendment: %w", err)
+		}
+		amendment.ContractID = contract.ID
+		amendment.Number = last + 1
+		if err := tx.Create(amendment).Error; err != nil {
+			return fmt.Errorf("create amendment: %w", err)
+		}
+
+		if amendment.EffectiveDate.After(today) {
+			return nil
+		}
+		return applyAmendment(tx, &contract, amendment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetContract(ctx, employeeID, contractID)
+}
+
+// ApplyDueAmendments applies the amendments of active contracts whose effective date has come, returning how
+// many were applied. Amendments of a contract renewed or ended before they took effect are never applied.
+func (r *Repo) ApplyDueAmendments(ctx context.Context, today time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var due []ContractAmendment
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN employee_contracts c ON c.id = employee_contract_amendments.contract_id AND c.status = ?", ContractStatusActive).
+		Where("employee_contract_amendments.applied_at IS NULL AND employee_contract_amendments.effective_date <= ?", today).
+		Order("employee_contract_amendments.effective_date ASC, employee_contract_amendments.number ASC").
+		Find(&due).Error; err != nil {
+		return 0, fmt.Errorf("get due amendments: %w", err)
+	}
+
+	applied := 0
+	for i := range due {
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var contract Contract
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", due[i].ContractID).First(&contract).Error; err != nil {
+				return fmt.Errorf("get contract: %w", err)
+			}
+			if contract.Status != ContractStatusActive {
+				return errContractNotActive
+			}
+			return applyAmendment(tx, &contract, &due[i])
+		})
+		if errors.Is(err, errContractNotActive) {
+			continue
+		}
+		if err != nil {
+			return applied, fmt.Errorf("apply amendment %s: %w", due[i].ID, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// EndContracts ends the active contracts of employees whose termination date has passed, on that date, and the
+// active contracts whose end date has passed without renewal. Only the latter are left to be notified.
+func (r *Repo) EndContracts(ctx context.Context, today time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE employee_contracts c
+			SET status = ?, ended_on = e.termination_date, end_notice = ?, updated_at = NOW()
+			FROM employees e
+			WHERE e.id = c.employee_id AND c.status = ? AND e.status = 'terminated'
+			  AND e.termination_date IS NOT NULL AND e.termination_date < ?`,
+			ContractStatusEnded, contractNoticeEnded, ContractStatusActive, today).Error; err != nil {
+			return fmt.Errorf("end contracts of terminated employees: %w", err)
+		}
+		if err := tx.Model(&Contract{}).Where("status = ? AND end_date < ?", ContractStatusActive, today).
+			Updates(map[string]interface{}{
+				"status":     ContractStatusEnded,
+				"ended_on":   gorm.Expr("end_date"),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return fmt.Errorf("end lapsed contracts: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListLapsedContracts retrieves the contracts that ended without a renewal, recorded or upcoming, and HR was not
+// yet told about
+func (r *Repo) ListLapsedContracts(ctx context.Context) ([]ContractDeadline, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var contracts []ContractDeadline
+	if err := r.db.WithContext(ctx).Table("employee_contracts c").
+		Select("c.*, e.first_name || ' ' || e.last_name AS employee_name, c.ended_on AS date").
+		Joins("JOIN employees e ON e.id = c.employee_id AND e.deleted_at IS NULL").
+		Where("c.status = ? AND COALESCE(c.end_notice, '') <> ?", ContractStatusEnded, contractNoticeEnded).
+		Where("NOT EXISTS (SELECT 1 FROM employee_contracts r WHERE r.renewal_of_id = c.id)").
+		Order("c.ended_on ASC").Scan(&contracts).Error; err != nil {
+		return nil, fmt.Errorf("list lapsed contracts: %w", err)
+	}
+	for i := range contracts {
+		contracts[i].Deadline = "contract_end"
+	}
+	return contracts, nil
+}
+
+// ListContractDeadlines retrieves the active contracts whose end or probation end falls within a number of days
+// of a date, soonest first
+func (r *Repo) ListContractDeadlines(ctx context.Context, asOf time.Time, days int) ([]ContractDeadline, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	from := dateOnly(asOf)
+	to := from.AddDate(0, 0, days)
+
+	var deadlines []ContractDeadline
+	for _, kind := range []struct {
+		name   string
+		column string
+	}{
+		{"contract_end", "end_date"},
+		{"probation_end", "probation_end_date"},
+	} {
+		db := r.db.WithContext(ctx).Table("employee_contracts c").
+			Select("c.*, e.first_name || ' ' || e.last_name AS employee_name, c."+kind.column+" AS date").
+			Joins("JOIN employees e ON e.id = c.employee_id AND e.deleted_at IS NULL").
+			Where("c.status = ? AND c."+kind.column+" BETWEEN ? AND ?", ContractStatusActive, from, to)
+		if kind.name == "contract_end" {
+			// A contract already renewed by an upcoming contract is not about to end
+			db = db.Where("NOT EXISTS (SELECT 1 FROM employee_contracts r WHERE r.renewal_of_id = c.id)")
+		}
+
+		var found []ContractDeadline
+		if err := db.Scan(&found).Error; err != nil {
+			return nil, fmt.Errorf("list contract deadlines: %w", err)
+		}
+		for i := range found {
+			found[i].Deadline = kind.name
+			found[i].DaysLeft = int(found[i].Date.Sub(from).Hours() / 24)
+		}
+		deadlines = append(deadlines, found...)
+	}
+
+	sort.SliceStable(deadlines, func(i, j int) bool {
+		if !deadlines[i].Date.Equal(deadlines[j].Date) {
+			return deadlines[i].Date.Before(deadlines[j].Date)
+		}
+		return deadlines[i].EmployeeName < deadlines[j].EmployeeName
+	})
+	return deadlines, nil
+}
+
+// setContractNotice records the last notice sent about a contract's end or probation end
+func (r *Repo) setContractNotice(ctx context.Context, contractID uuid.UUID, column, notice string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := r.db.WithContext(ctx).Model(&Contract{}).Where("id = ?", contractID).
+		Update(column, notice).Error; err != nil {
+		return fmt.Errorf("update contract notice: %w", err)
+	}
+	return nil
+}
+
+// lockActiveContract locks an employee and checks they have no active or upcoming contract
+func lockActiveContract(tx *gorm.DB, employeeID uuid.UUID) error {
+	var employee Employee
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("id = ?", employeeID).First(&employee).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("employee not found")
+		}
+		return fmt.Errorf("lock employee: %w", err)
+	}
+
+	var active int64
+	if err := tx.Model(&Contract{}).Where("employee_id = ? AND status IN ?", employeeID, []string{ContractStatusActive, ContractStatusPending}).
+		Count(&active).Error; err != nil {
+		return fmt.Errorf("count active contracts: %w", err)
+	}
+	if active > 0 {
+		return errActiveContract
+	}
+	return nil
+}
+
+// activateContract makes a pending contract the employee's active contract and gives the employee its terms.
+// The contract it renews is marked renewed, ending the day before if it was still active.
+func activateContract(tx *gorm.DB, contract *Contract) error {
+	if contract.RenewalOfID != nil {
+		var renewed Contract
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", *contract.RenewalOfID).First(&renewed).Error; err != nil {
+			return fmt.Errorf("get renewed contract: %w", err)
+		}
+		updates := map[string]interface{}{"status": ContractStatusRenewed, "updated_at": time.Now()}
+		if renewed.Status == ContractStatusActive {
+			updates["ended_on"] = contract.StartDate.AddDate(0, 0, -1)
+		}
+		if err := tx.Model(&renewed).Updates(updates).Error; err != nil {
+			return fmt.Errorf("update renewed contract: %w", err)
+		}
+	}
+
+	if err := tx.Model(&Contract{}).Where("id = ?", contract.ID).
+		Updates(map[string]interface{}{"status": ContractStatusActive, "updated_at": time.Now()}).Error; err != nil {
+		return fmt.Errorf("activate contract: %w", err)
+	}
+	contract.Status = ContractStatusActive
+	return syncContractTerms(tx, contract)
+}
+
+// applyAmendment changes a contract's terms to those an amendment sets, and the employee's too while the
+// contract is active
+func applyAmendment(tx *gorm.DB, contract *Contract, amendment *ContractAmendment) error {
+	now := time.Now()
+	updates := map[string]interface{}{"updated_at": now}
+	if amendment.GrossSalary != nil {
+		contract.GrossSalary = *amendment.GrossSalary
+		updates["gross_salary"] = contract.GrossSalary
+	}
+	if amendment.PositionID != nil {
+		contract.PositionID, contract.Position = amendment.PositionID, amendment.Position
+		updates["position_id"], updates["position"] = contract.PositionID, contract.Position
+	}
+	if amendment.EndDate != nil {
+		contract.EndDate = amendment.EndDate
+		updates["end_date"], updates["end_notice"] = contract.EndDate, ""
+	}
+	if amendment.ProbationEndDate != nil {
+		contract.ProbationEndDate = amendment.ProbationEndDate
+		updates["probation_end_date"], updates["probation_notice"] = contract.ProbationEndDate, ""
+	}
+	if err := tx.Model(&Contract{}).Where("id = ?", contract.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("amend contract: %w", err)
+	}
+
+	if contract.Status == ContractStatusActive {
+		if err := syncContractTerms(tx, contract); err != nil {
+			return err
+		}
+	}
+
+	amendment.AppliedAt = &now
+	if err := tx.Model(&ContractAmendment{}).Where("id = ?", amendment.ID).Update("applied_at", now).Error; err != nil {
+		return fmt.Errorf("mark amendment applied: %w", err)
+	}
+	return nil
+}
+
+// syncContractTerms gives an employee the type, salary and position of their active contract
+func syncContractTerms(tx *gorm.DB, contract *Contract) error {
+	if err := tx.Model(&Employee{}).Where("id = ?", contract.EmployeeID).Updates(map[string]interface{}{
+		"contract_type": contract.ContractType,
+		"gross_salary":  contract.GrossSalary,
+		"position_id":   contract.PositionID,
+		"position":      contract.Position,
+		"updated_at":    time.Now(),
+	}).Error; err != nil {
+		return fmt.Errorf("update employee contract terms: %w", err)
+	}
+	return nil
+}
+
+// checkContractTerms truncates a contract's dates to the day and checks they fit its type and each other
+func checkContractTerms(c *Contract) error {
+	c.StartDate = dateOnly(c.StartDate)
+	for _, date := range []**time.Time{&c.EndDate, &c.ProbationEndDate, &c.SignedOn} {
+		if *date != nil {
+			day := dateOnly(**date)
+			*date = &day
+		}
+	}
+
+	switch {
+	case c.ContractType == "permanent" && c.EndDate != nil:
+		return fmt.Errorf("permanent contracts have no end_date")
+	case (c.ContractType == "fixed_term" || c.ContractType == "intern") && c.EndDate == nil:
+		return fmt.Errorf("end_date is required for %s contracts", c.ContractType)
+	case c.EndDate != nil && c.EndDate.Before(c.StartDate):
+		return fmt.Errorf("end_date cannot be before start_date")
+	case c.ProbationEndDate != nil && !c.ProbationEndDate.After(c.StartDate):
+		return fmt.Errorf("probation_end_date must be after start_date")
+	case c.ProbationEndDate != nil && c.EndDate != nil && c.ProbationEndDate.After(*c.EndDate):
+		return fmt.Errorf("probation_end_date cannot be after end_date")
+	case c.SignedOn != nil && c.SignedOn.After(time.Now()):
+		return fmt.Errorf("signed_on cannot be in the future")
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package employee
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-server/internal/middleware"
+	"go-server/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListDocuments lists an employee's documents (HR/Admin only)
+func (h *Handler) ListDocuments(c *gin.Context) {
+	employeeID, ok := h.documentEmployee(c)
+	if !ok {
+		return
+	}
+
+	var query DocumentListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	documents, err := h.repo.ListDocuments(c.Request.Context(), employeeID, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list documents"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"documents": documents})
+}
+
+// UploadDocument uploads the first file of a new employee document (HR/Admin only)
+func (h *Handler) UploadDocument(c *gin.Context) {
+	employeeID, ok := h.documentEmployee(c)
+	if !ok {
+		return
+	}
+
+	var input UploadDocumentRequest
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	version, content, ok := readDocumentUpload(c, input.IssuedOn, input.ExpiresOn, input.Notes)
+	if !ok {
+		return
+	}
+	version.UploadedBy = userID
+
+	document := &Document{
+		EmployeeID: employeeID,
+		Category:   input.Category,
+		Title:      strings.TrimSpace(input.Title),
+		CreatedBy:  userID,
+	}
+	if err := h.repo.CreateDocument(c.Request.Context(), document, version, content); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store document"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// UploadDocumentVersion uploads a new file for an employee document, replacing its current version (HR/Admin only)
+func (h *Handler) UploadDocumentVersion(c *gin.Context) {
+	employeeID, ok := h.documentEmployee(c)
+	if !ok {
+		return
+	}
+	documentID, err := uuid.Parse(c.Param("document_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+		return
+	}
+
+	var input UploadDocumentVersionRequest
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.repo.GetDocument(c.Request.Context(), employeeID, documentID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	version, content, ok := readDocumentUpload(c, input.IssuedOn, input.ExpiresOn, input.Notes)
+	if !ok {
+		return
+	}
+	version.UploadedBy = userID
+
+	document, err := h.repo.AddDocumentVersion(c.Request.Context(), employeeID, documentID, version, content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store document"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// GetDocument retrieves an employee document with its version history (HR/Admin only)
+func (h *Handler) GetDocument(c *gin.Context) {
+	employeeID, ok := h.documentEmployee(c)
+	if !ok {
+		return
+	}
+	documentID, err := uuid.Parse(c.Param("document_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+		return
+	}
+
+	document, err := h.repo.GetDocument(c.Request.Context(), employeeID, documentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// DownloadDocument downloads the current or a given version of an employee document (HR/Admin only)
+func (h *Handler) DownloadDocument(c *gin.Context) {
+	employeeID, ok := h.documentEmployee(c)
+	if !ok {
+		return
+	}
+	documentID, err := uuid.Parse(c.Param("document_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+		return
+	}
+
+	var query DocumentDownloadQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.repo.GetDocument(c.Request.Context(), employeeID, documentID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+
+	version, content, err := h.repo.GetDocumentFile(c.Request.Context(), employeeID, documentID, query.Version)
+	if err != nil {
+		if errors.Is(err, errDocumentVersionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Document version not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read document file"})
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": version.FileName}))
+	c.Header("X-Checksum-SHA256", version.Checksum)
+	c.Data(http.StatusOK, version.ContentType, content)
+}
+
+// DeleteDocument deletes an employee document; its files are retained (HR/Admin only)
+func (h *Handler) DeleteDocument(c *gin.Context) {
+	employeeID, ok := h.documentEmployee(c)
+	if !ok {
+		return
+	}
+	documentID, err := uuid.Parse(c.Param("document_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+		return
+	}
+
+	if err := h.repo.DeleteDocument(c.Request.Context(), employeeID, documentID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Document deleted successfully"})
+}
+
+// ListExpiringDocuments lists the documents of current employees that expire soon or have expired (HR/Admin only)
+func (h *Handler) ListExpiringDocuments(c *gin.Context) {
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" && userRole != "hr" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can view employee documents"})
+		return
+	}
+
+	var query ExpiringDocumentsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.Days == 0 {
+		query.Days = documentExpiryWarningDays
+	}
+
+	documents, err := h.repo.ListExpiringDocuments(c.Request.Context(), time.Now(), query.Days, query.Category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list expiring documents"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"documents": documents, "days": query.Days})
+}
+
+// documentEmployee checks the caller may manage employee documents and that the employee in the URL exists
+func (h *Handler) documentEmployee(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return uuid.Nil, false
+	}
+
+	if _, err := middleware.GetUserID(c); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return uuid.Nil, false
+	}
+
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" && userRole != "hr" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can manage employee documents"})
+		return uuid.Nil, false
+	}
+
+	if _, err := h.repo.GetByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// readDocumentUpload reads the uploaded file and dates of a document version, answering the request itself when
+// they are invalid
+func readDocumentUpload(c *gin.Context, issuedOn, expiresOn, notes string) (*DocumentVersion, []byte, bool) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+		return nil, nil, false
+	}
+
+	content, contentType, err := storage.ReadUpload(header)
+	if err == storage.ErrTooLarge {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File exceeds the maximum upload size"})
+		return nil, nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return nil, nil, false
+	}
+	if _, ok := allowedDocumentTypes[contentType]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errUnsupportedDocumentType.Error()})
+		return nil, nil, false
+	}
+
+	version := &DocumentVersion{
+		FileName:    documentFileName(header.Filename),
+		ContentType: contentType,
+		Notes:       strings.TrimSpace(notes),
+	}
+	for _, field := range []struct {
+		name  string
+		value string
+		dest  **time.Time
+	}{
+		{"issued_on", issuedOn, &version.IssuedOn},
+		{"expires_on", expiresOn, &version.ExpiresOn},
+	} {
+		if field.value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", field.value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + field.name + " format, use YYYY-MM-DD"})
+			return nil, nil, false
+		}
+		*field.dest = &t
+	}
+	if err := checkDocumentDates(version.IssuedOn, version.ExpiresOn); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	return version, content, true
+}
+
+// documentFileName keeps the base name of an uploaded file, at most 255 bytes long
+func documentFileName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		name = "document"
+	}
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
@@ -0,0 +1,100 @@
+package employee
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-server/internal/notifications"
+
+	"gorm.io/gorm"
+)
+
+// DocumentExpiryJob tells HR every day about ID documents and medical certificates that expire within
+// documentExpiryWarningDays or have expired: once when the expiry comes within range and once when it passes
+type DocumentExpiryJob struct {
+	repo          *Repo
+	notifications *notifications.Repo
+	location      *time.Location
+}
+
+// NewDocumentExpiryJob creates the daily document expiry job; loc is the company time zone that decides the day
+func NewDocumentExpiryJob(gormDB *gorm.DB, loc *time.Location) *DocumentExpiryJob {
+	return &DocumentExpiryJob{
+		repo:          NewRepo(gormDB),
+		notifications: notifications.NewRepo(gormDB),
+		location:      loc,
+	}
+}
+
+// Run notifies admins and HR of each tracked document whose expiry notice is due
+func (j *DocumentExpiryJob) Run(ctx context.Context) error {
+	now := time.Now().In(j.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var documents []ExpiringDocument
+	for _, category := range expiryTrackedCategories {
+		found, err := j.repo.ListExpiringDocuments(ctx, today, documentExpiryWarningDays, category)
+		if err != nil {
+			return err
+		}
+		documents = append(documents, found...)
+	}
+	if len(documents) == 0 {
+		return nil
+	}
+
+	recipients, err := j.repo.getHRAlertRecipients(ctx)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, document := range documents {
+		notice := documentNoticeExpiring
+		if document.DaysLeft < 0 {
+			notice = documentNoticeExpired
+		}
+		if document.ExpiryNotice == notice || document.ExpiryNotice == documentNoticeExpired {
+			continue
+		}
+
+		if len(recipients) > 0 {
+			title, message := documentExpiryAlert(&document)
+			link := fmt.Sprintf("/employees/%s/documents/%s", document.EmployeeID, document.ID)
+			if err := j.notifications.CreateForMultipleUsers(ctx, recipients, title, message, "warning", &link); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", document.ID, err))
+				continue
+			}
+		}
+		if err := j.repo.setDocumentExpiryNotice(ctx, document.ID, notice); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", document.ID, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("notify %d of %d expiring documents failed: %s", len(failures), len(documents), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// documentExpiryAlert writes the title and message of an expiry notice
+func documentExpiryAlert(document *ExpiringDocument) (string, string) {
+	kind, noun := "ID document", "ID document"
+	if document.Category == DocumentCategoryMedicalCertificate {
+		kind, noun = "Medical certificate", "medical certificate"
+	}
+	expiresOn := document.ExpiresOn.Format("2006-01-02")
+
+	switch {
+	case document.DaysLeft < 0:
+		return fmt.Sprintf("%s expired: %s", kind, document.EmployeeName),
+			fmt.Sprintf("%s's %s \"%s\" expired on %s. Upload the renewed document.", document.EmployeeName, noun, document.Title, expiresOn)
+	case document.DaysLeft == 0:
+		return fmt.Sprintf("%s expires today: %s", kind, document.EmployeeName),
+			fmt.Sprintf("%s's %s \"%s\" expires today (%s).", document.EmployeeName, noun, document.Title, expiresOn)
+	default:
+		return fmt.Sprintf("%s expiring: %s", kind, document.EmployeeName),
+			fmt.Sprintf("%s's %s \"%s\" expires on %s, in %d days.", document.EmployeeName, noun, document.Title, expiresOn, document.DaysLeft)
+	}
+}
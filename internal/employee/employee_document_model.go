@@ -0,0 +1,120 @@
+package employee
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Document categories
+const (
+	DocumentCategoryContract           = "contract"
+	DocumentCategoryDiploma            = "diploma"
+	DocumentCategoryIDDocument         = "id_document"
+	DocumentCategoryMedicalCertificate = "medical_certificate"
+	DocumentCategoryOther              = "other"
+)
+
+// Expiry notices sent for a document's current version
+const (
+	documentNoticeExpiring = "expiring"
+	documentNoticeExpired  = "expired"
+)
+
+// documentExpiryWarningDays is how many days before an ID or medical certificate expires HR is told
+const documentExpiryWarningDays = 30
+
+// expiryTrackedCategories are the categories HR is notified about before their documents expire
+var expiryTrackedCategories = []string{DocumentCategoryIDDocument, DocumentCategoryMedicalCertificate}
+
+// allowedDocumentTypes are the file types accepted for employee documents, with the extension they are stored under
+var allowedDocumentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// Document is a categorized employee document. Each upload of a new file adds a version; the document's dates
+// are those of its current version.
+type Document struct {
+	ID             uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EmployeeID     uuid.UUID         `gorm:"type:uuid;not null;index" json:"employee_id"`
+	Category       string            `gorm:"type:varchar(30);not null" json:"category"`
+	Title          string            `gorm:"type:varchar(200);not null" json:"title"`
+	CurrentVersion int               `gorm:"not null;default:1" json:"current_version"`
+	IssuedOn       *time.Time        `gorm:"type:date" json:"issued_on,omitempty"`
+	ExpiresOn      *time.Time        `gorm:"type:date" json:"expires_on,omitempty"`
+	ExpiryNotice   string            `gorm:"type:varchar(20)" json:"-"`
+	CreatedBy      uuid.UUID         `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt      time.Time         `gorm:"default:now()" json:"created_at"`
+	UpdatedAt      time.Time         `gorm:"default:now()" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt    `gorm:"index" json:"-"`
+	Versions       []DocumentVersion `gorm:"foreignKey:DocumentID" json:"versions,omitempty"`
+}
+
+// DocumentVersion is one uploaded file of a document
+type DocumentVersion struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	DocumentID  uuid.UUID  `gorm:"type:uuid;not null" json:"document_id"`
+	Version     int        `gorm:"not null" json:"version"`
+	FileName    string     `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType string     `gorm:"type:varchar(100);not null" json:"content_type"`
+	SizeBytes   int64      `gorm:"not null" json:"size_bytes"`
+	Checksum    string     `gorm:"type:char(64);not null" json:"checksum"`
+	StorageKey  string     `gorm:"type:varchar(255);not null" json:"-"`
+	IssuedOn    *time.Time `gorm:"type:date" json:"issued_on,omitempty"`
+	ExpiresOn   *time.Time `gorm:"type:date" json:"expires_on,omitempty"`
+	Notes       string     `gorm:"type:text" json:"notes,omitempty"`
+	UploadedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"uploaded_by"`
+	UploadedAt  time.Time  `gorm:"default:now()" json:"uploaded_at"`
+}
+
+// UploadDocumentRequest represents the form fields sent with a new document's first file
+type UploadDocumentRequest struct {
+	Category  string `form:"category" binding:"required,oneof=contract diploma id_document medical_certificate other"`
+	Title     string `form:"title" binding:"required,max=200"`
+	IssuedOn  string `form:"issued_on"`
+	ExpiresOn string `form:"expires_on"`
+	Notes     string `form:"notes"`
+}
+
+// UploadDocumentVersionRequest represents the form fields sent with a new file for an existing document
+type UploadDocumentVersionRequest struct {
+	IssuedOn  string `form:"issued_on"`
+	ExpiresOn string `form:"expires_on"`
+	Notes     string `form:"notes"`
+}
+
+// DocumentListQuery represents query parameters for listing an employee's documents
+type DocumentListQuery struct {
+	Category string `form:"category" binding:"omitempty,oneof=contract diploma id_document medical_certificate other"`
+}
+
+// DocumentDownloadQuery selects the version of a document to download, the current one by default
+type DocumentDownloadQuery struct {
+	Version int `form:"version" binding:"omitempty,min=1"`
+}
+
+// ExpiringDocumentsQuery represents query parameters for listing documents about to expire
+type ExpiringDocumentsQuery struct {
+	Days     int    `form:"days" binding:"omitempty,min=1,max=365"`
+	Category string `form:"category" binding:"omitempty,oneof=contract diploma id_document medical_certificate other"`
+}
+
+// ExpiringDocument is a document that expires soon or has expired, with the employee it belongs to
+type ExpiringDocument struct {
+	Document
+	EmployeeName string `json:"employee_name"`
+	DaysLeft     int    `json:"days_left"`
+}
+
+// TableName specifies the table name for Document model
+func (Document) TableName() string {
+	return "employee_documents"
+}
+
+// TableName specifies the table name for DocumentVersion model
+func (DocumentVersion) TableName() string {
+	return "employee_document_versions"
+}
@@ -0,0 +1,295 @@
+package employee
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-server/internal/auth"
+	"go-server/internal/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errUnsupportedDocumentType is returned for uploads that are not PDF, JPEG or PNG files
+var errUnsupportedDocumentType = errors.New("unsupported file type: upload a PDF, JPEG or PNG file")
+
+// errDocumentVersionNotFound is returned when a document has no version with the requested number
+var errDocumentVersionNotFound = errors.New("document version not found")
+
+// CreateDocument stores the first file of a new employee document and records the document
+func (r *Repo) CreateDocument(ctx context.Context, document *Document, version *DocumentVersion, content []byte) error {
+	if err := checkDocumentDates(version.IssuedOn, version.ExpiresOn); err != nil {
+		return err
+	}
+	files, err := storage.Default()
+	if err != nil {
+		return fmt.Errorf("open file storage: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	document.ID = uuid.New()
+	document.CurrentVersion = 1
+	document.IssuedOn = version.IssuedOn
+	document.ExpiresOn = version.ExpiresOn
+	version.Version = 1
+	if err := storeDocumentFile(ctx, files, document, version, content); err != nil {
+		return err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Versions").Create(document).Error; err != nil {
+			return fmt.Errorf("create document: %w", err)
+		}
+		version.DocumentID = document.ID
+		if err := tx.Create(version).Error; err != nil {
+			return fmt.Errorf("create document version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		discardDocumentFile(files, version.StorageKey)
+		return err
+	}
+	document.Versions = []DocumentVersion{*version}
+	return nil
+}
+
+// AddDocumentVersion stores a new file for a document, which becomes its current version. The document takes
+// the new version's dates, and expiry notices start over for them.
+func (r *Repo) AddDocumentVersion(ctx context.Context, employeeID, documentID uuid.UUID, version *DocumentVersion, content []byte) (*Document, error) {
+	if err := checkDocumentDates(version.IssuedOn, version.ExpiresOn); err != nil {
+		return nil, err
+	}
+	files, err := storage.Default()
+	if err != nil {
+		return nil, fmt.Errorf("open file storage: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	stored := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var document Document
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND employee_id = ?", documentID, employeeID).First(&document).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("document not found")
+			}
+			return fmt.Errorf("get document: %w", err)
+		}
+
+		version.DocumentID = document.ID
+		version.Version = document.CurrentVersion + 1
+		if err := storeDocumentFile(ctx, files, &document, version, content); err != nil {
+			return err
+		}
+		stored = true
+
+		if err := tx.Create(version).Error; err != nil {
+			return fmt.Errorf("create document version: %w", err)
+		}
+		if err := tx.Model(&document).Updates(map[string]interface{}{
+			"current_version": version.Version,
+			"issued_on":       version.IssuedOn,
+			"expires_on":      version.ExpiresOn,
+			"expiry_notice":   "",
+			"updated_at":      time.Now(),
+		}).Error; err != nil {
+			return fmt.Errorf("update document: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if stored {
+			discardDocumentFile(files, version.StorageKey)
+		}
+		return nil, err
+	}
+	return r.GetDocument(ctx, employeeID, documentID)
+}
+
+// GetDocument retrieves an employee document with all its versions, newest first
+func (r *Repo) GetDocument(ctx context.Context, employeeID, documentID uuid.UUID) (*Document, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var document Document
+	if err := r.db.WithContext(ctx).
+		Preload("Versions", func(db *gorm.DB) *gorm.DB { return db.Order("version DESC") }).
+		Where("id = ? AND employee_id = ?", documentID, employeeID).First(&document).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("document not found")
+		}
+		return nil, fmt.Errorf("get document: %w", err)
+	}
+	return &document, nil
+}
+
+// ListDocuments retrieves an employee's documents by category and title, without their versions
+func (r *Repo) ListDocuments(ctx context.Context, employeeID uuid.UUID, query DocumentListQuery) ([]Document, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Where("employee_id = ?", employeeID)
+	if query.Category != "" {
+		db = db.Where("category = ?", query.Category)
+	}
+
+	var documents []Document
+	if err := db.Order("category ASC, title ASC").Find(&documents).Error; err != nil {
+		return nil, fmt.Errorf("list documents: %w", err)
+	}
+	return documents, nil
+}
+
+// GetDocumentFile retrieves a version of a document, the current one when version is 0, with its file
+func (r *Repo) GetDocumentFile(ctx context.Context, employeeID, documentID uuid.UUID, version int) (*DocumentVersion, []byte, error) {
+	document, err := r.GetDocument(ctx, employeeID, documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version == 0 {
+		version = document.CurrentVersion
+	}
+
+	var found *DocumentVersion
+	for i := range document.Versions {
+		if document.Versions[i].Version == version {
+			found = &document.Versions[i]
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil, errDocumentVersionNotFound
+	}
+
+	files, err := storage.Default()
+	if err != nil {
+		return nil, nil, fmt.Errorf("open file storage: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	content, err := files.Get(ctx, found.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read document file: %w", err)
+	}
+	return found, content, nil
+}
+
+// DeleteDocument soft deletes an employee document. Its files are kept, as employee records must be retained.
+func (r *Repo) DeleteDocument(ctx context.Context, employeeID, documentID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Where("id = ? AND employee_id = ?", documentID, employeeID).Delete(&Document{})
+	if result.Error != nil {
+		return fmt.Errorf("delete document: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("document not found")
+	}
+	return nil
+}
+
+// ListExpiringDocuments retrieves the documents of current employees that expire within a number of days of a
+// date, or have already expired, soonest first
+func (r *Repo) ListExpiringDocuments(ctx context.Context, asOf time.Time, days int, category string) ([]ExpiringDocument, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Table("employee_documents d").
+		Select("d.*, e.first_name || ' ' || e.last_name AS employee_name").
+		Joins("JOIN employees e ON e.id = d.employee_id AND e.deleted_at IS NULL").
+		Where("d.deleted_at IS NULL AND e.status <> ?", "terminated").
+		Where("d.expires_on IS NOT NULL AND d.expires_on <= ?", dateOnly(asOf).AddDate(0, 0, days))
+	if category != "" {
+		db = db.Where("d.category = ?", category)
+	}
+
+	var documents []ExpiringDocument
+	if err := db.Order("d.expires_on ASC, employee_name ASC").Scan(&documents).Error; err != nil {
+		return nil, fmt.Errorf("list expiring documents: %w", err)
+	}
+	for i := range documents {
+		documents[i].DaysLeft = int(documents[i].ExpiresOn.Sub(dateOnly(asOf)).Hours() / 24)
+	}
+	return documents, nil
+}
+
+// setDocumentExpiryNotice records the last expiry notice sent for a document's current version
+func (r *Repo) setDocumentExpiryNotice(ctx context.Context, documentID uuid.UUID, notice string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := r.db.WithContext(ctx).Model(&Document{}).Where("id = ?", documentID).
+		Update("expiry_notice", notice).Error; err != nil {
+		return fmt.Errorf("update document expiry notice: %w", err)
+	}
+	return nil
+}
+
+// getHRAlertRecipients retrieves the active admin and HR users told about expiring documents and contracts
+func (r *Repo) getHRAlertRecipients(ctx context.Context) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&auth.User{}).Where("role IN ? AND is_active = ?", []string{"admin", "hr"}, true).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("get HR alert recipients: %w", err)
+	}
+	return ids, nil
+}
+
+// storeDocumentFile checks an upload's type, fills in the version's file details and stores the file
+func storeDocumentFile(ctx context.Context, files storage.Storage, document *Document, version *DocumentVersion, content []byte) error {
+	ext, ok := allowedDocumentTypes[version.ContentType]
+	if !ok {
+		return errUnsupportedDocumentType
+	}
+
+	sum := sha256.Sum256(content)
+	version.Checksum = hex.EncodeToString(sum[:])
+	version.SizeBytes = int64(len(content))
+	version.StorageKey = fmt.Sprintf("employees/%s/documents/%s/v%d%s", document.EmployeeID, document.ID, version.Version, ext)
+	if err := files.Put(ctx, version.StorageKey, content, version.ContentType); err != nil {
+		return fmt.Errorf("store document file: %w", err)
+	}
+	return nil
+}
+
+// discardDocumentFile removes a file stored for a document version that could not be recorded
+func discardDocumentFile(files storage.Storage, key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := files.Delete(ctx, key); err != nil {
+		log.Printf("employee documents: failed to remove unrecorded file %s: %v", key, err)
+	}
+}
+
+// checkDocumentDates checks that a document does not expire before it was issued
+func checkDocumentDates(issuedOn, expiresOn *time.Time) error {
+	if issuedOn != nil && issuedOn.After(time.Now()) {
+		return fmt.Errorf("issued_on cannot be in the future")
+	}
+	if issuedOn != nil && expiresOn != nil && expiresOn.Before(*issuedOn) {
+		return fmt.Errorf("expires_on cannot be before issued_on")
+	}
+	return nil
+}
+
+// dateOnly truncates a time to its calendar date in UTC
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
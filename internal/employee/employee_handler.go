@@ -1,6 +1,7 @@
 package employee
 
 import (
+	"errors"
 	"net/http"
 
 	"go-server/internal/middleware"
@@ -35,11 +36,17 @@ func (h *Handler) Create(c *gin.Context) {
 		Gender:                input.Gender,
 		Nationality:           input.Nationality,
 		NationalID:            input.NationalID,
-		Position:              input.Position,
-		Department:            input.Department,
+		CINIssuedOn:           input.CINIssuedOn,
+		CINIssuedAt:           input.CINIssuedAt,
+		NIF:                   input.NIF,
+		CNAPSNumber:           input.CNAPSNumber,
+		OSTIENumber:           input.OSTIENumber,
+		PositionID:            optionalID(input.PositionID),
+		DepartmentID:          optionalID(input.DepartmentID),
 		HireDate:              input.HireDate,
 		ContractType:          input.ContractType,
 		GrossSalary:           input.GrossSalary,
+		Dependents:            input.Dependents,
 		Status:                "active",
 		Address:               input.Address,
 		Phone:                 input.Phone,
@@ -48,6 +55,19 @@ func (h *Handler) Create(c *gin.Context) {
 		ManagerID:             input.ManagerID,
 	}
 
+	if err := h.repo.CheckIdentifiers(c.Request.Context(), employee, nil); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.repo.CheckManager(c.Request.Context(), employee, nil); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.repo.CheckAssignment(c.Request.Context(), employee, nil); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := h.repo.Create(c.Request.Context(), employee); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create employee"})
 		return
@@ -56,7 +76,7 @@ func (h *Handler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, employee)
 }
 
-// GetByID retrieves an employee by ID
+// GetByID retrieves an employee by ID, with their contract history for HR and admins
 func (h *Handler) GetByID(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
@@ -79,6 +99,17 @@ func (h *Handler) GetByID(c *gin.Context) {
 		_ = userID
 	}
 
+	// HR and admins also see the contract history
+	if userRole == "admin" || userRole == "hr" {
+		contracts, err := h.repo.ListContracts(c.Request.Context(), id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get employee contracts"})
+			return
+		}
+		c.JSON(http.StatusOK, EmployeeProfile{Employee: employee, Contracts: contracts})
+		return
+	}
+
 	c.JSON(http.StatusOK, employee)
 }
 
@@ -103,6 +134,8 @@ func (h *Handler) Update(c *gin.Context) {
 		return
 	}
 
+	stored := *employee
+
 	// Update fields if provided
 	if input.FirstName != nil {
 		employee.FirstName = *input.FirstName
@@ -122,11 +155,26 @@ func (h *Handler) Update(c *gin.Context) {
 	if input.NationalID != nil {
 		employee.NationalID = *input.NationalID
 	}
-	if input.Position != nil {
-		employee.Position = *input.Position
+	if input.CINIssuedOn != nil {
+		employee.CINIssuedOn = input.CINIssuedOn
+	}
+	if input.CINIssuedAt != nil {
+		employee.CINIssuedAt = *input.CINIssuedAt
+	}
+	if input.NIF != nil {
+		employee.NIF = *input.NIF
+	}
+	if input.CNAPSNumber != nil {
+		employee.CNAPSNumber = *input.CNAPSNumber
+	}
+	if input.OSTIENumber != nil {
+		employee.OSTIENumber = *input.OSTIENumber
+	}
+	if input.PositionID != nil {
+		employee.PositionID = optionalID(input.PositionID)
 	}
-	if input.Department != nil {
-		employee.Department = *input.Department
+	if input.DepartmentID != nil {
+		employee.DepartmentID = optionalID(input.DepartmentID)
 	}
 	if input.ContractType != nil {
 		employee.ContractType = *input.ContractType
@@ -135,8 +183,26 @@ func (h *Handler) Update(c *gin.Context) {
 		employee.GrossSalary = *input.GrossSalary
 	}
 	if input.Status != nil {
+		// Reinstating an employee clears the exit date unless a new one is given
+		if employee.Status == "terminated" && *input.Status != "terminated" && input.TerminationDate == nil {
+			employee.TerminationDate = nil
+		}
 		employee.Status = *input.Status
 	}
+	if input.TerminationDate != nil {
+		employee.TerminationDate = input.TerminationDate
+	}
+	if employee.Status == "terminated" && employee.TerminationDate == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "termination_date is required for terminated employees"})
+		return
+	}
+	if employee.TerminationDate != nil && employee.TerminationDate.Before(employee.HireDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "termination_date cannot be before hire_date"})
+		return
+	}
+	if input.Dependents != nil {
+		employee.Dependents = *input.Dependents
+	}
 	if input.Address != nil {
 		employee.Address = *input.Address
 	}
@@ -153,6 +219,27 @@ func (h *Handler) Update(c *gin.Context) {
 		employee.ManagerID = input.ManagerID
 	}
 
+	if err := h.repo.CheckContractTerms(c.Request.Context(), employee, &stored); err != nil {
+		if errors.Is(err, errContractTerms) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update employee"})
+		return
+	}
+	if err := h.repo.CheckIdentifiers(c.Request.Context(), employee, &stored); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.repo.CheckManager(c.Request.Context(), employee, &stored); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.repo.CheckAssignment(c.Request.Context(), employee, &stored); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := h.repo.Update(c.Request.Context(), employee); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update employee"})
 		return
@@ -200,7 +287,7 @@ func (h *Handler) List(c *gin.Context) {
 	})
 }
 
-// GetDepartments retrieves all unique departments
+// GetDepartments retrieves the names of active departments
 func (h *Handler) GetDepartments(c *gin.Context) {
 	departments, err := h.repo.GetDepartments(c.Request.Context())
 	if err != nil {
@@ -211,7 +298,7 @@ func (h *Handler) GetDepartments(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"departments": departments})
 }
 
-// GetPositions retrieves all unique positions
+// GetPositions retrieves the titles of active positions
 func (h *Handler) GetPositions(c *gin.Context) {
 	positions, err := h.repo.GetPositions(c.Request.Context())
 	if err != nil {
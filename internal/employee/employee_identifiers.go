@@ -0,0 +1,178 @@
+package employee
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// cinPattern is a Malagasy national identity card number: 12 digits, printed in groups of three
+	cinPattern = regexp.MustCompile(`^[1-6][0-9]{11}$`)
+	// nifPattern is a tax identification number issued by the DGI: 10 digits
+	nifPattern = regexp.MustCompile(`^[0-9]{10}$`)
+	// cnapsPattern is a worker's CNAPS registration number
+	cnapsPattern = regexp.MustCompile(`^[0-9]{8,15}$`)
+	// ostiePattern is an affiliation number with an inter-company medical service (OSTIE, OSIE, ...)
+	ostiePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9/-]{3,29}$`)
+)
+
+// identifierSeparators are the characters people type or paste between groups of digits
+var identifierSeparators = strings.NewReplacer(" ", "", ".", "", "-", "", "\u00a0", "")
+
+// normalizeCIN checks a CIN number and returns its 12 digits. An empty number is allowed.
+func normalizeCIN(s string) (string, error) {
+	cin := identifierSeparators.Replace(strings.TrimSpace(s))
+	if cin == "" {
+		return "", nil
+	}
+	if !cinPattern.MatchString(cin) {
+		return "", fmt.Errorf("national_id must be a 12-digit CIN number")
+	}
+	return cin, nil
+}
+
+// normalizeNIF checks a NIF and returns its 10 digits. An empty NIF is allowed.
+func normalizeNIF(s string) (string, error) {
+	nif := identifierSeparators.Replace(strings.TrimSpace(s))
+	if nif == "" {
+		return "", nil
+	}
+	if !nifPattern.MatchString(nif) {
+		return "", fmt.Errorf("nif must be a 10-digit tax identification number")
+	}
+	return nif, nil
+}
+
+// normalizeCNAPSNumber checks a CNAPS registration number and returns its digits. An empty number is allowed.
+func normalizeCNAPSNumber(s string) (string, error) {
+	number := identifierSeparators.Replace(strings.TrimSpace(s))
+	if number == "" {
+		return "", nil
+	}
+	if !cnapsPattern.MatchString(number) {
+		return "", fmt.Errorf("cnaps_number must have 8 to 15 digits")
+	}
+	return number, nil
+}
+
+// normalizeOSTIENumber checks a medical service affiliation number and returns it in upper case without spaces.
+// An empty number is allowed.
+func normalizeOSTIENumber(s string) (string, error) {
+	number := strings.ToUpper(strings.Join(strings.Fields(s), ""))
+	if number == "" {
+		return "", nil
+	}
+	if !ostiePattern.MatchString(number) {
+		return "", fmt.Errorf("ostie_number must have 4 to 30 letters, digits, '/' or '-'")
+	}
+	return number, nil
+}
+
+// isMalagasy reports whether a nationality is Malagasy, the default, whose national ID is a CIN
+func isMalagasy(nationality string) bool {
+	n := strings.ToLower(strings.TrimSpace(nationality))
+	return n == "" || n == "malagasy" || n == "malgache"
+}
+
+// normalizeIdentifiers checks and normalizes an employee's social security and tax identifiers and CIN details.
+// The national ID of a foreign employee is a passport or residence permit number and is only trimmed. On an
+// update, previous is the stored employee and identifiers left unchanged are not checked again, so records
+// entered before validation existed can still be edited.
+func normalizeIdentifiers(e, previous *Employee) error {
+	var stored Employee
+	if previous != nil {
+		stored = *previous
+	}
+	unchanged := func(value, before string) bool {
+		return previous != nil && value == before
+	}
+
+	var err error
+	if unchanged(e.NationalID, stored.NationalID) && e.Nationality == stored.Nationality {
+		// kept as stored
+	} else if isMalagasy(e.Nationality) {
+		if e.NationalID, err = normalizeCIN(e.NationalID); err != nil {
+			return err
+		}
+	} else {
+		e.NationalID = strings.TrimSpace(e.NationalID)
+	}
+	if !unchanged(e.NIF, stored.NIF) {
+		if e.NIF, err = normalizeNIF(e.NIF); err != nil {
+			return err
+		}
+	}
+	if !unchanged(e.CNAPSNumber, stored.CNAPSNumber) {
+		if e.CNAPSNumber, err = normalizeCNAPSNumber(e.CNAPSNumber); err != nil {
+			return err
+		}
+	}
+	if !unchanged(e.OSTIENumber, stored.OSTIENumber) {
+		if e.OSTIENumber, err = normalizeOSTIENumber(e.OSTIENumber); err != nil {
+			return err
+		}
+	}
+
+	e.CINIssuedAt = strings.TrimSpace(e.CINIssuedAt)
+	if e.NationalID == "" && (e.CINIssuedOn != nil || e.CINIssuedAt != "") {
+		return fmt.Errorf("cin_issued_on and cin_issued_at require a national_id")
+	}
+	if e.CINIssuedOn != nil {
+		if e.CINIssuedOn.After(time.Now()) {
+			return fmt.Errorf("cin_issued_on cannot be in the future")
+		}
+		if e.DateOfBirth != nil && e.CINIssuedOn.Before(*e.DateOfBirth) {
+			return fmt.Errorf("cin_issued_on cannot be before date_of_birth")
+		}
+	}
+	return nil
+}
+
+// CheckIdentifiers normalizes an employee's identifiers and checks that no other employee holds the same
+// CIN, NIF, CNAPS or OSTIE number. previous is the stored employee on an update, nil on creation.
+func (r *Repo) CheckIdentifiers(ctx context.Context, e, previous *Employee) error {
+	if err := normalizeIdentifiers(e, previous); err != nil {
+		return err
+	}
+
+	var stored Employee
+	if previous != nil {
+		stored = *previous
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	identifiers := []struct {
+		column string
+		value  string
+		before string
+	}{
+		{"national_id", e.NationalID, stored.NationalID},
+		{"nif", e.NIF, stored.NIF},
+		{"cnaps_number", e.CNAPSNumber, stored.CNAPSNumber},
+		{"ostie_number", e.OSTIENumber, stored.OSTIENumber},
+	}
+	for _, identifier := range identifiers {
+		if identifier.value == "" || (previous != nil && identifier.value == identifier.before) {
+			continue
+		}
+		db := r.db.WithContext(ctx).Model(&Employee{}).Where(identifier.column+" = ?", identifier.value)
+		if e.ID != uuid.Nil {
+			db = db.Where("id <> ?", e.ID)
+		}
+		var holder Employee
+		if err := db.Select("id", "first_name", "last_name").Limit(1).Find(&holder).Error; err != nil {
+			return fmt.Errorf("check %s: %w", identifier.column, err)
+		}
+		if holder.ID != uuid.Nil {
+			return fmt.Errorf("%s %s already belongs to %s %s", identifier.column, identifier.value, holder.FirstName, holder.LastName)
+		}
+	}
+	return nil
+}
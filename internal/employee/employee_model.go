@@ -7,7 +7,8 @@ import (
 	"gorm.io/gorm"
 )
 
-// Employee represents an employee in the system
+// Employee represents an employee in the system. Position and Department hold the title and name of PositionID
+// and DepartmentID.
 type Employee struct {
 	ID                   uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	CompanyID            uuid.UUID      `gorm:"type:uuid;not null" json:"company_id"`
@@ -17,12 +18,21 @@ type Employee struct {
 	Gender               string         `gorm:"type:varchar(20);check:gender IN ('male', 'female', 'other')" json:"gender,omitempty"`
 	Nationality          string         `gorm:"type:varchar(100);default:'Malagasy'" json:"nationality"`
 	NationalID           string         `gorm:"type:varchar(50)" json:"national_id,omitempty"`
+	CINIssuedOn          *time.Time     `gorm:"type:date" json:"cin_issued_on,omitempty"`
+	CINIssuedAt          string         `gorm:"type:varchar(100)" json:"cin_issued_at,omitempty"`
+	NIF                  string         `gorm:"type:varchar(10)" json:"nif,omitempty"`
+	CNAPSNumber          string         `gorm:"type:varchar(15)" json:"cnaps_number,omitempty"`
+	OSTIENumber          string         `gorm:"type:varchar(30)" json:"ostie_number,omitempty"`
+	PositionID           *uuid.UUID     `gorm:"type:uuid" json:"position_id,omitempty"`
+	DepartmentID         *uuid.UUID     `gorm:"type:uuid" json:"department_id,omitempty"`
 	Position             string         `gorm:"type:varchar(100)" json:"position,omitempty"`
 	Department           string         `gorm:"type:varchar(100)" json:"department,omitempty"`
 	HireDate             time.Time      `gorm:"type:date;not null" json:"hire_date"`
 	ContractType         string         `gorm:"type:varchar(50);check:contract_type IN ('permanent', 'fixed_term', 'intern', 'contractor');default:'permanent'" json:"contract_type"`
 	GrossSalary          float64        `gorm:"type:numeric(15,2);not null;check:gross_salary >= 200000" json:"gross_salary"`
 	Status               string         `gorm:"type:varchar(20);default:'active';check:status IN ('active', 'on_leave', 'terminated')" json:"status"`
+	TerminationDate      *time.Time     `gorm:"type:date" json:"termination_date,omitempty"`
+	Dependents           int            `gorm:"not null;default:0;check:dependents >= 0" json:"dependents"`
 	Address              string         `gorm:"type:text" json:"address,omitempty"`
 	Phone                string         `gorm:"type:varchar(50)" json:"phone,omitempty"`
 	EmergencyContactName string         `gorm:"type:varchar(100)" json:"emergency_contact_name,omitempty"`
@@ -42,11 +52,17 @@ type CreateEmployeeRequest struct {
 	Gender                string     `json:"gender,omitempty" binding:"omitempty,oneof=male female other"`
 	Nationality           string     `json:"nationality,omitempty"`
 	NationalID            string     `json:"national_id,omitempty"`
-	Position              string     `json:"position,omitempty"`
-	Department            string     `json:"department,omitempty"`
+	CINIssuedOn           *time.Time `json:"cin_issued_on,omitempty"`
+	CINIssuedAt           string     `json:"cin_issued_at,omitempty"`
+	NIF                   string     `json:"nif,omitempty"`
+	CNAPSNumber           string     `json:"cnaps_number,omitempty"`
+	OSTIENumber           string     `json:"ostie_number,omitempty"`
+	PositionID            *uuid.UUID `json:"position_id,omitempty"`
+	DepartmentID          *uuid.UUID `json:"department_id,omitempty"`
 	HireDate              time.Time  `json:"hire_date" binding:"required"`
 	ContractType          string     `json:"contract_type,omitempty" binding:"omitempty,oneof=permanent fixed_term intern contractor"`
 	GrossSalary           float64    `json:"gross_salary" binding:"required,min=200000"`
+	Dependents            int        `json:"dependents,omitempty" binding:"omitempty,min=0"`
 	Address               string     `json:"address,omitempty"`
 	Phone                 string     `json:"phone,omitempty"`
 	EmergencyContactName  string     `json:"emergency_contact_name,omitempty"`
@@ -62,11 +78,18 @@ type UpdateEmployeeRequest struct {
 	Gender                *string    `json:"gender,omitempty" binding:"omitempty,oneof=male female other"`
 	Nationality           *string    `json:"nationality,omitempty"`
 	NationalID            *string    `json:"national_id,omitempty"`
-	Position              *string    `json:"position,omitempty"`
-	Department            *string    `json:"department,omitempty"`
+	CINIssuedOn           *time.Time `json:"cin_issued_on,omitempty"`
+	CINIssuedAt           *string    `json:"cin_issued_at,omitempty"`
+	NIF                   *string    `json:"nif,omitempty"`
+	CNAPSNumber           *string    `json:"cnaps_number,omitempty"`
+	OSTIENumber           *string    `json:"ostie_number,omitempty"`
+	PositionID            *uuid.UUID `json:"position_id,omitempty"`
+	DepartmentID          *uuid.UUID `json:"department_id,omitempty"`
 	ContractType          *string    `json:"contract_type,omitempty" binding:"omitempty,oneof=permanent fixed_term intern contractor"`
 	GrossSalary           *float64   `json:"gross_salary,omitempty" binding:"omitempty,min=200000"`
 	Status                *string    `json:"status,omitempty" binding:"omitempty,oneof=active on_leave terminated"`
+	Dependents            *int       `json:"dependents,omitempty" binding:"omitempty,min=0"`
+	TerminationDate       *time.Time `json:"termination_date,omitempty"`
 	Address               *string    `json:"address,omitempty"`
 	Phone                 *string    `json:"phone,omitempty"`
 	EmergencyContactName  *string    `json:"emergency_contact_name,omitempty"`
@@ -76,12 +99,14 @@ type UpdateEmployeeRequest struct {
 
 // EmployeeListQuery represents query parameters for listing employees
 type EmployeeListQuery struct {
-	Search     string `form:"search"`
-	Department string `form:"department"`
-	Position   string `form:"position"`
-	Status     string `form:"status" binding:"omitempty,oneof=active on_leave terminated"`
-	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
-	Offset     int    `form:"offset" binding:"omitempty,min=0"`
+	Search       string `form:"search"`
+	Department   string `form:"department"`
+	Position     string `form:"position"`
+	DepartmentID string `form:"department_id" binding:"omitempty,uuid"`
+	PositionID   string `form:"position_id" binding:"omitempty,uuid"`
+	Status       string `form:"status" binding:"omitempty,oneof=active on_leave terminated"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset       int    `form:"offset" binding:"omitempty,min=0"`
 }
 
 // TableName specifies the table name for Employee model
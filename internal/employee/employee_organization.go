@@ -0,0 +1,68 @@
+package employee
+
+import (
+	"context"
+	"fmt"
+
+	"go-server/internal/organization"
+
+	"github.com/google/uuid"
+)
+
+// CheckAssignment checks an employee's department and position and copies their names onto the employee. A
+// newly assigned department or position must be active, and the gross salary must lie within the position's
+// salary band whenever the position or the salary changes.
+func (r *Repo) CheckAssignment(ctx context.Context, e, previous *Employee) error {
+	var stored Employee
+	if previous != nil {
+		stored = *previous
+	}
+	org := organization.NewRepo(r.db)
+
+	e.Department = ""
+	if e.DepartmentID != nil {
+		department, err := org.GetDepartment(ctx, *e.DepartmentID)
+		if err != nil {
+			return fmt.Errorf("department not found")
+		}
+		if !department.IsActive && !sameID(e.DepartmentID, stored.DepartmentID) {
+			return fmt.Errorf("department %s is inactive", department.Name)
+		}
+		e.Department = department.Name
+	}
+
+	e.Position = ""
+	if e.PositionID != nil {
+		position, err := org.GetPosition(ctx, *e.PositionID)
+		if err != nil {
+			return fmt.Errorf("position not found")
+		}
+		changed := !sameID(e.PositionID, stored.PositionID)
+		if !position.IsActive && changed {
+			return fmt.Errorf("position %s is inactive", position.Title)
+		}
+		if previous == nil || changed || e.GrossSalary != stored.GrossSalary {
+			if err := position.CheckSalary(e.GrossSalary); err != nil {
+				return err
+			}
+		}
+		e.Position = position.Title
+	}
+	return nil
+}
+
+// optionalID drops a reference given as the nil UUID, which clears it
+func optionalID(id *uuid.UUID) *uuid.UUID {
+	if id == nil || *id == uuid.Nil {
+		return nil
+	}
+	return id
+}
+
+// sameID reports whether two optional references point to the same row
+func sameID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
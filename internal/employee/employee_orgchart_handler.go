@@ -0,0 +1,127 @@
+package employee
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetOrgChart returns the organizational chart as a JSON tree or a Graphviz DOT graph
+func (h *Handler) GetOrgChart(c *gin.Context) {
+	var query OrgChartQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var companyID, rootID *uuid.UUID
+	if query.CompanyID != "" {
+		id, err := uuid.Parse(query.CompanyID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+			return
+		}
+		companyID = &id
+	}
+	if query.RootID != "" {
+		id, err := uuid.Parse(query.RootID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+			return
+		}
+		root, err := h.repo.GetByID(c.Request.Context(), id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+			return
+		}
+		if root.Status == "terminated" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Terminated employees are not in the org chart"})
+			return
+		}
+		rootID, companyID = &root.ID, &root.CompanyID
+	}
+
+	chart, err := h.repo.GetOrgChart(c.Request.Context(), companyID, rootID, query.Depth)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build org chart"})
+		return
+	}
+
+	if query.Format == "dot" {
+		c.Header("Content-Disposition", "attachment; filename=org-chart.dot")
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(orgChartDOT(chart)))
+		return
+	}
+	c.JSON(http.StatusOK, chart)
+}
+
+// orgChartDOT renders an org chart as a top-down Graphviz graph, with one cluster per department
+func orgChartDOT(chart *OrgChart) string {
+	var departments []string
+	members := make(map[string][]*OrgChartNode)
+	var edges []string
+
+	var walk func(node *OrgChartNode)
+	walk = func(node *OrgChartNode) {
+		if _, ok := members[node.Department]; !ok {
+			departments = append(departments, node.Department)
+		}
+		members[node.Department] = append(members[node.Department], node)
+		for _, report := range node.Reports {
+			edges = append(edges, fmt.Sprintf("  %s -> %s;\n", dotQuote(node.ID.String()), dotQuote(report.ID.String())))
+			walk(report)
+		}
+	}
+	for _, root := range chart.Roots {
+		walk(root)
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph org_chart {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	for i, department := range departments {
+		label := department
+		if label == "" {
+			label = "No department"
+		}
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(label))
+		for _, node := range members[department] {
+			lines := []string{node.FirstName + " " + node.LastName}
+			if node.Position != "" {
+				lines = append(lines, node.Position)
+			}
+			if node.Headcount > 1 {
+				lines = append(lines, fmt.Sprintf("%d people", node.Headcount))
+			}
+			fmt.Fprintf(&b, "    %s [label=%s];\n", dotQuote(node.ID.String()), dotLabel(lines))
+		}
+		b.WriteString("  }\n")
+	}
+	for _, edge := range edges {
+		b.WriteString(edge)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotEscaper escapes text inside a quoted DOT string
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ", "\r", " ")
+
+// dotQuote writes s as a quoted DOT identifier
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// dotLabel writes lines as a quoted DOT label, one per line
+func dotLabel(lines []string) string {
+	escaped := make([]string, len(lines))
+	for i, line := range lines {
+		escaped[i] = dotEscaper.Replace(line)
+	}
+	return `"` + strings.Join(escaped, `\n`) + `"`
+}
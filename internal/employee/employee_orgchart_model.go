@@ -0,0 +1,52 @@
+package employee
+
+import "github.com/google/uuid"
+
+// OrgChartQuery represents query parameters for the organizational chart
+type OrgChartQuery struct {
+	RootID    string `form:"root_id" binding:"omitempty,uuid"`
+	CompanyID string `form:"company_id" binding:"omitempty,uuid"`
+	Depth     int    `form:"depth" binding:"omitempty,min=1,max=50"`
+	Format    string `form:"format" binding:"omitempty,oneof=json dot"`
+}
+
+// OrgChart is the reporting tree of current employees, built from their manager_id
+type OrgChart struct {
+	Headcount   int                   `json:"headcount"`
+	Departments []DepartmentHeadcount `json:"departments"`
+	Roots       []*OrgChartNode       `json:"roots"`
+}
+
+// OrgChartNode is an employee in the org chart with the people reporting to them. Headcount and departments
+// cover the whole subtree, the employee included, even when depth hides its lower levels.
+type OrgChartNode struct {
+	ID            uuid.UUID             `json:"id"`
+	FirstName     string                `json:"first_name"`
+	LastName      string                `json:"last_name"`
+	Position      string                `json:"position,omitempty"`
+	Department    string                `json:"department,omitempty"`
+	Status        string                `json:"status"`
+	ManagerID     *uuid.UUID            `json:"manager_id,omitempty"`
+	DirectReports int                   `json:"direct_reports"`
+	Headcount     int                   `json:"headcount"`
+	Departments   []DepartmentHeadcount `json:"departments"`
+	Reports       []*OrgChartNode       `json:"reports"`
+}
+
+// DepartmentHeadcount is the number of people of a department in a subtree; employees without a department
+// are counted under an empty name
+type DepartmentHeadcount struct {
+	Department string `json:"department"`
+	Headcount  int    `json:"headcount"`
+}
+
+// orgChartEmployee is the part of an employee shown in the org chart
+type orgChartEmployee struct {
+	ID         uuid.UUID
+	FirstName  string
+	LastName   string
+	Position   string
+	Department string
+	Status     string
+	ManagerID  *uuid.UUID
+}
@@ -0,0 +1,198 @@
+package employee
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetOrgChart builds the reporting tree of a company's current employees, or of all companies when companyID is
+// nil. The tree is rooted at rootID when given, otherwise at every employee without a current manager. depth
+// limits the levels of reports returned below the roots; 0 returns them all.
+func (r *Repo) GetOrgChart(ctx context.Context, companyID, rootID *uuid.UUID, depth int) (*OrgChart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Model(&Employee{}).
+		Select("id", "first_name", "last_name", "position", "department", "status", "manager_id").
+		Where("status <> ?", "terminated")
+	if companyID != nil {
+		db = db.Where("company_id = ?", *companyID)
+	}
+
+	var employees []orgChartEmployee
+	if err := db.Order("last_name ASC, first_name ASC, id ASC").Scan(&employees).Error; err != nil {
+		return nil, fmt.Errorf("get org chart employees: %w", err)
+	}
+
+	chart, ok := buildOrgChart(employees, rootID, depth)
+	if !ok {
+		return nil, fmt.Errorf("employee not found")
+	}
+	return chart, nil
+}
+
+// CheckManager checks that an employee's manager is another current employee who does not already report to
+// them, directly or through other managers. An unchanged manager is not checked again.
+func (r *Repo) CheckManager(ctx context.Context, e, previous *Employee) error {
+	if e.ManagerID == nil {
+		return nil
+	}
+	if previous != nil && previous.ManagerID != nil && *previous.ManagerID == *e.ManagerID {
+		return nil
+	}
+	if e.ID != uuid.Nil && *e.ManagerID == e.ID {
+		return fmt.Errorf("an employee cannot be their own manager")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var manager Employee
+	if err := r.db.WithContext(ctx).Select("id", "first_name", "last_name", "status").
+		Where("id = ?", *e.ManagerID).Limit(1).Find(&manager).Error; err != nil {
+		return fmt.Errorf("check manager: %w", err)
+	}
+	if manager.ID == uuid.Nil {
+		return fmt.Errorf("manager not found")
+	}
+	if manager.Status == "terminated" {
+		return fmt.Errorf("manager %s %s is terminated", manager.FirstName, manager.LastName)
+	}
+	if e.ID == uuid.Nil {
+		return nil
+	}
+
+	// Walk up the new manager's reporting line; UNION drops repeated rows, so lines that already loop still end
+	var cycle bool
+	if err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE line AS (
+			SELECT id, manager_id FROM employees WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT e.id, e.manager_id FROM employees e JOIN line l ON e.id = l.manager_id WHERE e.deleted_at IS NULL
+		)
+		SELECT EXISTS (SELECT 1 FROM line WHERE id = ?)`, manager.ID, e.ID).Scan(&cycle).Error; err != nil {
+		return fmt.Errorf("check reporting line: %w", err)
+	}
+	if cycle {
+		return fmt.Errorf("manager_id would create a reporting cycle: %s %s already reports to %s %s",
+			manager.FirstName, manager.LastName, e.FirstName, e.LastName)
+	}
+	return nil
+}
+
+// buildOrgChart arranges employees, in display order, under their managers. Employees whose manager is not
+// among them become roots; so does the first of each group caught in a reporting cycle entered before cycles
+// were rejected. It reports false when rootID is not among the employees.
+func buildOrgChart(employees []orgChartEmployee, rootID *uuid.UUID, depth int) (*OrgChart, bool) {
+	nodes := make(map[uuid.UUID]*OrgChartNode, len(employees))
+	for _, e := range employees {
+		nodes[e.ID] = &OrgChartNode{
+			ID:         e.ID,
+			FirstName:  e.FirstName,
+			LastName:   e.LastName,
+			Position:   e.Position,
+			Department: e.Department,
+			Status:     e.Status,
+			ManagerID:  e.ManagerID,
+			Reports:    []*OrgChartNode{},
+		}
+	}
+
+	reports := make(map[uuid.UUID][]*OrgChartNode)
+	for _, e := range employees {
+		if e.ManagerID == nil || *e.ManagerID == e.ID {
+			continue
+		}
+		if _, ok := nodes[*e.ManagerID]; ok {
+			reports[*e.ManagerID] = append(reports[*e.ManagerID], nodes[e.ID])
+		}
+	}
+
+	visited := make(map[uuid.UUID]bool, len(employees))
+	var attach func(node *OrgChartNode) map[string]int
+	attach = func(node *OrgChartNode) map[string]int {
+		visited[node.ID] = true
+		departments := map[string]int{node.Department: 1}
+		node.Headcount = 1
+		for _, report := range reports[node.ID] {
+			if visited[report.ID] {
+				continue
+			}
+			for department, count := range attach(report) {
+				departments[department] += count
+			}
+			node.Headcount += report.Headcount
+			node.Reports = append(node.Reports, report)
+		}
+		node.DirectReports = len(node.Reports)
+		node.Departments = departmentHeadcounts(departments)
+		return departments
+	}
+
+	chart := &OrgChart{Roots: []*OrgChartNode{}}
+	total := make(map[string]int)
+	addRoot := func(node *OrgChartNode) {
+		for department, count := range attach(node) {
+			total[department] += count
+		}
+		chart.Headcount += node.Headcount
+		chart.Roots = append(chart.Roots, node)
+	}
+
+	if rootID != nil {
+		root, ok := nodes[*rootID]
+		if !ok {
+			return nil, false
+		}
+		addRoot(root)
+	} else {
+		for _, e := range employees {
+			if e.ManagerID == nil || *e.ManagerID == e.ID || nodes[*e.ManagerID] == nil {
+				addRoot(nodes[e.ID])
+			}
+		}
+		for _, e := range employees {
+			if !visited[e.ID] {
+				addRoot(nodes[e.ID])
+			}
+		}
+	}
+	chart.Departments = departmentHeadcounts(total)
+
+	if depth > 0 {
+		for _, root := range chart.Roots {
+			pruneOrgChart(root, depth)
+		}
+	}
+	return chart, true
+}
+
+// pruneOrgChart drops the reports of nodes more than depth levels below node
+func pruneOrgChart(node *OrgChartNode, depth int) {
+	if depth == 0 {
+		node.Reports = []*OrgChartNode{}
+		return
+	}
+	for _, report := range node.Reports {
+		pruneOrgChart(report, depth-1)
+	}
+}
+
+// departmentHeadcounts lists headcounts by department, largest first
+func departmentHeadcounts(counts map[string]int) []DepartmentHeadcount {
+	headcounts := make([]DepartmentHeadcount, 0, len(counts))
+	for department, count := range counts {
+		headcounts = append(headcounts, DepartmentHeadcount{Department: department, Headcount: count})
+	}
+	sort.Slice(headcounts, func(i, j int) bool {
+		if headcounts[i].Headcount != headcounts[j].Headcount {
+			return headcounts[i].Headcount > headcounts[j].Headcount
+		}
+		return headcounts[i].Department < headcounts[j].Department
+	})
+	return headcounts
+}
@@ -0,0 +1,112 @@
+package employee
+
+import (
+	"net/http"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetPaymentAccount retrieves the masked account an employee is paid to (HR/Accountant/Admin only)
+func (h *Handler) GetPaymentAccount(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	// Verify HR or Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" && userRole != "hr" && userRole != "accountant" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	account, err := h.repo.GetPaymentAccount(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment account not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// SetPaymentAccount sets the bank account or mobile money number an employee is paid to (HR/Admin only)
+func (h *Handler) SetPaymentAccount(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var input SetPaymentAccountRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" && userRole != "hr" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can set payment accounts"})
+		return
+	}
+
+	if _, err := h.repo.GetByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+
+	account := &PaymentAccount{
+		EmployeeID:     id,
+		Method:         input.Method,
+		AccountHolder:  input.AccountHolder,
+		BankName:       input.BankName,
+		IBAN:           input.AccountNumber,
+		BIC:            input.BIC,
+		MobileOperator: input.MobileOperator,
+		MobileNumber:   input.MobileNumber,
+		UpdatedBy:      &userID,
+	}
+	if err := h.repo.SetPaymentAccount(c.Request.Context(), account); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// DeletePaymentAccount removes the account an employee is paid to (HR/Admin only)
+func (h *Handler) DeletePaymentAccount(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" && userRole != "hr" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can delete payment accounts"})
+		return
+	}
+
+	if err := h.repo.DeletePaymentAccount(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment account not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment account deleted successfully"})
+}
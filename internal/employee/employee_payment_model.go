@@ -0,0 +1,63 @@
+package employee
+
+import (
+	"time"
+
+	"go-server/internal/banking"
+
+	"github.com/google/uuid"
+)
+
+// Salary payment methods
+const (
+	PaymentMethodBankTransfer = "bank_transfer"
+	PaymentMethodMobileMoney  = "mobile_money"
+)
+
+// PaymentAccount holds the bank account or mobile money number an employee's net salary is paid to.
+// Account and mobile numbers are never returned in full: responses only carry their masked form.
+type PaymentAccount struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EmployeeID          uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"employee_id"`
+	Method              string     `gorm:"type:varchar(20);not null" json:"method"`
+	AccountHolder       string     `gorm:"type:varchar(140);not null" json:"account_holder"`
+	BankName            string     `gorm:"type:varchar(100)" json:"bank_name,omitempty"`
+	IBAN                string     `gorm:"type:varchar(34)" json:"-"`
+	BIC                 string     `gorm:"type:varchar(11)" json:"bic,omitempty"`
+	MobileOperator      string     `gorm:"type:varchar(20)" json:"mobile_operator,omitempty"`
+	MobileNumber        string     `gorm:"type:varchar(15)" json:"-"`
+	AccountNumberMasked string     `gorm:"-" json:"account_number_masked,omitempty"`
+	MobileNumberMasked  string     `gorm:"-" json:"mobile_number_masked,omitempty"`
+	UpdatedBy           *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+	CreatedAt           time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt           time.Time  `gorm:"default:now()" json:"updated_at"`
+}
+
+// SetPaymentAccountRequest represents request to set the account an employee is paid to.
+// AccountNumber accepts a 23-digit RIB or an IBAN; MobileOperator defaults to the operator of MobileNumber.
+type SetPaymentAccountRequest struct {
+	Method         string `json:"method" binding:"required,oneof=bank_transfer mobile_money"`
+	AccountHolder  string `json:"account_holder" binding:"required,max=140"`
+	BankName       string `json:"bank_name" binding:"max=100"`
+	AccountNumber  string `json:"account_number"`
+	BIC            string `json:"bic"`
+	MobileOperator string `json:"mobile_operator" binding:"omitempty,oneof=mvola orange_money airtel_money"`
+	MobileNumber   string `json:"mobile_number"`
+}
+
+// Mask fills the masked account and mobile numbers returned in responses
+func (a *PaymentAccount) Mask() {
+	a.AccountNumberMasked = ""
+	a.MobileNumberMasked = ""
+	if a.IBAN != "" {
+		a.AccountNumberMasked = banking.MaskAccount(a.IBAN)
+	}
+	if a.MobileNumber != "" {
+		a.MobileNumberMasked = banking.MaskMSISDN(a.MobileNumber)
+	}
+}
+
+// TableName specifies the table name for PaymentAccount model
+func (PaymentAccount) TableName() string {
+	return "employee_payment_accounts"
+}
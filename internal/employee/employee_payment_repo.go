@@ -0,0 +1,113 @@
+package employee
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-server/internal/banking"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetPaymentAccount retrieves the account an employee is paid to
+func (r *Repo) GetPaymentAccount(ctx context.Context, employeeID uuid.UUID) (*PaymentAccount, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var account PaymentAccount
+	if err := r.db.WithContext(ctx).Where("employee_id = ?", employeeID).First(&account).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("payment account not found")
+		}
+		return nil, fmt.Errorf("get payment account: %w", err)
+	}
+	account.Mask()
+	return &account, nil
+}
+
+// SetPaymentAccount validates and stores the account an employee is paid to, replacing any previous one
+func (r *Repo) SetPaymentAccount(ctx context.Context, account *PaymentAccount) error {
+	if err := normalizePaymentAccount(account); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	account.UpdatedAt = time.Now()
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "employee_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"method", "account_holder", "bank_name", "iban", "bic", "mobile_operator", "mobile_number", "updated_by", "updated_at",
+		}),
+	}).Create(account).Error; err != nil {
+		return fmt.Errorf("set payment account: %w", err)
+	}
+
+	stored, err := r.GetPaymentAccount(ctx, account.EmployeeID)
+	if err != nil {
+		return err
+	}
+	*account = *stored
+	return nil
+}
+
+// DeletePaymentAccount removes the account an employee is paid to
+func (r *Repo) DeletePaymentAccount(ctx context.Context, employeeID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Where("employee_id = ?", employeeID).Delete(&PaymentAccount{})
+	if result.Error != nil {
+		return fmt.Errorf("delete payment account: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("payment account not found")
+	}
+	return nil
+}
+
+// normalizePaymentAccount checks that an account has the details its method needs, normalizes them, and clears
+// the details of the other method. Bank accounts are stored as IBANs and mobile numbers in international form.
+func normalizePaymentAccount(account *PaymentAccount) error {
+	switch account.Method {
+	case PaymentMethodBankTransfer:
+		iban, err := banking.NormalizeAccount(account.IBAN)
+		if err != nil {
+			return err
+		}
+		account.IBAN = iban
+		if account.BIC != "" {
+			bic, err := banking.NormalizeBIC(account.BIC)
+			if err != nil {
+				return err
+			}
+			account.BIC = bic
+		}
+		if account.BankName == "" {
+			return fmt.Errorf("bank_name is required for bank transfers")
+		}
+		account.MobileOperator = ""
+		account.MobileNumber = ""
+	case PaymentMethodMobileMoney:
+		msisdn, err := banking.NormalizeMSISDN(account.MobileNumber)
+		if err != nil {
+			return err
+		}
+		operator := banking.MobileOperator(msisdn)
+		if account.MobileOperator != "" && account.MobileOperator != operator {
+			return fmt.Errorf("mobile number is on the %s network, not %s", operator, account.MobileOperator)
+		}
+		account.MobileNumber = msisdn
+		account.MobileOperator = operator
+		account.BankName = ""
+		account.IBAN = ""
+		account.BIC = ""
+	default:
+		return fmt.Errorf("invalid payment method %s", account.Method)
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"go-server/internal/organization"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -93,6 +95,14 @@ func (r *Repo) List(ctx context.Context, query EmployeeListQuery) ([]Employee, i
 		db = db.Where("position = ?", query.Position)
 	}
 
+	if query.DepartmentID != "" {
+		db = db.Where("department_id = ?", query.DepartmentID)
+	}
+
+	if query.PositionID != "" {
+		db = db.Where("position_id = ?", query.PositionID)
+	}
+
 	if query.Status != "" {
 		db = db.Where("status = ?", query.Status)
 	}
@@ -151,25 +161,27 @@ func (r *Repo) GetSubordinates(ctx context.Context, managerID uuid.UUID) ([]Empl
 	return employees, nil
 }
 
-// GetDepartments retrieves all unique departments
+// GetDepartments retrieves the names of active departments
 func (r *Repo) GetDepartments(ctx context.Context) ([]string, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	var departments []string
-	if err := r.db.WithContext(ctx).Model(&Employee{}).Distinct("department").Pluck("department", &departments).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&organization.Department{}).Where("is_active = ?", true).
+		Order("name ASC").Pluck("name", &departments).Error; err != nil {
 		return nil, fmt.Errorf("get departments: %w", err)
 	}
 	return departments, nil
 }
 
-// GetPositions retrieves all unique positions
+// GetPositions retrieves the titles of active positions
 func (r *Repo) GetPositions(ctx context.Context) ([]string, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	var positions []string
-	if err := r.db.WithContext(ctx).Model(&Employee{}).Distinct("position").Pluck("position", &positions).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&organization.Position{}).Where("is_active = ?", true).
+		Order("title ASC").Pluck("title", &positions).Error; err != nil {
 		return nil, fmt.Errorf("get positions: %w", err)
 	}
 	return positions, nil
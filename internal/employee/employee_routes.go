@@ -23,6 +23,9 @@ func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
 		employees.GET("/departments", handler.GetDepartments)
 		employees.GET("/positions", handler.GetPositions)
 
+		// Organizational chart from reporting lines, as a JSON tree or Graphviz DOT
+		employees.GET("/org-chart", handler.GetOrgChart)
+
 		// Individual employee operations
 		employees.GET("/:id", handler.GetByID)
 		employees.PUT("/:id", middleware.RequireRole("admin", "hr"), handler.Update)
@@ -30,5 +33,28 @@ func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
 
 		// Get subordinates
 		employees.GET("/:id/subordinates", handler.GetSubordinates)
+
+		// Salary payment account, returned masked (HR/Admin write, Accountant read)
+		employees.GET("/:id/payment-account", middleware.RequireRole("admin", "hr", "accountant"), handler.GetPaymentAccount)
+		employees.PUT("/:id/payment-account", middleware.RequireRole("admin", "hr"), handler.SetPaymentAccount)
+		employees.DELETE("/:id/payment-account", middleware.RequireRole("admin", "hr"), handler.DeletePaymentAccount)
+
+		// Employee documents: contracts, diplomas, ID documents and medical certificates, with versions (HR/Admin only)
+		employees.GET("/documents/expiring", middleware.RequireRole("admin", "hr"), handler.ListExpiringDocuments)
+		employees.GET("/:id/documents", middleware.RequireRole("admin", "hr"), handler.ListDocuments)
+		employees.POST("/:id/documents", middleware.RequireRole("admin", "hr"), handler.UploadDocument)
+		employees.GET("/:id/documents/:document_id", middleware.RequireRole("admin", "hr"), handler.GetDocument)
+		employees.DELETE("/:id/documents/:document_id", middleware.RequireRole("admin", "hr"), handler.DeleteDocument)
+		employees.GET("/:id/documents/:document_id/download", middleware.RequireRole("admin", "hr"), handler.DownloadDocument)
+		employees.POST("/:id/documents/:document_id/versions", middleware.RequireRole("admin", "hr"), handler.UploadDocumentVersion)
+
+		// Employment contracts with probation, amendments and renewals (HR/Admin only)
+		employees.GET("/contracts/deadlines", middleware.RequireRole("admin", "hr"), handler.ListContractDeadlines)
+		employees.GET("/:id/contracts", middleware.RequireRole("admin", "hr"), handler.ListContracts)
+		employees.POST("/:id/contracts", middleware.RequireRole("admin", "hr"), handler.CreateContract)
+		employees.GET("/:id/contracts/:contract_id", middleware.RequireRole("admin", "hr"), handler.GetContract)
+		employees.PUT("/:id/contracts/:contract_id", middleware.RequireRole("admin", "hr"), handler.UpdateContract)
+		employees.POST("/:id/contracts/:contract_id/renew", middleware.RequireRole("admin", "hr"), handler.RenewContract)
+		employees.POST("/:id/contracts/:contract_id/amendments", middleware.RequireRole("admin", "hr"), handler.AddContractAmendment)
 	}
 }
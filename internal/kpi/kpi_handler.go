@@ -48,8 +48,8 @@ func (h *Handler) CreateKPI(c *gin.Context) {
 		TargetValue:      input.TargetValue,
 		WeightPercentage: input.WeightPercentage,
 		ScoringScale:     input.ScoringScale,
-		Department:       input.Department,
-		Position:         input.Position,
+		DepartmentID:     optionalID(input.DepartmentID),
+		PositionID:       optionalID(input.PositionID),
 		CreatedBy:        userID,
 	}
 
@@ -57,6 +57,11 @@ func (h *Handler) CreateKPI(c *gin.Context) {
 		kpi.ScoringScale = "1_to_5"
 	}
 
+	if err := h.repo.CheckTargets(c.Request.Context(), kpi, nil); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := h.repo.CreateKPI(c.Request.Context(), kpi); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -111,6 +116,8 @@ func (h *Handler) UpdateKPI(c *gin.Context) {
 		return
 	}
 
+	stored := *kpi
+
 	// Update fields if provided
 	if input.Name != nil {
 		kpi.Name = *input.Name
@@ -127,16 +134,21 @@ func (h *Handler) UpdateKPI(c *gin.Context) {
 	if input.ScoringScale != nil {
 		kpi.ScoringScale = *input.ScoringScale
 	}
-	if input.Department != nil {
-		kpi.Department = *input.Department
+	if input.DepartmentID != nil {
+		kpi.DepartmentID = optionalID(input.DepartmentID)
 	}
-	if input.Position != nil {
-		kpi.Position = *input.Position
+	if input.PositionID != nil {
+		kpi.PositionID = optionalID(input.PositionID)
 	}
 	if input.IsActive != nil {
 		kpi.IsActive = *input.IsActive
 	}
 
+	if err := h.repo.CheckTargets(c.Request.Context(), kpi, &stored); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := h.repo.UpdateKPI(c.Request.Context(), kpi); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update KPI"})
 		return
@@ -469,3 +481,11 @@ func (h *Handler) GeneratePerformanceReport(c *gin.Context) {
 
 	c.JSON(http.StatusOK, report)
 }
+
+// optionalID drops a reference given as the nil UUID, which clears it
+func optionalID(id *uuid.UUID) *uuid.UUID {
+	if id == nil || *id == uuid.Nil {
+		return nil
+	}
+	return id
+}
@@ -6,20 +6,23 @@ import (
 	"github.com/google/uuid"
 )
 
-// KPI represents a Key Performance Indicator template
+// KPI represents a Key Performance Indicator template. Department and Position hold the name and title of
+// DepartmentID and PositionID.
 type KPI struct {
-	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Name             string    `gorm:"type:varchar(255);not null" json:"name"`
-	Description      string    `gorm:"type:text" json:"description"`
-	TargetValue      float64   `gorm:"type:numeric(15,2);not null" json:"target_value"`
-	WeightPercentage float64   `gorm:"type:numeric(5,2);not null" json:"weight_percentage"`
-	ScoringScale     string    `gorm:"type:varchar(20);default:'1_to_5'" json:"scoring_scale"`
-	Department       string    `gorm:"type:varchar(100)" json:"department"`
-	Position         string    `gorm:"type:varchar(100)" json:"position"`
-	IsActive         bool      `gorm:"default:true" json:"is_active"`
-	CreatedBy        uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
-	CreatedAt        time.Time `gorm:"default:now()" json:"created_at"`
-	UpdatedAt        time.Time `gorm:"default:now()" json:"updated_at"`
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name             string     `gorm:"type:varchar(255);not null" json:"name"`
+	Description      string     `gorm:"type:text" json:"description"`
+	TargetValue      float64    `gorm:"type:numeric(15,2);not null" json:"target_value"`
+	WeightPercentage float64    `gorm:"type:numeric(5,2);not null" json:"weight_percentage"`
+	ScoringScale     string     `gorm:"type:varchar(20);default:'1_to_5'" json:"scoring_scale"`
+	DepartmentID     *uuid.UUID `gorm:"type:uuid" json:"department_id,omitempty"`
+	PositionID       *uuid.UUID `gorm:"type:uuid" json:"position_id,omitempty"`
+	Department       string     `gorm:"type:varchar(100)" json:"department"`
+	Position         string     `gorm:"type:varchar(100)" json:"position"`
+	IsActive         bool       `gorm:"default:true" json:"is_active"`
+	CreatedBy        uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt        time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"default:now()" json:"updated_at"`
 }
 
 // PerformanceReview represents a performance review for an employee
@@ -42,34 +45,36 @@ type PerformanceReview struct {
 
 // CreateKPIRequest represents request to create a KPI
 type CreateKPIRequest struct {
-	Name             string  `json:"name" binding:"required"`
-	Description      string  `json:"description"`
-	TargetValue      float64 `json:"target_value" binding:"required"`
-	WeightPercentage float64 `json:"weight_percentage" binding:"required,min=0.01,max=100"`
-	ScoringScale     string  `json:"scoring_scale" binding:"omitempty,oneof=1_to_5 1_to_10 custom"`
-	Department       string  `json:"department"`
-	Position         string  `json:"position"`
+	Name             string     `json:"name" binding:"required"`
+	Description      string     `json:"description"`
+	TargetValue      float64    `json:"target_value" binding:"required"`
+	WeightPercentage float64    `json:"weight_percentage" binding:"required,min=0.01,max=100"`
+	ScoringScale     string     `json:"scoring_scale" binding:"omitempty,oneof=1_to_5 1_to_10 custom"`
+	DepartmentID     *uuid.UUID `json:"department_id"`
+	PositionID       *uuid.UUID `json:"position_id"`
 }
 
 // UpdateKPIRequest represents request to update a KPI
 type UpdateKPIRequest struct {
-	Name             *string  `json:"name"`
-	Description      *string  `json:"description"`
-	TargetValue      *float64 `json:"target_value" binding:"omitempty,min=0"`
-	WeightPercentage *float64 `json:"weight_percentage" binding:"omitempty,min=0.01,max=100"`
-	ScoringScale     *string  `json:"scoring_scale" binding:"omitempty,oneof=1_to_5 1_to_10 custom"`
-	Department       *string  `json:"department"`
-	Position         *string  `json:"position"`
-	IsActive         *bool    `json:"is_active"`
+	Name             *string    `json:"name"`
+	Description      *string    `json:"description"`
+	TargetValue      *float64   `json:"target_value" binding:"omitempty,min=0"`
+	WeightPercentage *float64   `json:"weight_percentage" binding:"omitempty,min=0.01,max=100"`
+	ScoringScale     *string    `json:"scoring_scale" binding:"omitempty,oneof=1_to_5 1_to_10 custom"`
+	DepartmentID     *uuid.UUID `json:"department_id"`
+	PositionID       *uuid.UUID `json:"position_id"`
+	IsActive         *bool      `json:"is_active"`
 }
 
 // KPIListQuery represents query parameters for listing KPIs
 type KPIListQuery struct {
-	Department string `form:"department"`
-	Position   string `form:"position"`
-	IsActive   *bool  `form:"is_active"`
-	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
-	Offset     int    `form:"offset" binding:"omitempty,min=0"`
+	Department   string `form:"department"`
+	Position     string `form:"position"`
+	DepartmentID string `form:"department_id" binding:"omitempty,uuid"`
+	PositionID   string `form:"position_id" binding:"omitempty,uuid"`
+	IsActive     *bool  `form:"is_active"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset       int    `form:"offset" binding:"omitempty,min=0"`
 }
 
 // CreatePerformanceReviewRequest represents request to create a performance review
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"go-server/internal/organization"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -89,6 +91,12 @@ func (r *Repo) ListKPIs(ctx context.Context, query KPIListQuery) ([]KPI, int64,
 	if query.Position != "" {
 		db = db.Where("position = ?", query.Position)
 	}
+	if query.DepartmentID != "" {
+		db = db.Where("department_id = ?", query.DepartmentID)
+	}
+	if query.PositionID != "" {
+		db = db.Where("position_id = ?", query.PositionID)
+	}
 	if query.IsActive != nil {
 		db = db.Where("is_active = ?", *query.IsActive)
 	}
@@ -237,29 +245,64 @@ func (r *Repo) GetPerformanceReviewsByEmployee(ctx context.Context, employeeID u
 }
 
 // GetKPIsByDepartment retrieves all KPIs for a department
-func (r *Repo) GetKPIsByDepartment(ctx context.Context, department string) ([]KPI, error) {
+func (r *Repo) GetKPIsByDepartment(ctx context.Context, departmentID uuid.UUID) ([]KPI, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	var kpis []KPI
-	if err := r.db.WithContext(ctx).Where("department = ? AND is_active = ?", department, true).Find(&kpis).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("department_id = ? AND is_active = ?", departmentID, true).Find(&kpis).Error; err != nil {
 		return nil, fmt.Errorf("get KPIs by department: %w", err)
 	}
 	return kpis, nil
 }
 
 // GetKPIsByPosition retrieves all KPIs for a position
-func (r *Repo) GetKPIsByPosition(ctx context.Context, position string) ([]KPI, error) {
+func (r *Repo) GetKPIsByPosition(ctx context.Context, positionID uuid.UUID) ([]KPI, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	var kpis []KPI
-	if err := r.db.WithContext(ctx).Where("position = ? AND is_active = ?", position, true).Find(&kpis).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("position_id = ? AND is_active = ?", positionID, true).Find(&kpis).Error; err != nil {
 		return nil, fmt.Errorf("get KPIs by position: %w", err)
 	}
 	return kpis, nil
 }
 
+// CheckTargets checks the department and position a KPI targets and copies their names onto it. A newly
+// targeted department or position must be active.
+func (r *Repo) CheckTargets(ctx context.Context, kpi, previous *KPI) error {
+	var stored KPI
+	if previous != nil {
+		stored = *previous
+	}
+	org := organization.NewRepo(r.db)
+
+	kpi.Department = ""
+	if kpi.DepartmentID != nil {
+		department, err := org.GetDepartment(ctx, *kpi.DepartmentID)
+		if err != nil {
+			return fmt.Errorf("department not found")
+		}
+		if !department.IsActive && (stored.DepartmentID == nil || *stored.DepartmentID != department.ID) {
+			return fmt.Errorf("department %s is inactive", department.Name)
+		}
+		kpi.Department = department.Name
+	}
+
+	kpi.Position = ""
+	if kpi.PositionID != nil {
+		position, err := org.GetPosition(ctx, *kpi.PositionID)
+		if err != nil {
+			return fmt.Errorf("position not found")
+		}
+		if !position.IsActive && (stored.PositionID == nil || *stored.PositionID != position.ID) {
+			return fmt.Errorf("position %s is inactive", position.Title)
+		}
+		kpi.Position = position.Title
+	}
+	return nil
+}
+
 // CalculateFinalScore calculates the final score for a performance review
 func (r *Repo) CalculateFinalScore(ctx context.Context, reviewID uuid.UUID) (*PerformanceReview, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
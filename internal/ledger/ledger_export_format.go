@@ -0,0 +1,145 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// accountMapper resolves ledger accounts to the external accounting software's codes
+type accountMapper map[string]AccountMapping
+
+// resolve returns the external code and name of a ledger account: the mapping of the account itself or of its
+// closest mapped parent, or the ledger code and name when none is mapped
+func (m accountMapper) resolve(code, name string) (string, string) {
+	for prefix := code; prefix != ""; prefix = prefix[:len(prefix)-1] {
+		mapping, ok := m[prefix]
+		if !ok {
+			continue
+		}
+		if mapping.ExternalName != "" {
+			name = mapping.ExternalName
+		}
+		return mapping.ExternalCode, name
+	}
+	return code, name
+}
+
+// renderJournalCSV writes entries as semicolon-separated UTF-8, one line per journal line, amounts with a dot decimal
+func renderJournalCSV(entries []JournalEntry, mapper accountMapper) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	header := []string{"journal", "entry_number", "entry_date", "line_number", "account_code", "account_name",
+		"ledger_account_code", "description", "debit", "credit", "source_type", "source_id", "reversal_of"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			code, name := mapper.resolve(line.AccountCode, line.AccountName)
+			record := []string{
+				entry.Journal, entry.EntryNumber, entry.EntryDate.Format("2006-01-02"), strconv.Itoa(line.LineNumber),
+				code, name, line.AccountCode, lineDescription(entry, line),
+				strconv.FormatFloat(line.Debit, 'f', 2, 64), strconv.FormatFloat(line.Credit, 'f', 2, 64),
+				entry.SourceType, optionalID(entry.SourceID), optionalID(entry.ReversalOf),
+			}
+			if err := w.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// renderJournalFEC writes entries in the 18 pipe-separated columns of the Fichier des Écritures Comptables:
+// dates as YYYYMMDD and amounts with a comma decimal
+func renderJournalFEC(entries []JournalEntry, mapper accountMapper) []byte {
+	var b strings.Builder
+	b.WriteString("JournalCode|JournalLib|EcritureNum|EcritureDate|CompteNum|CompteLib|CompAuxNum|CompAuxLib|PieceRef|" +
+		"PieceDate|EcritureLib|Debit|Credit|EcritureLet|DateLet|ValidDate|Montantdevise|Idevise\r\n")
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			code, name := mapper.resolve(line.AccountCode, line.AccountName)
+			fields := []string{
+				entry.Journal, journalLabels[entry.Journal], entry.EntryNumber, entry.EntryDate.Format("20060102"),
+				code, name, "", "", entry.EntryNumber, entry.EntryDate.Format("20060102"), lineDescription(entry, line),
+				fecAmount(line.Debit), fecAmount(line.Credit), "", "", entry.PostedAt.Format("20060102"), "", "",
+			}
+			for i, field := range fields {
+				fields[i] = fecField(field)
+			}
+			b.WriteString(strings.Join(fields, "|"))
+			b.WriteString("\r\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+// journalFeedEntry publishes an entry in the JSON feed with its accounts mapped
+func journalFeedEntry(entry JournalEntry, mapper accountMapper) JournalFeedEntry {
+	feedEntry := JournalFeedEntry{
+		ID:          entry.ID,
+		EntryNumber: entry.EntryNumber,
+		Journal:     entry.Journal,
+		EntryDate:   entry.EntryDate.Format("2006-01-02"),
+		Description: entry.Description,
+		SourceType:  entry.SourceType,
+		SourceID:    entry.SourceID,
+		ReversalOf:  entry.ReversalOf,
+		PostedAt:    entry.PostedAt,
+		TotalDebit:  entry.TotalDebit,
+		TotalCredit: entry.TotalCredit,
+		Lines:       make([]JournalFeedLine, 0, len(entry.Lines)),
+	}
+	for _, line := range entry.Lines {
+		code, name := mapper.resolve(line.AccountCode, line.AccountName)
+		feedEntry.Lines = append(feedEntry.Lines, JournalFeedLine{
+			LineNumber:        line.LineNumber,
+			AccountCode:       code,
+			AccountName:       name,
+			LedgerAccountCode: line.AccountCode,
+			Debit:             line.Debit,
+			Credit:            line.Credit,
+			Description:       line.Description,
+		})
+	}
+	return feedEntry
+}
+
+// journalLabels name the journals in FEC files
+var journalLabels = map[string]string{
+	JournalPayroll:       "Journal de paie",
+	JournalMiscellaneous: "Opérations diverses",
+}
+
+// lineDescription returns a line's own description, or its entry's when the line has none
+func lineDescription(entry JournalEntry, line JournalLine) string {
+	if line.Description != "" {
+		return line.Description
+	}
+	return entry.Description
+}
+
+// fecAmount formats an amount with two decimals and a comma separator, e.g. 1250000,00
+func fecAmount(amount float64) string {
+	return strings.Replace(strconv.FormatFloat(amount, 'f', 2, 64), ".", ",", 1)
+}
+
+// fecField keeps separators and line breaks out of a FEC field
+func fecField(s string) string {
+	return strings.NewReplacer("|", "/", "\r", " ", "\n", " ").Replace(s)
+}
+
+// optionalID formats an optional ID, or "" when unset
+func optionalID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
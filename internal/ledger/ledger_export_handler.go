@@ -0,0 +1,236 @@
+package ledger
+
+import (
+	"errors"
+	"net/http"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListAccountMappings lists the mappings of ledger accounts to the external accounting software's codes
+func (h *Handler) ListAccountMappings(c *gin.Context) {
+	mappings, err := h.repo.ListAccountMappings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list account mappings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mappings": mappings})
+}
+
+// SetAccountMapping maps a ledger account to an external account code (Accountant only)
+func (h *Handler) SetAccountMapping(c *gin.Context) {
+	accountCode := c.Param("code")
+	if !isAccountCode(accountCode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account code"})
+		return
+	}
+
+	var input SetAccountMappingRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can map accounts"})
+		return
+	}
+
+	mapping := &AccountMapping{
+		AccountCode:  accountCode,
+		ExternalCode: input.ExternalCode,
+		ExternalName: input.ExternalName,
+		UpdatedBy:    &userID,
+	}
+	if err := h.repo.SetAccountMapping(c.Request.Context(), mapping); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save account mapping"})
+		return
+	}
+
+	c.JSON(http.StatusOK, mapping)
+}
+
+// DeleteAccountMapping removes the mapping of a ledger account (Accountant only)
+func (h *Handler) DeleteAccountMapping(c *gin.Context) {
+	accountCode := c.Param("code")
+	if !isAccountCode(accountCode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account code"})
+		return
+	}
+
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can map accounts"})
+		return
+	}
+
+	if err := h.repo.DeleteAccountMapping(c.Request.Context(), accountCode); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Account mapping not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account mapping deleted successfully"})
+}
+
+// CreateJournalExport exports a period of a journal as a CSV or FEC file and records the export (Accountant only)
+func (h *Handler) CreateJournalExport(c *gin.Context) {
+	var input CreateJournalExportRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	from, to, err := parsePeriod(input.From, input.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can export journals"})
+		return
+	}
+
+	export, err := h.repo.CreateExport(c.Request.Context(), input.Format, input.Journal, *from, *to, input.IncludeExported, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	writeExport(c, http.StatusCreated, export)
+}
+
+// ListJournalExports lists the export log
+func (h *Handler) ListJournalExports(c *gin.Context) {
+	var query JournalExportListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	exports, total, err := h.repo.ListExports(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list journal exports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exports": exports,
+		"total":   total,
+		"limit":   query.Limit,
+		"offset":  query.Offset,
+	})
+}
+
+// GetJournalExport retrieves an export log record
+func (h *Handler) GetJournalExport(c *gin.Context) {
+	export, ok := h.loadExport(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// DownloadJournalExport downloads the file of an earlier export again, byte for byte
+func (h *Handler) DownloadJournalExport(c *gin.Context) {
+	export, ok := h.loadExport(c)
+	if !ok {
+		return
+	}
+	if export.Format == ExportFormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Feed pages are replayed from their cursor, not downloaded"})
+		return
+	}
+
+	writeExport(c, http.StatusOK, export)
+}
+
+// GetJournalFeed returns the journal entries posted after a cursor as JSON for accounting software integration
+func (h *Handler) GetJournalFeed(c *gin.Context) {
+	var query JournalFeedQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	page, err := h.repo.GetFeedPage(c.Request.Context(), query, userID)
+	if err != nil {
+		if errors.Is(err, errInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get journal feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// loadExport loads the export in the URL, writing the error response when it cannot
+func (h *Handler) loadExport(c *gin.Context) (*JournalExport, bool) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid journal export ID"})
+		return nil, false
+	}
+
+	export, err := h.repo.GetExportByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Journal export not found"})
+		return nil, false
+	}
+	return export, true
+}
+
+// writeExport sends an export file with its log ID and checksum in headers
+func writeExport(c *gin.Context, status int, export *JournalExport) {
+	c.Header("Content-Disposition", "attachment; filename="+export.Filename)
+	c.Header("X-Export-ID", export.ID.String())
+	c.Header("X-Export-Checksum", export.Checksum)
+	c.Data(status, export.ContentType, export.Content)
+}
+
+// isAccountCode reports whether s is a non-empty string of digits
+func isAccountCode(s string) bool {
+	if s == "" || len(s) > 20 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Journal export formats
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatFEC  = "fec"
+	ExportFormatJSON = "json"
+)
+
+// feedSettleDelay keeps entries out of the JSON feed until every transaction that could still commit
+// an entry posted before them has finished, so a cursor never skips a late commit
+const feedSettleDelay = time.Minute
+
+// AccountMapping maps a ledger account, and its sub-accounts without a mapping of their own,
+// to the account code used by the company's external accounting software
+type AccountMapping struct {
+	AccountCode  string     `gorm:"type:varchar(20);primary_key" json:"account_code"`
+	ExternalCode string     `gorm:"type:varchar(50);not null" json:"external_code"`
+	ExternalName string     `gorm:"type:varchar(255)" json:"external_name,omitempty"`
+	UpdatedAt    time.Time  `gorm:"default:now()" json:"updated_at"`
+	UpdatedBy    *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+}
+
+// SetAccountMappingRequest represents request to map a ledger account to an external account
+type SetAccountMappingRequest struct {
+	ExternalCode string `json:"external_code" binding:"required,max=50"`
+	ExternalName string `json:"external_name" binding:"max=255"`
+}
+
+// JournalExport records a journal export: a CSV or FEC file, whose entries are not exported again in that format,
+// or a page of the JSON feed
+type JournalExport struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Format          string     `gorm:"type:varchar(10);not null" json:"format"`
+	Journal         string     `gorm:"type:varchar(10);not null" json:"journal"`
+	PeriodStart     *time.Time `gorm:"type:date" json:"period_start,omitempty"`
+	PeriodEnd       *time.Time `gorm:"type:date" json:"period_end,omitempty"`
+	IncludeExported bool       `gorm:"not null;default:false" json:"include_exported"`
+	Cursor          string     `gorm:"type:varchar(100)" json:"cursor,omitempty"`
+	NextCursor      string     `gorm:"type:varchar(100)" json:"next_cursor,omitempty"`
+	EntryCount      int        `gorm:"not null" json:"entry_count"`
+	FirstEntry      string     `gorm:"type:varchar(50)" json:"first_entry,omitempty"`
+	LastEntry       string     `gorm:"type:varchar(50)" json:"last_entry,omitempty"`
+	TotalDebit      float64    `gorm:"type:numeric(15,2);not null" json:"total_debit"`
+	TotalCredit     float64    `gorm:"type:numeric(15,2);not null" json:"total_credit"`
+	Filename        string     `gorm:"type:varchar(255)" json:"filename,omitempty"`
+	ContentType     string     `gorm:"type:varchar(100)" json:"content_type,omitempty"`
+	Content         []byte     `gorm:"type:bytea" json:"-"`
+	Checksum        string     `gorm:"type:varchar(64)" json:"checksum,omitempty"`
+	ExportedBy      uuid.UUID  `gorm:"type:uuid;not null" json:"exported_by"`
+	ExportedAt      time.Time  `gorm:"not null" json:"exported_at"`
+}
+
+// JournalExportEntry links an exported entry to the file export that included it. Entries are only linked
+// once per format outside re-exports, which makes a second export of the same entry fail.
+type JournalExportEntry struct {
+	ExportID   uuid.UUID `gorm:"type:uuid;primary_key" json:"export_id"`
+	EntryID    uuid.UUID `gorm:"type:uuid;primary_key" json:"entry_id"`
+	Format     string    `gorm:"type:varchar(10);not null" json:"format"`
+	IsReexport bool      `gorm:"not null;default:false" json:"is_reexport"`
+}
+
+// CreateJournalExportRequest represents request to export a period of a journal as a file
+type CreateJournalExportRequest struct {
+	Format          string `json:"format" binding:"required,oneof=csv fec"`
+	From            string `json:"from" binding:"required"`
+	To              string `json:"to" binding:"required"`
+	Journal         string `json:"journal" binding:"omitempty,max=10"`
+	IncludeExported bool   `json:"include_exported"`
+}
+
+// JournalExportListQuery represents query parameters for listing journal exports
+type JournalExportListQuery struct {
+	Format  string `form:"format"`
+	Journal string `form:"journal"`
+	Limit   int    `form:"limit"`
+	Offset  int    `form:"offset"`
+}
+
+// JournalFeedQuery represents query parameters for the JSON journal feed
+type JournalFeedQuery struct {
+	Cursor  string `form:"cursor"`
+	Journal string `form:"journal"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// JournalFeedPage is one page of the JSON journal feed. Passing NextCursor back returns the entries posted after
+// this page; it is returned even when the page is empty, so a consumer can keep polling from where it stopped.
+type JournalFeedPage struct {
+	ExportID   *uuid.UUID         `json:"export_id,omitempty"`
+	Entries    []JournalFeedEntry `json:"entries"`
+	NextCursor string             `json:"next_cursor"`
+	HasMore    bool               `json:"has_more"`
+}
+
+// JournalFeedEntry is a journal entry as published in the feed, with account codes mapped for the external software
+type JournalFeedEntry struct {
+	ID          uuid.UUID         `json:"id"`
+	EntryNumber string            `json:"entry_number"`
+	Journal     string            `json:"journal"`
+	EntryDate   string            `json:"entry_date"`
+	Description string            `json:"description"`
+	SourceType  string            `json:"source_type"`
+	SourceID    *uuid.UUID        `json:"source_id,omitempty"`
+	ReversalOf  *uuid.UUID        `json:"reversal_of,omitempty"`
+	PostedAt    time.Time         `json:"posted_at"`
+	TotalDebit  float64           `json:"total_debit"`
+	TotalCredit float64           `json:"total_credit"`
+	Lines       []JournalFeedLine `json:"lines"`
+}
+
+// JournalFeedLine is one line of a feed entry
+type JournalFeedLine struct {
+	LineNumber        int     `json:"line_number"`
+	AccountCode       string  `json:"account_code"`
+	AccountName       string  `json:"account_name"`
+	LedgerAccountCode string  `json:"ledger_account_code"`
+	Debit             float64 `json:"debit"`
+	Credit            float64 `json:"credit"`
+	Description       string  `json:"description,omitempty"`
+}
+
+// TableName specifies the table name for AccountMapping model
+func (AccountMapping) TableName() string {
+	return "ledger_account_mappings"
+}
+
+// TableName specifies the table name for JournalExport model
+func (JournalExport) TableName() string {
+	return "journal_exports"
+}
+
+// TableName specifies the table name for JournalExportEntry model
+func (JournalExportEntry) TableName() string {
+	return "journal_export_entries"
+}
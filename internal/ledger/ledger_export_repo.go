@@ -0,0 +1,353 @@
+package ledger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-server/internal/company"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errInvalidCursor rejects a feed cursor that was not returned by the feed
+var errInvalidCursor = errors.New("invalid cursor")
+
+// ListAccountMappings retrieves the account mappings in account code order
+func (r *Repo) ListAccountMappings(ctx context.Context) ([]AccountMapping, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var mappings []AccountMapping
+	if err := r.db.WithContext(ctx).Order("account_code ASC").Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("list account mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+// SetAccountMapping creates or replaces the mapping of a ledger account
+func (r *Repo) SetAccountMapping(ctx context.Context, mapping *AccountMapping) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	mapping.UpdatedAt = time.Now()
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"external_code", "external_name", "updated_at", "updated_by"}),
+	}).Create(mapping).Error; err != nil {
+		return fmt.Errorf("save account mapping: %w", err)
+	}
+	return nil
+}
+
+// DeleteAccountMapping removes the mapping of a ledger account, which is then exported under its own code
+func (r *Repo) DeleteAccountMapping(ctx context.Context, accountCode string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Where("account_code = ?", accountCode).Delete(&AccountMapping{})
+	if result.Error != nil {
+		return fmt.Errorf("delete account mapping: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("account mapping not found")
+	}
+	return nil
+}
+
+// getAccountMapper loads the account mappings for an export
+func getAccountMapper(tx *gorm.DB) (accountMapper, error) {
+	var mappings []AccountMapping
+	if err := tx.Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("get account mappings: %w", err)
+	}
+	mapper := accountMapper{}
+	for _, mapping := range mappings {
+		mapper[mapping.AccountCode] = mapping
+	}
+	return mapper, nil
+}
+
+// CreateExport exports the entries of a journal dated between from and to inclusive as a CSV or FEC file and
+// records the export with its content. Entries already exported in the format are left out unless
+// includeExported is set, so each file only carries entries the accounting software has not imported yet.
+func (r *Repo) CreateExport(ctx context.Context, format, journal string, from, to time.Time, includeExported bool, exportedBy uuid.UUID) (*JournalExport, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	journal = exportJournal(journal)
+	from, to = dateOnly(from), dateOnly(to)
+
+	var export *JournalExport
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		db := tx.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("line_number ASC")
+		}).Where("journal = ? AND entry_date >= ? AND entry_date <= ?", journal, from, to)
+		if !includeExported {
+			db = db.Where("NOT EXISTS (SELECT 1 FROM journal_export_entries ee WHERE ee.entry_id = journal_entries.id AND ee.format = ?)", format)
+		}
+
+		var entries []JournalEntry
+		if err := db.Order("entry_date ASC, entry_number ASC").Find(&entries).Error; err != nil {
+			return fmt.Errorf("get journal entries to export: %w", err)
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no %s journal entries to export between %s and %s", journal, from.Format("2006-01-02"), to.Format("2006-01-02"))
+		}
+
+		mapper, err := getAccountMapper(tx)
+		if err != nil {
+			return err
+		}
+
+		export = &JournalExport{
+			Format:          format,
+			Journal:         journal,
+			PeriodStart:     &from,
+			PeriodEnd:       &to,
+			IncludeExported: includeExported,
+			ExportedBy:      exportedBy,
+			ExportedAt:      time.Now(),
+		}
+		summarizeExport(export, entries)
+
+		switch format {
+		case ExportFormatCSV:
+			export.Filename = fmt.Sprintf("journal-%s-%s-%s.csv", journal, from.Format("20060102"), to.Format("20060102"))
+			export.ContentType = "text/csv; charset=utf-8"
+			export.Content, err = renderJournalCSV(entries, mapper)
+			if err != nil {
+				return fmt.Errorf("render journal csv: %w", err)
+			}
+		case ExportFormatFEC:
+			nif, err := companyNIF(tx)
+			if err != nil {
+				return err
+			}
+			export.Filename = fmt.Sprintf("%sFEC%s.txt", nif, to.Format("20060102"))
+			export.ContentType = "text/plain; charset=utf-8"
+			export.Content = renderJournalFEC(entries, mapper)
+		default:
+			return fmt.Errorf("unsupported export format %q", format)
+		}
+		sum := sha256.Sum256(export.Content)
+		export.Checksum = hex.EncodeToString(sum[:])
+
+		if err := tx.Create(export).Error; err != nil {
+			return fmt.Errorf("create journal export: %w", err)
+		}
+		return recordExportedEntries(tx, export, entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// recordExportedEntries links the entries of a file export to it, marking those already exported in the format
+// as re-exports. Concurrent exports of the same entries fail on the first-export unique index.
+func recordExportedEntries(tx *gorm.DB, export *JournalExport, entries []JournalEntry) error {
+	ids := make([]uuid.UUID, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+
+	var exported []uuid.UUID
+	if err := tx.Model(&JournalExportEntry{}).Where("format = ? AND NOT is_reexport AND entry_id IN ?", export.Format, ids).
+		Pluck("entry_id", &exported).Error; err != nil {
+		return fmt.Errorf("get exported journal entries: %w", err)
+	}
+	already := map[uuid.UUID]bool{}
+	for _, id := range exported {
+		already[id] = true
+	}
+
+	links := make([]JournalExportEntry, len(entries))
+	for i, entry := range entries {
+		links[i] = JournalExportEntry{ExportID: export.ID, EntryID: entry.ID, Format: export.Format, IsReexport: already[entry.ID]}
+	}
+	if err := tx.CreateInBatches(links, 500).Error; err != nil {
+		return fmt.Errorf("record exported journal entries: %w", err)
+	}
+	return nil
+}
+
+// GetExportByID retrieves a journal export with its content
+func (r *Repo) GetExportByID(ctx context.Context, id uuid.UUID) (*JournalExport, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var export JournalExport
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&export).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("journal export not found")
+		}
+		return nil, fmt.Errorf("get journal export: %w", err)
+	}
+	return &export, nil
+}
+
+// ListExports retrieves the export log, most recent first, without file contents
+func (r *Repo) ListExports(ctx context.Context, query JournalExportListQuery) ([]JournalExport, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Model(&JournalExport{})
+	if query.Format != "" {
+		db = db.Where("format = ?", query.Format)
+	}
+	if query.Journal != "" {
+		db = db.Where("journal = ?", strings.ToUpper(query.Journal))
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count journal exports: %w", err)
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = 50
+	}
+
+	var exports []JournalExport
+	if err := db.Omit("content").Limit(limit).Offset(query.Offset).Order("exported_at DESC").Find(&exports).Error; err != nil {
+		return nil, 0, fmt.Errorf("list journal exports: %w", err)
+	}
+	return exports, total, nil
+}
+
+// GetFeedPage returns the entries of a journal posted after a cursor, oldest first, and records the page in the
+// export log. Entries are only published once they are older than feedSettleDelay.
+func (r *Repo) GetFeedPage(ctx context.Context, query JournalFeedQuery, exportedBy uuid.UUID) (*JournalFeedPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = 100
+	}
+	journal := exportJournal(query.Journal)
+
+	db := r.db.WithContext(ctx).Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line_number ASC")
+	}).Where("journal = ? AND posted_at < ?", journal, time.Now().Add(-feedSettleDelay))
+	if query.Cursor != "" {
+		postedAt, id, err := decodeFeedCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("(posted_at, id) > (?, ?)", postedAt, id)
+	}
+
+	var entries []JournalEntry
+	if err := db.Order("posted_at ASC, id ASC").Limit(limit + 1).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("get journal feed: %w", err)
+	}
+
+	page := &JournalFeedPage{Entries: []JournalFeedEntry{}, NextCursor: query.Cursor}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		page.HasMore = true
+	}
+	if len(entries) == 0 {
+		return page, nil
+	}
+
+	mapper, err := getAccountMapper(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		page.Entries = append(page.Entries, journalFeedEntry(entry, mapper))
+	}
+	last := entries[len(entries)-1]
+	page.NextCursor = encodeFeedCursor(last.PostedAt, last.ID)
+
+	export := &JournalExport{
+		Format:     ExportFormatJSON,
+		Journal:    journal,
+		Cursor:     query.Cursor,
+		NextCursor: page.NextCursor,
+		ExportedBy: exportedBy,
+		ExportedAt: time.Now(),
+	}
+	summarizeExport(export, entries)
+	if err := r.db.WithContext(ctx).Create(export).Error; err != nil {
+		return nil, fmt.Errorf("create journal export: %w", err)
+	}
+	page.ExportID = &export.ID
+	return page, nil
+}
+
+// summarizeExport sets the entry count, entry number range and totals of an export
+func summarizeExport(export *JournalExport, entries []JournalEntry) {
+	export.EntryCount = len(entries)
+	export.FirstEntry = entries[0].EntryNumber
+	export.LastEntry = entries[len(entries)-1].EntryNumber
+	for _, entry := range entries {
+		export.TotalDebit += entry.TotalDebit
+		export.TotalCredit += entry.TotalCredit
+	}
+	export.TotalDebit = roundAmount(export.TotalDebit)
+	export.TotalCredit = roundAmount(export.TotalCredit)
+}
+
+// exportJournal returns the journal to export, the payroll journal by default
+func exportJournal(journal string) string {
+	if journal == "" {
+		return JournalPayroll
+	}
+	return strings.ToUpper(journal)
+}
+
+// companyNIF returns the company tax number that prefixes FEC file names, without spaces or separators
+func companyNIF(tx *gorm.DB) (string, error) {
+	var settings company.CompanySettings
+	if err := tx.Select("company_nif").First(&settings).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return "", fmt.Errorf("get company settings: %w", err)
+	}
+	if settings.CompanyNIF == nil {
+		return "", nil
+	}
+	return strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') {
+			return r
+		}
+		return -1
+	}, *settings.CompanyNIF), nil
+}
+
+// encodeFeedCursor encodes the position of an entry in the feed as an opaque token
+func encodeFeedCursor(postedAt time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(postedAt.UnixMicro(), 10) + "_" + id.String()))
+}
+
+// decodeFeedCursor decodes a cursor returned by encodeFeedCursor
+func decodeFeedCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+	micros, idPart, ok := strings.Cut(string(raw), "_")
+	if !ok {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+	unixMicro, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return time.Time{}, uuid.Nil, errInvalidCursor
+	}
+	return time.UnixMicro(unixMicro), id, nil
+}
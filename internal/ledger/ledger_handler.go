@@ -0,0 +1,250 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler handles general ledger requests
+type Handler struct {
+	repo *Repo
+}
+
+// NewHandler creates a new ledger handler
+func NewHandler(repo *Repo) *Handler {
+	return &Handler{repo: repo}
+}
+
+// PostJournalEntry posts a manual journal entry (Accountant/Admin, HR outside control accounts)
+func (h *Handler) PostJournalEntry(c *gin.Context) {
+	var input PostJournalEntryRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Accountant or HR role; control accounts are checked when posting
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" && userRole != "hr" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can post journal entries"})
+		return
+	}
+	if input.Journal == JournalPayroll {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The PAIE journal is reserved for approved payroll"})
+		return
+	}
+
+	entry := &JournalEntry{
+		Journal:     input.Journal,
+		EntryDate:   input.EntryDate,
+		Description: input.Description,
+		SourceType:  SourceManual,
+		PostedBy:    userID,
+	}
+	for _, line := range input.Lines {
+		entry.Lines = append(entry.Lines, JournalLine{
+			AccountCode: line.AccountCode,
+			AccountName: line.AccountName,
+			Debit:       line.Debit,
+			Credit:      line.Credit,
+			Description: line.Description,
+		})
+	}
+
+	if err := h.repo.PostEntry(c.Request.Context(), entry); err != nil {
+		if errors.Is(err, ErrControlAccount) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// ReverseJournalEntry posts the reversal of a journal entry (Accountant only)
+func (h *Handler) ReverseJournalEntry(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid journal entry ID"})
+		return
+	}
+
+	var input ReverseJournalEntryRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can reverse journal entries"})
+		return
+	}
+
+	if _, err := h.repo.GetEntryByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Journal entry not found"})
+		return
+	}
+
+	reversal, err := h.repo.ReverseEntry(c.Request.Context(), id, userID, input.EntryDate, input.Reason)
+	if err != nil {
+		if errors.Is(err, ErrControlAccount) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, errNotManualEntry) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reversal)
+}
+
+// GetJournalEntry retrieves a journal entry with its lines
+func (h *Handler) GetJournalEntry(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid journal entry ID"})
+		return
+	}
+
+	entry, err := h.repo.GetEntryByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Journal entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// ListJournalEntries retrieves journal entries with filtering
+func (h *Handler) ListJournalEntries(c *gin.Context) {
+	var query JournalEntryListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	from, to, err := parsePeriod(query.From, query.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries, total, err := h.repo.ListEntries(c.Request.Context(), query, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list journal entries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"total":   total,
+		"limit":   query.Limit,
+		"offset":  query.Offset,
+	})
+}
+
+// GetTrialBalance totals the debits and credits of every account over a period
+func (h *Handler) GetTrialBalance(c *gin.Context) {
+	var query TrialBalanceQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	from, to, err := parsePeriod(query.From, query.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	balance, err := h.repo.GetTrialBalance(c.Request.Context(), from, to, query.Journal)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate trial balance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetAccountLedger lists the postings to an account and its sub-accounts over a period
+func (h *Handler) GetAccountLedger(c *gin.Context) {
+	accountCode := c.Param("code")
+	if !isAccountCode(accountCode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account code"})
+		return
+	}
+
+	var query AccountLedgerQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	from, to, err := parsePeriod(query.From, query.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ledger, err := h.repo.GetAccountLedger(c.Request.Context(), accountCode, from, to, query.Journal)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate account ledger"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ledger)
+}
+
+// parsePeriod parses optional YYYY-MM-DD bounds of a period
+func parsePeriod(fromParam, toParam string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromParam != "" {
+		t, err := time.Parse("2006-01-02", fromParam)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid from format, use YYYY-MM-DD")
+		}
+		from = &t
+	}
+	if toParam != "" {
+		t, err := time.Parse("2006-01-02", toParam)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid to format, use YYYY-MM-DD")
+		}
+		to = &t
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, nil, fmt.Errorf("to cannot be before from")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,174 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Journals that entries are posted to
+const (
+	JournalPayroll       = "PAIE"
+	JournalMiscellaneous = "OD"
+)
+
+// Source types of journal entries
+const (
+	SourceManual          = "manual"
+	SourcePayrollApproved = "payroll_approved"
+)
+
+// ControlAccounts are the social and tax liability accounts (CNAPS, IRSA, OSTIE) that only Accountants and Admins
+// may post to, including their sub-accounts (PRD §8.1)
+var ControlAccounts = []string{"431", "437", "438"}
+
+// JournalEntry is a balanced accounting entry: the debits of its lines equal their credits.
+// Entries are immutable once posted; a mistake is corrected by posting the entry's reversal.
+type JournalEntry struct {
+	ID          uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EntryNumber string        `gorm:"type:varchar(50);unique;not null" json:"entry_number"`
+	Journal     string        `gorm:"type:varchar(10);not null;index" json:"journal"`
+	EntryDate   time.Time     `gorm:"type:date;not null;index" json:"entry_date"`
+	Description string        `gorm:"type:text;not null" json:"description"`
+	SourceType  string        `gorm:"type:varchar(50);not null" json:"source_type"`
+	SourceID    *uuid.UUID    `gorm:"type:uuid" json:"source_id,omitempty"`
+	ReversalOf  *uuid.UUID    `gorm:"type:uuid" json:"reversal_of,omitempty"`
+	ReversedBy  *uuid.UUID    `gorm:"type:uuid" json:"reversed_by,omitempty"`
+	TotalDebit  float64       `gorm:"type:numeric(15,2);not null" json:"total_debit"`
+	TotalCredit float64       `gorm:"type:numeric(15,2);not null" json:"total_credit"`
+	PostedBy    uuid.UUID     `gorm:"type:uuid;not null" json:"posted_by"`
+	PostedAt    time.Time     `gorm:"not null" json:"posted_at"`
+	Lines       []JournalLine `gorm:"foreignKey:EntryID" json:"lines,omitempty"`
+}
+
+// JournalLine debits or credits one account within a journal entry
+type JournalLine struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EntryID     uuid.UUID `gorm:"type:uuid;not null;index" json:"entry_id"`
+	LineNumber  int       `gorm:"not null" json:"line_number"`
+	AccountCode string    `gorm:"type:varchar(20);not null;index" json:"account_code"`
+	AccountName string    `gorm:"type:varchar(255);not null" json:"account_name"`
+	Debit       float64   `gorm:"type:numeric(15,2);not null;default:0" json:"debit"`
+	Credit      float64   `gorm:"type:numeric(15,2);not null;default:0" json:"credit"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+}
+
+// PostJournalEntryRequest represents request to post a manual journal entry
+type PostJournalEntryRequest struct {
+	Journal     string               `json:"journal" binding:"omitempty,max=10"`
+	EntryDate   time.Time            `json:"entry_date" binding:"required"`
+	Description string               `json:"description" binding:"required"`
+	Lines       []JournalLineRequest `json:"lines" binding:"required,min=2,dive"`
+}
+
+// JournalLineRequest is one line of a manual journal entry; exactly one of debit and credit is set
+type JournalLineRequest struct {
+	AccountCode string  `json:"account_code" binding:"required,numeric,max=20"`
+	AccountName string  `json:"account_name" binding:"required,max=255"`
+	Debit       float64 `json:"debit" binding:"min=0"`
+	Credit      float64 `json:"credit" binding:"min=0"`
+	Description string  `json:"description"`
+}
+
+// ReverseJournalEntryRequest represents request to reverse a journal entry
+type ReverseJournalEntryRequest struct {
+	EntryDate *time.Time `json:"entry_date"`
+	Reason    string     `json:"reason" binding:"required"`
+}
+
+// JournalEntryListQuery represents query parameters for listing journal entries
+type JournalEntryListQuery struct {
+	Journal     string `form:"journal"`
+	AccountCode string `form:"account_code"`
+	SourceType  string `form:"source_type"`
+	From        string `form:"from"`
+	To          string `form:"to"`
+	Limit       int    `form:"limit"`
+	Offset      int    `form:"offset"`
+}
+
+// TrialBalanceQuery represents query parameters for the trial balance; dates are YYYY-MM-DD and inclusive
+type TrialBalanceQuery struct {
+	From    string `form:"from"`
+	To      string `form:"to"`
+	Journal string `form:"journal"`
+}
+
+// TrialBalanceAccount is the movement of one account over the trial balance period.
+// Balance is debit minus credit: liabilities such as 431 show a negative balance.
+type TrialBalanceAccount struct {
+	AccountCode string  `json:"account_code"`
+	AccountName string  `json:"account_name"`
+	Debit       float64 `json:"debit"`
+	Credit      float64 `json:"credit"`
+	Balance     float64 `json:"balance"`
+}
+
+// TrialBalance lists the movement of every account posted to in a period
+type TrialBalance struct {
+	From        *time.Time            `json:"from,omitempty"`
+	To          *time.Time            `json:"to,omitempty"`
+	Journal     string                `json:"journal,omitempty"`
+	Accounts    []TrialBalanceAccount `json:"accounts"`
+	TotalDebit  float64               `json:"total_debit"`
+	TotalCredit float64               `json:"total_credit"`
+	Balanced    bool                  `json:"balanced"`
+}
+
+// Account returns the movement of an account and its sub-accounts, e.g. 431 covers 4311 and 4312
+func (t *TrialBalance) Account(code string) TrialBalanceAccount {
+	total := TrialBalanceAccount{AccountCode: code}
+	for _, account := range t.Accounts {
+		if isSubAccount(account.AccountCode, code) {
+			total.Debit += account.Debit
+			total.Credit += account.Credit
+		}
+	}
+	total.Debit = roundAmount(total.Debit)
+	total.Credit = roundAmount(total.Credit)
+	total.Balance = roundAmount(total.Debit - total.Credit)
+	return total
+}
+
+// AccountLedgerQuery represents query parameters for an account ledger; dates are YYYY-MM-DD and inclusive
+type AccountLedgerQuery struct {
+	From    string `form:"from"`
+	To      string `form:"to"`
+	Journal string `form:"journal"`
+}
+
+// AccountLedgerLine is one posting in an account ledger with the account balance after it
+type AccountLedgerLine struct {
+	EntryID        uuid.UUID `json:"entry_id"`
+	EntryNumber    string    `json:"entry_number"`
+	Journal        string    `json:"journal"`
+	EntryDate      time.Time `json:"entry_date"`
+	AccountCode    string    `json:"account_code"`
+	AccountName    string    `json:"account_name"`
+	Description    string    `json:"description"`
+	Debit          float64   `json:"debit"`
+	Credit         float64   `json:"credit"`
+	RunningBalance float64   `json:"running_balance"`
+}
+
+// AccountLedger lists the postings to an account and its sub-accounts over a period, in date order
+type AccountLedger struct {
+	AccountCode    string              `json:"account_code"`
+	From           *time.Time          `json:"from,omitempty"`
+	To             *time.Time          `json:"to,omitempty"`
+	OpeningBalance float64             `json:"opening_balance"`
+	Lines          []AccountLedgerLine `json:"lines"`
+	TotalDebit     float64             `json:"total_debit"`
+	TotalCredit    float64             `json:"total_credit"`
+	ClosingBalance float64             `json:"closing_balance"`
+}
+
+// TableName specifies the table name for JournalEntry model
+func (JournalEntry) TableName() string {
+	return "journal_entries"
+}
+
+// TableName specifies the table name for JournalLine model
+func (JournalLine) TableName() string {
+	return "journal_lines"
+}
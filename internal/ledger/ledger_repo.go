@@ -0,0 +1,363 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go-server/internal/auth"
+	"go-server/internal/sequence"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrControlAccount rejects a posting to a control account by someone other than an Accountant or Admin
+var ErrControlAccount = errors.New("only Accountant can post to accounts 431, 437 and 438")
+
+// errNotManualEntry rejects reversing an entry posted by another module, which must be corrected at its source
+var errNotManualEntry = errors.New("only manual journal entries can be reversed")
+
+// Repo handles general ledger database operations
+type Repo struct {
+	db *gorm.DB
+}
+
+// NewRepo creates a new ledger repository
+func NewRepo(database *gorm.DB) *Repo {
+	return &Repo{db: database}
+}
+
+// PostEntry validates and posts a journal entry with its lines
+func (r *Repo) PostEntry(ctx context.Context, entry *JournalEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return Post(tx, entry)
+	})
+}
+
+// Post validates a journal entry and stores it with its lines inside the caller's transaction, so a document
+// and its accounting entry are committed together. Amounts are rounded to the cent; every line must either debit
+// or credit a positive amount, and the entry is rejected unless its debits equal its credits. Lines on control
+// accounts are only accepted from Accountants and Admins, whose role is read from the poster's user record.
+func Post(tx *gorm.DB, entry *JournalEntry) error {
+	if len(entry.Lines) < 2 {
+		return fmt.Errorf("a journal entry needs at least two lines")
+	}
+	if entry.Journal == "" {
+		entry.Journal = JournalMiscellaneous
+	}
+	entry.Journal = strings.ToUpper(entry.Journal)
+	if entry.SourceType == "" {
+		entry.SourceType = SourceManual
+	}
+
+	var totalDebit, totalCredit float64
+	touchesControlAccount := false
+	for i := range entry.Lines {
+		line := &entry.Lines[i]
+		line.LineNumber = i + 1
+		line.Debit = roundAmount(line.Debit)
+		line.Credit = roundAmount(line.Credit)
+		if line.AccountCode == "" {
+			return fmt.Errorf("line %d: account code is required", line.LineNumber)
+		}
+		if line.Debit < 0 || line.Credit < 0 {
+			return fmt.Errorf("line %d: amounts cannot be negative", line.LineNumber)
+		}
+		if (line.Debit > 0) == (line.Credit > 0) {
+			return fmt.Errorf("line %d: set either a debit or a credit", line.LineNumber)
+		}
+		if IsControlAccount(line.AccountCode) {
+			touchesControlAccount = true
+		}
+		totalDebit += line.Debit
+		totalCredit += line.Credit
+	}
+	entry.TotalDebit = roundAmount(totalDebit)
+	entry.TotalCredit = roundAmount(totalCredit)
+	if entry.TotalDebit != entry.TotalCredit {
+		return fmt.Errorf("journal entry is unbalanced: debits %.2f, credits %.2f", entry.TotalDebit, entry.TotalCredit)
+	}
+
+	if touchesControlAccount {
+		var roles []string
+		if err := tx.Model(&auth.User{}).Where("id = ?", entry.PostedBy).Pluck("role", &roles).Error; err != nil {
+			return fmt.Errorf("get poster role: %w", err)
+		}
+		if len(roles) == 0 || (roles[0] != "accountant" && roles[0] != "admin") {
+			return ErrControlAccount
+		}
+	}
+
+	entry.EntryDate = dateOnly(entry.EntryDate)
+	fiscalYear, seq, err := sequence.Next(tx, sequence.Journal(entry.Journal), entry.EntryDate)
+	if err != nil {
+		return err
+	}
+	entry.EntryNumber = sequence.Format(entry.Journal, fiscalYear, seq)
+	entry.PostedAt = time.Now()
+
+	if err := tx.Create(entry).Error; err != nil {
+		return fmt.Errorf("create journal entry: %w", err)
+	}
+	return nil
+}
+
+// ReverseEntry posts the mirror image of an entry, debits and credits swapped, and marks the entry as reversed.
+// The reversal is dated entryDate, or today when nil, and may not precede the original entry. Only manual entries
+// can be reversed: an entry posted from approved payroll stays in step with the payslips it records.
+func (r *Repo) ReverseEntry(ctx context.Context, id uuid.UUID, postedBy uuid.UUID, entryDate *time.Time, reason string) (*JournalEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var reversal *JournalEntry
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var original JournalEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&original).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("journal entry not found")
+			}
+			return fmt.Errorf("get journal entry: %w", err)
+		}
+		if original.ReversedBy != nil {
+			return fmt.Errorf("journal entry %s is already reversed", original.EntryNumber)
+		}
+		if original.ReversalOf != nil {
+			return fmt.Errorf("journal entry %s is a reversal and cannot be reversed", original.EntryNumber)
+		}
+		if original.SourceType != SourceManual {
+			return errNotManualEntry
+		}
+		if err := tx.Where("entry_id = ?", original.ID).Order("line_number ASC").Find(&original.Lines).Error; err != nil {
+			return fmt.Errorf("get journal lines: %w", err)
+		}
+
+		date := time.Now()
+		if entryDate != nil {
+			date = *entryDate
+		}
+		if dateOnly(date).Before(dateOnly(original.EntryDate)) {
+			return fmt.Errorf("a reversal cannot be dated before the entry it reverses")
+		}
+
+		reversal = &JournalEntry{
+			Journal:     original.Journal,
+			EntryDate:   date,
+			Description: fmt.Sprintf("Extourne de %s : %s", original.EntryNumber, reason),
+			SourceType:  original.SourceType,
+			SourceID:    original.SourceID,
+			ReversalOf:  &original.ID,
+			PostedBy:    postedBy,
+		}
+		for _, line := range original.Lines {
+			reversal.Lines = append(reversal.Lines, JournalLine{
+				AccountCode: line.AccountCode,
+				AccountName: line.AccountName,
+				Debit:       line.Credit,
+				Credit:      line.Debit,
+				Description: line.Description,
+			})
+		}
+		if err := Post(tx, reversal); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&JournalEntry{}).Where("id = ?", original.ID).Update("reversed_by", reversal.ID).Error; err != nil {
+			return fmt.Errorf("mark journal entry reversed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reversal, nil
+}
+
+// GetEntryByID retrieves a journal entry with its lines
+func (r *Repo) GetEntryByID(ctx context.Context, id uuid.UUID) (*JournalEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var entry JournalEntry
+	if err := r.db.WithContext(ctx).Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line_number ASC")
+	}).Where("id = ?", id).First(&entry).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("journal entry not found")
+		}
+		return nil, fmt.Errorf("get journal entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// ListEntries retrieves journal entries with their lines, most recent first
+func (r *Repo) ListEntries(ctx context.Context, query JournalEntryListQuery, from, to *time.Time) ([]JournalEntry, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Model(&JournalEntry{})
+	if query.Journal != "" {
+		db = db.Where("journal = ?", strings.ToUpper(query.Journal))
+	}
+	if query.SourceType != "" {
+		db = db.Where("source_type = ?", query.SourceType)
+	}
+	if query.AccountCode != "" {
+		db = db.Where("id IN (?)", r.db.Model(&JournalLine{}).Select("entry_id").
+			Where("account_code LIKE ?", likePrefix(query.AccountCode)))
+	}
+	if from != nil {
+		db = db.Where("entry_date >= ?", *from)
+	}
+	if to != nil {
+		db = db.Where("entry_date <= ?", *to)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count journal entries: %w", err)
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = 50
+	}
+
+	var entries []JournalEntry
+	if err := db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line_number ASC")
+	}).Limit(limit).Offset(query.Offset).Order("entry_date DESC, entry_number DESC").Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("list journal entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+// GetTrialBalance totals the debits and credits of every account posted to between from and to inclusive,
+// optionally within one journal. Either bound may be nil for an open-ended period.
+func (r *Repo) GetTrialBalance(ctx context.Context, from, to *time.Time, journal string) (*TrialBalance, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	balance := &TrialBalance{From: from, To: to, Journal: strings.ToUpper(journal), Accounts: []TrialBalanceAccount{}}
+
+	db := r.linesQuery(ctx, from, to, journal).
+		Select("journal_lines.account_code, MAX(journal_lines.account_name) AS account_name, " +
+			"SUM(journal_lines.debit) AS debit, SUM(journal_lines.credit) AS credit").
+		Group("journal_lines.account_code").
+		Order("journal_lines.account_code ASC")
+	if err := db.Scan(&balance.Accounts).Error; err != nil {
+		return nil, fmt.Errorf("get trial balance: %w", err)
+	}
+
+	for i := range balance.Accounts {
+		account := &balance.Accounts[i]
+		account.Balance = roundAmount(account.Debit - account.Credit)
+		balance.TotalDebit += account.Debit
+		balance.TotalCredit += account.Credit
+	}
+	balance.TotalDebit = roundAmount(balance.TotalDebit)
+	balance.TotalCredit = roundAmount(balance.TotalCredit)
+	balance.Balanced = balance.TotalDebit == balance.TotalCredit
+	return balance, nil
+}
+
+// GetAccountLedger lists the postings to an account and its sub-accounts between from and to inclusive,
+// with the balance brought forward from before the period and the running balance after each posting
+func (r *Repo) GetAccountLedger(ctx context.Context, accountCode string, from, to *time.Time, journal string) (*AccountLedger, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	ledger := &AccountLedger{AccountCode: accountCode, From: from, To: to, Lines: []AccountLedgerLine{}}
+
+	if from != nil {
+		before := from.AddDate(0, 0, -1)
+		var opening struct{ Balance float64 }
+		if err := r.linesQuery(ctx, nil, &before, journal).
+			Select("COALESCE(SUM(journal_lines.debit - journal_lines.credit), 0) AS balance").
+			Where("journal_lines.account_code LIKE ?", likePrefix(accountCode)).
+			Scan(&opening).Error; err != nil {
+			return nil, fmt.Errorf("get opening balance: %w", err)
+		}
+		ledger.OpeningBalance = roundAmount(opening.Balance)
+	}
+
+	if err := r.linesQuery(ctx, from, to, journal).
+		Select("journal_entries.id AS entry_id, journal_entries.entry_number, journal_entries.journal, journal_entries.entry_date, "+
+			"journal_lines.account_code, journal_lines.account_name, "+
+			"COALESCE(NULLIF(journal_lines.description, ''), journal_entries.description) AS description, "+
+			"journal_lines.debit, journal_lines.credit").
+		Where("journal_lines.account_code LIKE ?", likePrefix(accountCode)).
+		Order("journal_entries.entry_date ASC, journal_entries.entry_number ASC, journal_lines.line_number ASC").
+		Scan(&ledger.Lines).Error; err != nil {
+		return nil, fmt.Errorf("get account ledger: %w", err)
+	}
+
+	running := ledger.OpeningBalance
+	for i := range ledger.Lines {
+		line := &ledger.Lines[i]
+		running = roundAmount(running + line.Debit - line.Credit)
+		line.RunningBalance = running
+		ledger.TotalDebit += line.Debit
+		ledger.TotalCredit += line.Credit
+	}
+	ledger.TotalDebit = roundAmount(ledger.TotalDebit)
+	ledger.TotalCredit = roundAmount(ledger.TotalCredit)
+	ledger.ClosingBalance = running
+	return ledger, nil
+}
+
+// linesQuery selects journal lines joined to their entries, filtered on entry date and journal
+func (r *Repo) linesQuery(ctx context.Context, from, to *time.Time, journal string) *gorm.DB {
+	db := r.db.WithContext(ctx).Table("journal_lines").
+		Joins("JOIN journal_entries ON journal_entries.id = journal_lines.entry_id")
+	if from != nil {
+		db = db.Where("journal_entries.entry_date >= ?", dateOnly(*from))
+	}
+	if to != nil {
+		db = db.Where("journal_entries.entry_date <= ?", dateOnly(*to))
+	}
+	if journal != "" {
+		db = db.Where("journal_entries.journal = ?", strings.ToUpper(journal))
+	}
+	return db
+}
+
+// IsControlAccount reports whether an account is, or is a sub-account of, one of the control accounts
+func IsControlAccount(accountCode string) bool {
+	for _, code := range ControlAccounts {
+		if isSubAccount(accountCode, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubAccount reports whether accountCode is parent or one of its sub-accounts
+func isSubAccount(accountCode, parent string) bool {
+	return strings.HasPrefix(accountCode, parent)
+}
+
+// roundAmount rounds an amount to the cent
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// dateOnly truncates a time to midnight UTC of its calendar day
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// likeEscaper escapes the LIKE wildcards with PostgreSQL's default escape character, the backslash
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// likePrefix builds a LIKE pattern matching values that start with prefix taken literally
+func likePrefix(prefix string) string {
+	return likeEscaper.Replace(prefix) + "%"
+}
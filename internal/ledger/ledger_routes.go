@@ -0,0 +1,38 @@
+package ledger
+
+import (
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterRoutes registers general ledger routes
+func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
+	repo := NewRepo(gormDB)
+	handler := NewHandler(repo)
+
+	ledger := rg.Group("/ledger")
+	ledger.Use(middleware.AuthMiddleware())
+	{
+		// Journal entries (HR may post outside accounts 431/437/438, Accountant/Admin only for the rest)
+		ledger.POST("/entries", middleware.RequireRole("admin", "accountant", "hr"), handler.PostJournalEntry)
+		ledger.GET("/entries", middleware.RequireRole("admin", "accountant"), handler.ListJournalEntries)
+		ledger.GET("/entries/:id", middleware.RequireRole("admin", "accountant"), handler.GetJournalEntry)
+		ledger.POST("/entries/:id/reverse", middleware.RequireRole("admin", "accountant"), handler.ReverseJournalEntry)
+
+		// Reports (Accountant/Admin only)
+		ledger.GET("/trial-balance", middleware.RequireRole("admin", "accountant"), handler.GetTrialBalance)
+		ledger.GET("/accounts/:code", middleware.RequireRole("admin", "accountant"), handler.GetAccountLedger)
+
+		// Accounting software integration: account mappings, file exports and the JSON feed (Accountant/Admin only)
+		ledger.GET("/account-mappings", middleware.RequireRole("admin", "accountant"), handler.ListAccountMappings)
+		ledger.PUT("/account-mappings/:code", middleware.RequireRole("admin", "accountant"), handler.SetAccountMapping)
+		ledger.DELETE("/account-mappings/:code", middleware.RequireRole("admin", "accountant"), handler.DeleteAccountMapping)
+		ledger.POST("/exports", middleware.RequireRole("admin", "accountant"), handler.CreateJournalExport)
+		ledger.GET("/exports", middleware.RequireRole("admin", "accountant"), handler.ListJournalExports)
+		ledger.GET("/exports/:id", middleware.RequireRole("admin", "accountant"), handler.GetJournalExport)
+		ledger.GET("/exports/:id/download", middleware.RequireRole("admin", "accountant"), handler.DownloadJournalExport)
+		ledger.GET("/feed", middleware.RequireRole("admin", "accountant"), handler.GetJournalFeed)
+	}
+}
@@ -0,0 +1,85 @@
+// Package mailer sends plain-text emails through the SMTP server configured in the environment.
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured is returned when SMTP_HOST is not set
+var ErrNotConfigured = errors.New("email is not configured: SMTP_HOST is not set")
+
+// Mailer sends emails through an SMTP server
+type Mailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+// FromEnv configures a mailer from SMTP_HOST, SMTP_PORT (default 587), SMTP_USER, SMTP_PASSWORD and SMTP_FROM.
+// Authentication is only used when SMTP_USER is set; SMTP_FROM defaults to SMTP_USER.
+func FromEnv() (*Mailer, error) {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil, ErrNotConfigured
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_USER")
+	}
+	if from == "" {
+		return nil, fmt.Errorf("SMTP_FROM must be set to send email")
+	}
+
+	return &Mailer{
+		addr:     net.JoinHostPort(host, port),
+		host:     host,
+		username: os.Getenv("SMTP_USER"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     from,
+	}, nil
+}
+
+// Send sends a UTF-8 plain-text email to each recipient. The connection is upgraded with STARTTLS
+// when the server offers it.
+func (m *Mailer) Send(to []string, subject, body string) error {
+	if len(to) == 0 {
+		return nil
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+	if err := smtp.SendMail(m.addr, auth, m.from, to, buildMessage(m.from, to, subject, body, time.Now())); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return nil
+}
+
+// buildMessage formats the headers and body of an email, encoding the subject for non-ASCII characters
+func buildMessage(from string, to []string, subject, body string, date time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,6 @@
+-- Drop payroll_runs table
+DROP INDEX IF EXISTS idx_payroll_drafts_run_id;
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS run_id;
+DROP INDEX IF EXISTS idx_payroll_runs_created_by;
+DROP INDEX IF EXISTS idx_payroll_runs_status;
+DROP TABLE IF EXISTS payroll_runs;
@@ -0,0 +1,27 @@
+-- Payroll Runs table (batch of drafts created by HR for one period)
+CREATE TABLE payroll_runs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  period_start DATE NOT NULL,
+  period_end DATE NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'submitted', 'approved', 'rejected')),
+  total_employees INTEGER NOT NULL DEFAULT 0,
+  total_gross_salary NUMERIC(15,2) NOT NULL DEFAULT 0,
+  total_cnaps_employee NUMERIC(15,2) NOT NULL DEFAULT 0,
+  total_cnaps_employer NUMERIC(15,2) NOT NULL DEFAULT 0,
+  total_ostie_employee NUMERIC(15,2) NOT NULL DEFAULT 0,
+  total_ostie_employer NUMERIC(15,2) NOT NULL DEFAULT 0,
+  total_irsa NUMERIC(15,2) NOT NULL DEFAULT 0,
+  total_net_salary NUMERIC(15,2) NOT NULL DEFAULT 0,
+  created_by UUID NOT NULL REFERENCES users(id),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE(period_start, period_end)
+);
+
+-- Link drafts to the run that created them
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS run_id UUID REFERENCES payroll_runs(id) ON DELETE SET NULL;
+
+-- Indexes for performance
+CREATE INDEX idx_payroll_runs_status ON payroll_runs(status);
+CREATE INDEX idx_payroll_runs_created_by ON payroll_runs(created_by);
+CREATE INDEX idx_payroll_drafts_run_id ON payroll_drafts(run_id);
@@ -0,0 +1,17 @@
+-- Drop payroll approval workflow
+DROP INDEX IF EXISTS idx_payroll_status_changes_run_id;
+DROP INDEX IF EXISTS idx_payroll_status_changes_draft_id;
+DROP INDEX IF EXISTS idx_payroll_drafts_status;
+DROP TABLE IF EXISTS payroll_status_changes;
+
+UPDATE payroll_runs SET status = 'draft' WHERE status = 'reopened';
+ALTER TABLE payroll_runs DROP CONSTRAINT IF EXISTS payroll_runs_status_check;
+ALTER TABLE payroll_runs ADD CONSTRAINT payroll_runs_status_check
+  CHECK (status IN ('draft', 'submitted', 'approved', 'rejected'));
+
+ALTER TABLE payroll_drafts DROP CONSTRAINT IF EXISTS payroll_drafts_status_check;
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS rejection_reason;
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS submitted_at;
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS status;
@@ -0,0 +1,37 @@
+-- Approval workflow status on payroll drafts
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'draft';
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMPTZ;
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id);
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+ALTER TABLE payroll_drafts ADD CONSTRAINT payroll_drafts_status_check
+  CHECK (status IN ('draft', 'submitted', 'approved', 'rejected', 'reopened'));
+
+-- Drafts approved before the workflow existed
+UPDATE payroll_drafts d
+SET status = 'approved', reviewed_by = a.accountant_id, reviewed_at = a.approved_at
+FROM payroll_approved a
+WHERE a.draft_id = d.id;
+
+-- Runs can be reopened after rejection
+ALTER TABLE payroll_runs DROP CONSTRAINT IF EXISTS payroll_runs_status_check;
+ALTER TABLE payroll_runs ADD CONSTRAINT payroll_runs_status_check
+  CHECK (status IN ('draft', 'submitted', 'approved', 'rejected', 'reopened'));
+
+-- Payroll Status Changes table (audit trail of submissions, approvals, rejections and comments)
+CREATE TABLE payroll_status_changes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  draft_id UUID REFERENCES payroll_drafts(id) ON DELETE CASCADE,
+  run_id UUID REFERENCES payroll_runs(id) ON DELETE CASCADE,
+  from_status VARCHAR(20) NOT NULL,
+  to_status VARCHAR(20) NOT NULL,
+  comment TEXT,
+  changed_by UUID NOT NULL REFERENCES users(id),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  CHECK (draft_id IS NOT NULL OR run_id IS NOT NULL)
+);
+
+-- Indexes for performance
+CREATE INDEX idx_payroll_drafts_status ON payroll_drafts(status);
+CREATE INDEX idx_payroll_status_changes_draft_id ON payroll_status_changes(draft_id);
+CREATE INDEX idx_payroll_status_changes_run_id ON payroll_status_changes(run_id);
@@ -0,0 +1,3 @@
+-- Drop payslip signature metadata
+ALTER TABLE payroll_approved DROP COLUMN IF EXISTS signature_key_id;
+ALTER TABLE payroll_approved DROP COLUMN IF EXISTS signature_algorithm;
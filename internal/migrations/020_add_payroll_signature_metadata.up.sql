@@ -0,0 +1,4 @@
+-- Signature metadata for approved payslips (Ed25519 signing)
+-- Payslips approved before signing was introduced keep the 'legacy' marker and never verify
+ALTER TABLE payroll_approved ADD COLUMN IF NOT EXISTS signature_algorithm VARCHAR(20) NOT NULL DEFAULT 'legacy';
+ALTER TABLE payroll_approved ADD COLUMN IF NOT EXISTS signature_key_id VARCHAR(32);
@@ -0,0 +1,2 @@
+-- Drop document_sequences table
+DROP TABLE IF EXISTS document_sequences;
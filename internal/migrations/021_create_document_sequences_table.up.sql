@@ -0,0 +1,24 @@
+-- Document Sequences table (gap-free per fiscal year numbering of payslips, declarations and support tickets)
+CREATE TABLE document_sequences (
+  name VARCHAR(50) NOT NULL,
+  fiscal_year INTEGER NOT NULL,
+  last_value BIGINT NOT NULL DEFAULT 0 CHECK (last_value >= 0),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (name, fiscal_year)
+);
+
+-- Continue support ticket numbering from the tickets already issued in each fiscal year, which is named after
+-- the calendar year it starts in as company_settings.fiscal_year_start (MM-DD) sets it
+WITH settings AS (
+  SELECT COALESCE((SELECT NULLIF(fiscal_year_start, '') FROM company_settings LIMIT 1), '01-01') AS fiscal_year_start
+),
+tickets AS (
+  SELECT EXTRACT(YEAR FROM t.created_at)::INTEGER
+      - CASE WHEN to_char(t.created_at, 'MM-DD') < s.fiscal_year_start THEN 1 ELSE 0 END AS fiscal_year
+  FROM support_tickets t
+  CROSS JOIN settings s
+)
+INSERT INTO document_sequences (name, fiscal_year, last_value)
+SELECT 'support_ticket', fiscal_year, COUNT(*)
+FROM tickets
+GROUP BY fiscal_year;
@@ -0,0 +1,9 @@
+-- Drop payroll_line_items table
+DROP INDEX IF EXISTS idx_payroll_line_items_type;
+DROP INDEX IF EXISTS idx_payroll_line_items_draft_id;
+DROP TABLE IF EXISTS payroll_line_items;
+
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS total_deductions;
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS non_taxable_earnings;
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS taxable_earnings;
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS base_salary;
@@ -0,0 +1,25 @@
+-- Base salary and variable element totals on payroll drafts
+-- gross_salary becomes base salary plus taxable earnings
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS base_salary NUMERIC(15,2);
+UPDATE payroll_drafts SET base_salary = gross_salary WHERE base_salary IS NULL;
+ALTER TABLE payroll_drafts ALTER COLUMN base_salary SET NOT NULL;
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS taxable_earnings NUMERIC(15,2) NOT NULL DEFAULT 0;
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS non_taxable_earnings NUMERIC(15,2) NOT NULL DEFAULT 0;
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS total_deductions NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+-- Payroll Line Items table (allowances, bonuses, advances, loan repayments and deductions)
+CREATE TABLE payroll_line_items (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  draft_id UUID NOT NULL REFERENCES payroll_drafts(id) ON DELETE CASCADE,
+  type VARCHAR(30) NOT NULL CHECK (type IN ('taxable_earning', 'non_taxable_earning', 'advance', 'loan_repayment', 'deduction')),
+  code VARCHAR(50) NOT NULL,
+  label VARCHAR(255) NOT NULL,
+  amount NUMERIC(15,2) NOT NULL CHECK (amount > 0),
+  created_by UUID NOT NULL REFERENCES users(id),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE INDEX idx_payroll_line_items_draft_id ON payroll_line_items(draft_id);
+CREATE INDEX idx_payroll_line_items_type ON payroll_line_items(type);
@@ -0,0 +1,8 @@
+DELETE FROM payroll_configurations WHERE key = 'standard_working_hours';
+
+ALTER TABLE payroll_line_items DROP COLUMN IF EXISTS source;
+
+DROP INDEX IF EXISTS idx_attendance_overtime_approved;
+ALTER TABLE attendance DROP COLUMN IF EXISTS overtime_approved_at;
+ALTER TABLE attendance DROP COLUMN IF EXISTS overtime_approved_by;
+ALTER TABLE attendance DROP COLUMN IF EXISTS overtime_approved;
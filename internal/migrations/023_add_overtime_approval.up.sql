@@ -0,0 +1,14 @@
+-- Overtime approval on attendance records; only approved overtime is paid
+ALTER TABLE attendance ADD COLUMN IF NOT EXISTS overtime_approved BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE attendance ADD COLUMN IF NOT EXISTS overtime_approved_by UUID REFERENCES users(id);
+ALTER TABLE attendance ADD COLUMN IF NOT EXISTS overtime_approved_at TIMESTAMPTZ;
+
+CREATE INDEX idx_attendance_overtime_approved ON attendance(employee_id, date) WHERE overtime_approved = true;
+
+-- Line item origin; non-manual items are regenerated when a draft is recalculated
+ALTER TABLE payroll_line_items ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'manual';
+
+-- Monthly hours used to derive the hourly rate from base salary (40 h × 52 / 12)
+INSERT INTO payroll_configurations (key, value, description, data_type, category, created_by) VALUES
+('standard_working_hours', '173.33', 'Standard working hours per month used for hourly rates', 'number', 'attendance', gen_random_uuid())
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_attendance_employee_absent;
+DROP INDEX IF EXISTS idx_leaves_employee_period;
+
+DELETE FROM payroll_line_items WHERE type = 'absence';
+ALTER TABLE payroll_line_items DROP CONSTRAINT IF EXISTS payroll_line_items_type_check;
+ALTER TABLE payroll_line_items ADD CONSTRAINT payroll_line_items_type_check
+  CHECK (type IN ('taxable_earning', 'non_taxable_earning', 'advance', 'loan_repayment', 'deduction'));
+
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS absence_deductions;
@@ -0,0 +1,10 @@
+-- Unpaid leave and unjustified absences withheld from gross salary
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS absence_deductions NUMERIC(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE payroll_line_items DROP CONSTRAINT IF EXISTS payroll_line_items_type_check;
+ALTER TABLE payroll_line_items ADD CONSTRAINT payroll_line_items_type_check
+  CHECK (type IN ('taxable_earning', 'non_taxable_earning', 'advance', 'loan_repayment', 'deduction', 'absence'));
+
+-- Indexes for looking up approved leaves and absences of a payroll period
+CREATE INDEX IF NOT EXISTS idx_leaves_employee_period ON leaves(employee_id, start_date, end_date) WHERE status = 'approved';
+CREATE INDEX IF NOT EXISTS idx_attendance_employee_absent ON attendance(employee_id, date) WHERE status = 'absent';
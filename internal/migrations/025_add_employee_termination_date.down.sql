@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_employees_termination_date;
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_termination_date_check;
+ALTER TABLE employees DROP COLUMN IF EXISTS termination_date;
@@ -0,0 +1,11 @@
+-- Exit date used to prorate the final payslip and pay untaken leave
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS termination_date DATE;
+
+-- Employees terminated before this column existed take the date of their last update
+UPDATE employees SET termination_date = GREATEST(updated_at::date, hire_date)
+WHERE status = 'terminated' AND termination_date IS NULL;
+
+ALTER TABLE employees ADD CONSTRAINT employees_termination_date_check
+  CHECK (termination_date IS NULL OR termination_date >= hire_date);
+
+CREATE INDEX IF NOT EXISTS idx_employees_termination_date ON employees(termination_date) WHERE termination_date IS NOT NULL;
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_irsa_brackets_effective_to;
+DROP INDEX IF EXISTS idx_payroll_configurations_effective;
+DROP TABLE IF EXISTS payroll_draft_rule_versions;
+
+ALTER TABLE irsa_tax_brackets DROP CONSTRAINT IF EXISTS irsa_tax_brackets_effective_range_check;
+ALTER TABLE irsa_tax_brackets DROP COLUMN IF EXISTS effective_to;
+
+-- Keep only the latest version of each key so the key can be unique again
+DELETE FROM payroll_configurations p
+USING payroll_configurations newer
+WHERE p.key = newer.key AND p.effective_from < newer.effective_from;
+
+ALTER TABLE payroll_configurations DROP CONSTRAINT IF EXISTS payroll_configurations_effective_range_check;
+ALTER TABLE payroll_configurations DROP CONSTRAINT IF EXISTS payroll_configurations_key_effective_from_key;
+ALTER TABLE payroll_configurations ADD CONSTRAINT payroll_configurations_key_key UNIQUE (key);
+ALTER TABLE payroll_configurations DROP COLUMN IF EXISTS effective_to;
+ALTER TABLE payroll_configurations DROP COLUMN IF EXISTS effective_from;
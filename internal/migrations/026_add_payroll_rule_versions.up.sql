@@ -0,0 +1,34 @@
+-- Effective dating for payroll configuration: one row per version of a key
+ALTER TABLE payroll_configurations ADD COLUMN IF NOT EXISTS effective_from DATE;
+ALTER TABLE payroll_configurations ADD COLUMN IF NOT EXISTS effective_to DATE;
+
+-- Values set before versioning existed are treated as in force for every past period
+UPDATE payroll_configurations SET effective_from = DATE '1970-01-01' WHERE effective_from IS NULL;
+ALTER TABLE payroll_configurations ALTER COLUMN effective_from SET NOT NULL;
+
+ALTER TABLE payroll_configurations DROP CONSTRAINT IF EXISTS payroll_configurations_key_key;
+ALTER TABLE payroll_configurations ADD CONSTRAINT payroll_configurations_key_effective_from_key UNIQUE (key, effective_from);
+ALTER TABLE payroll_configurations ADD CONSTRAINT payroll_configurations_effective_range_check
+  CHECK (effective_to IS NULL OR effective_to >= effective_from);
+
+-- IRSA brackets already carry effective_date; add the end of their validity range
+ALTER TABLE irsa_tax_brackets ADD COLUMN IF NOT EXISTS effective_to DATE;
+ALTER TABLE irsa_tax_brackets ADD CONSTRAINT irsa_tax_brackets_effective_range_check
+  CHECK (effective_to IS NULL OR effective_to >= effective_date);
+
+-- Rule versions used for each draft calculation
+CREATE TABLE payroll_draft_rule_versions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  draft_id UUID NOT NULL REFERENCES payroll_drafts(id) ON DELETE CASCADE,
+  rule_type VARCHAR(20) NOT NULL CHECK (rule_type IN ('configuration', 'irsa_bracket')),
+  rule_id UUID NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  value VARCHAR(500) NOT NULL,
+  effective_from DATE NOT NULL,
+  effective_to DATE,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_payroll_draft_rule_versions_draft_id ON payroll_draft_rule_versions(draft_id);
+CREATE INDEX idx_payroll_configurations_effective ON payroll_configurations(key, effective_from, effective_to);
+CREATE INDEX idx_irsa_brackets_effective_to ON irsa_tax_brackets(effective_to);
@@ -0,0 +1,3 @@
+DELETE FROM payroll_configurations WHERE key = 'irsa_dependent_reduction';
+ALTER TABLE payroll_drafts DROP COLUMN IF EXISTS irsa_breakdown;
+ALTER TABLE employees DROP COLUMN IF EXISTS dependents;
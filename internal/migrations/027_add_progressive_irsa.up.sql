@@ -0,0 +1,9 @@
+-- Dependents give a fixed IRSA reduction each
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS dependents INTEGER NOT NULL DEFAULT 0 CHECK (dependents >= 0);
+
+-- Per-bracket IRSA computation of each draft
+ALTER TABLE payroll_drafts ADD COLUMN IF NOT EXISTS irsa_breakdown JSONB;
+
+INSERT INTO payroll_configurations (key, value, description, data_type, category, effective_from, created_by) VALUES
+('irsa_dependent_reduction', '2000', 'IRSA reduction per dependent per month', 'number', 'tax', DATE '1970-01-01', gen_random_uuid())
+ON CONFLICT (key, effective_from) DO NOTHING;
@@ -0,0 +1,14 @@
+ALTER TABLE irsa_tax_brackets DROP CONSTRAINT IF EXISTS irsa_tax_brackets_income_range_check;
+ALTER TABLE irsa_tax_brackets DROP CONSTRAINT IF EXISTS irsa_tax_brackets_rate_check;
+
+-- Brackets start again one ariary above the previous one
+UPDATE irsa_tax_brackets b
+SET min_income = p.max_income + 1, updated_at = NOW()
+FROM irsa_tax_brackets p
+WHERE p.id <> b.id
+  AND p.max_income = b.min_income
+  AND p.effective_date <= COALESCE(b.effective_to, DATE 'infinity')
+  AND b.effective_date <= COALESCE(p.effective_to, DATE 'infinity');
+
+-- Rates stay fractions; those converted from percentages are not restored
+ALTER TABLE irsa_tax_brackets ALTER COLUMN tax_rate TYPE NUMERIC(5,2);
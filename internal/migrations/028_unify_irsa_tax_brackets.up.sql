@@ -0,0 +1,22 @@
+-- Rates up to 4 decimals, always a fraction: the former declarations endpoints stored percentages
+ALTER TABLE irsa_tax_brackets ALTER COLUMN tax_rate TYPE NUMERIC(7,4);
+UPDATE irsa_tax_brackets SET tax_rate = tax_rate / 100 WHERE tax_rate > 1;
+
+-- Brackets start where the previous one ends instead of one ariary above it
+UPDATE irsa_tax_brackets b
+SET min_income = p.max_income, updated_at = NOW()
+FROM irsa_tax_brackets p
+WHERE p.id <> b.id
+  AND p.max_income = b.min_income - 1
+  AND p.effective_date <= COALESCE(b.effective_to, DATE 'infinity')
+  AND b.effective_date <= COALESCE(p.effective_to, DATE 'infinity');
+
+-- Brackets created through the declarations endpoints had no name
+UPDATE irsa_tax_brackets
+SET bracket_name = 'Tranche ' || rtrim(rtrim(to_char(tax_rate * 100, 'FM990.99'), '0'), '.') || '%'
+WHERE bracket_name = '';
+
+ALTER TABLE irsa_tax_brackets ADD CONSTRAINT irsa_tax_brackets_rate_check
+  CHECK (tax_rate >= 0 AND tax_rate <= 1);
+ALTER TABLE irsa_tax_brackets ADD CONSTRAINT irsa_tax_brackets_income_range_check
+  CHECK (min_income >= 0 AND (max_income IS NULL OR max_income > min_income));
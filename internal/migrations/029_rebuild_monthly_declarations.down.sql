@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS monthly_declarations;
+
+ALTER TABLE monthly_declarations_legacy RENAME TO monthly_declarations;
+ALTER INDEX IF EXISTS idx_monthly_declarations_legacy_employee_id RENAME TO idx_monthly_declarations_employee_id;
+ALTER INDEX IF EXISTS idx_monthly_declarations_legacy_period RENAME TO idx_monthly_declarations_period;
+ALTER INDEX IF EXISTS idx_monthly_declarations_legacy_status RENAME TO idx_monthly_declarations_status;
+ALTER INDEX IF EXISTS idx_monthly_declarations_legacy_accountant_id RENAME TO idx_monthly_declarations_accountant_id;
@@ -0,0 +1,56 @@
+-- The original table held one row per employee; declarations are now one row per type and month
+-- with the employee breakdown in declaration_data. Keep any old rows aside.
+--
+-- DATA LOSS: old rows are NOT converted. They combine CNAPS and OSTIE in one amount, carry no employer
+-- contributions and have 'monthly'/'annual' types, so no CNAPS, OSTIE or IRSA declaration can be rebuilt
+-- from them faithfully. After this migration they stay in monthly_declarations_legacy, which no endpoint
+-- reads; regenerate the months still needed from approved payroll (GET /declarations/{type}/generate).
+DO $$
+DECLARE
+  legacy_rows BIGINT;
+BEGIN
+  SELECT COUNT(*) INTO legacy_rows FROM monthly_declarations;
+  IF legacy_rows > 0 THEN
+    RAISE NOTICE '% declaration row(s) moved to monthly_declarations_legacy and not converted; regenerate the months still needed', legacy_rows;
+  END IF;
+END $$;
+
+ALTER TABLE monthly_declarations RENAME TO monthly_declarations_legacy;
+ALTER INDEX IF EXISTS idx_monthly_declarations_employee_id RENAME TO idx_monthly_declarations_legacy_employee_id;
+ALTER INDEX IF EXISTS idx_monthly_declarations_period RENAME TO idx_monthly_declarations_legacy_period;
+ALTER INDEX IF EXISTS idx_monthly_declarations_status RENAME TO idx_monthly_declarations_legacy_status;
+ALTER INDEX IF EXISTS idx_monthly_declarations_accountant_id RENAME TO idx_monthly_declarations_legacy_accountant_id;
+
+CREATE TABLE monthly_declarations (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  declaration_type VARCHAR(20) NOT NULL CHECK (declaration_type IN ('cnaps', 'ostie', 'irsa')),
+  declaration_period_start DATE NOT NULL,
+  declaration_period_end DATE NOT NULL,
+  declaration_number VARCHAR(50) NOT NULL UNIQUE,
+  company_name VARCHAR(255) NOT NULL,
+  company_address TEXT,
+  company_nif VARCHAR(50),
+  company_stat VARCHAR(50),
+  cnaps_number VARCHAR(50),
+  ostie_number VARCHAR(50),
+  total_employees INTEGER NOT NULL DEFAULT 0,
+  total_gross_salary NUMERIC(15,2) NOT NULL DEFAULT 0,
+  total_employee_contributions NUMERIC(15,2) NOT NULL DEFAULT 0,
+  total_employer_contributions NUMERIC(15,2) NOT NULL DEFAULT 0,
+  total_amount_due NUMERIC(15,2) NOT NULL DEFAULT 0,
+  declaration_data JSONB NOT NULL DEFAULT '[]',
+  status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'submitted', 'paid', 'cancelled')),
+  accountant_id UUID NOT NULL REFERENCES users(id),
+  submitted_at TIMESTAMPTZ,
+  paid_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  CONSTRAINT monthly_declarations_period_check CHECK (declaration_period_end >= declaration_period_start)
+);
+
+-- FR-DECL-004: one live declaration of each type per month
+CREATE UNIQUE INDEX idx_monthly_declarations_type_month ON monthly_declarations(declaration_type, declaration_period_start)
+  WHERE status <> 'cancelled';
+CREATE INDEX idx_monthly_declarations_period ON monthly_declarations(declaration_period_start, declaration_period_end);
+CREATE INDEX idx_monthly_declarations_status ON monthly_declarations(status);
+CREATE INDEX idx_monthly_declarations_accountant_id ON monthly_declarations(accountant_id);
@@ -0,0 +1,17 @@
+DELETE FROM payroll_configurations WHERE key IN (
+  'cnaps_declaration_due_day', 'ostie_declaration_due_day', 'irsa_declaration_due_day',
+  'cnaps_late_penalty_rate', 'ostie_late_penalty_rate', 'irsa_late_penalty_rate',
+  'cnaps_late_interest_rate', 'ostie_late_interest_rate', 'irsa_late_interest_rate'
+);
+
+DROP TABLE IF EXISTS declaration_payments;
+DROP INDEX IF EXISTS idx_monthly_declarations_open_due_date;
+
+ALTER TABLE monthly_declarations
+  DROP COLUMN IF EXISTS cancellation_reason,
+  DROP COLUMN IF EXISTS cancelled_by,
+  DROP COLUMN IF EXISTS cancelled_at,
+  DROP COLUMN IF EXISTS submission_reference,
+  DROP COLUMN IF EXISTS submitted_by,
+  DROP COLUMN IF EXISTS amount_paid,
+  DROP COLUMN IF EXISTS due_date;
@@ -0,0 +1,46 @@
+-- Declarations are due on a configured day of the month following the declared month
+ALTER TABLE monthly_declarations ADD COLUMN IF NOT EXISTS due_date DATE;
+UPDATE monthly_declarations SET due_date = (date_trunc('month', declaration_period_start) + INTERVAL '1 month 14 days')::date
+  WHERE due_date IS NULL;
+ALTER TABLE monthly_declarations ALTER COLUMN due_date SET NOT NULL;
+
+-- Lifecycle: draft -> submitted -> paid, or cancelled
+ALTER TABLE monthly_declarations
+  ADD COLUMN IF NOT EXISTS amount_paid NUMERIC(15,2) NOT NULL DEFAULT 0 CHECK (amount_paid >= 0),
+  ADD COLUMN IF NOT EXISTS submitted_by UUID REFERENCES users(id),
+  ADD COLUMN IF NOT EXISTS submission_reference VARCHAR(100),
+  ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS cancelled_by UUID REFERENCES users(id),
+  ADD COLUMN IF NOT EXISTS cancellation_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_monthly_declarations_open_due_date ON monthly_declarations(due_date)
+  WHERE status IN ('draft', 'submitted');
+
+CREATE TABLE IF NOT EXISTS declaration_payments (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  declaration_id UUID NOT NULL REFERENCES monthly_declarations(id) ON DELETE RESTRICT,
+  amount NUMERIC(15,2) NOT NULL CHECK (amount > 0),
+  paid_on DATE NOT NULL,
+  reference VARCHAR(100) NOT NULL,
+  payment_method VARCHAR(30) CHECK (payment_method IN ('bank_transfer', 'cheque', 'cash', 'mobile_money')),
+  notes TEXT,
+  recorded_by UUID NOT NULL REFERENCES users(id),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  CONSTRAINT declaration_payments_reference_unique UNIQUE (declaration_id, reference)
+);
+
+CREATE INDEX IF NOT EXISTS idx_declaration_payments_declaration_id ON declaration_payments(declaration_id);
+
+-- Due day and late-payment penalties per declaration type: a one-off surcharge on the amount unpaid
+-- at the due date plus interest for every started month of delay
+INSERT INTO payroll_configurations (key, value, description, data_type, category, effective_from, created_by) VALUES
+('cnaps_declaration_due_day', '15', 'Day of the following month CNAPS contributions are due', 'number', 'declarations', DATE '1970-01-01', gen_random_uuid()),
+('ostie_declaration_due_day', '15', 'Day of the following month OSTIE contributions are due', 'number', 'declarations', DATE '1970-01-01', gen_random_uuid()),
+('irsa_declaration_due_day', '15', 'Day of the following month withheld IRSA is due', 'number', 'declarations', DATE '1970-01-01', gen_random_uuid()),
+('cnaps_late_penalty_rate', '0.10', 'CNAPS surcharge on contributions unpaid at the due date', 'number', 'declarations', DATE '1970-01-01', gen_random_uuid()),
+('ostie_late_penalty_rate', '0.10', 'OSTIE surcharge on contributions unpaid at the due date', 'number', 'declarations', DATE '1970-01-01', gen_random_uuid()),
+('irsa_late_penalty_rate', '0.10', 'IRSA surcharge on tax unpaid at the due date', 'number', 'declarations', DATE '1970-01-01', gen_random_uuid()),
+('cnaps_late_interest_rate', '0.01', 'CNAPS late interest per started month on contributions unpaid at the due date', 'number', 'declarations', DATE '1970-01-01', gen_random_uuid()),
+('ostie_late_interest_rate', '0.01', 'OSTIE late interest per started month on contributions unpaid at the due date', 'number', 'declarations', DATE '1970-01-01', gen_random_uuid()),
+('irsa_late_interest_rate', '0.01', 'IRSA late interest per started month on tax unpaid at the due date', 'number', 'declarations', DATE '1970-01-01', gen_random_uuid())
+ON CONFLICT (key, effective_from) DO NOTHING;
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS journal_lines_balanced ON journal_lines;
+DROP FUNCTION IF EXISTS check_journal_entry_balance();
+
+DELETE FROM document_sequences WHERE name LIKE 'journal\_%';
+
+DROP TABLE IF EXISTS journal_lines;
+DROP TABLE IF EXISTS journal_entries;
@@ -0,0 +1,123 @@
+-- General ledger: balanced journal entries posted by payroll approval and by Accountants
+CREATE TABLE IF NOT EXISTS journal_entries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  entry_number VARCHAR(50) UNIQUE NOT NULL,
+  journal VARCHAR(10) NOT NULL,
+  entry_date DATE NOT NULL,
+  description TEXT NOT NULL,
+  source_type VARCHAR(50) NOT NULL DEFAULT 'manual',
+  source_id UUID,
+  reversal_of UUID REFERENCES journal_entries(id),
+  reversed_by UUID REFERENCES journal_entries(id),
+  total_debit NUMERIC(15,2) NOT NULL CHECK (total_debit > 0),
+  total_credit NUMERIC(15,2) NOT NULL,
+  posted_by UUID NOT NULL REFERENCES users(id),
+  posted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT journal_entries_balanced CHECK (total_debit = total_credit)
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_journal_date ON journal_entries(journal, entry_date);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_entry_date ON journal_entries(entry_date);
+
+-- A document is posted once; its reversal carries the same source
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_entries_source ON journal_entries(source_type, source_id)
+  WHERE source_id IS NOT NULL AND reversal_of IS NULL;
+
+CREATE TABLE IF NOT EXISTS journal_lines (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  entry_id UUID NOT NULL REFERENCES journal_entries(id) ON DELETE RESTRICT,
+  line_number INTEGER NOT NULL CHECK (line_number > 0),
+  account_code VARCHAR(20) NOT NULL CHECK (account_code ~ '^[0-9]+$'),
+  account_name VARCHAR(255) NOT NULL,
+  debit NUMERIC(15,2) NOT NULL DEFAULT 0 CHECK (debit >= 0),
+  credit NUMERIC(15,2) NOT NULL DEFAULT 0 CHECK (credit >= 0),
+  description TEXT,
+  CONSTRAINT journal_lines_one_side CHECK ((debit = 0) <> (credit = 0)),
+  CONSTRAINT journal_lines_entry_line_unique UNIQUE (entry_id, line_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_lines_entry_id ON journal_lines(entry_id);
+CREATE INDEX IF NOT EXISTS idx_journal_lines_account_code ON journal_lines(account_code);
+
+-- Post the GL entries of payroll approved so far to the payroll journal, numbered per fiscal year
+WITH settings AS (
+  SELECT COALESCE((SELECT NULLIF(fiscal_year_start, '') FROM company_settings LIMIT 1), '01-01') AS fiscal_year_start
+),
+approved AS (
+  SELECT pa.id, pa.fiche_paie_number, pa.accountant_id, pa.approved_at, pd.period_start, pd.period_end,
+    EXTRACT(YEAR FROM pd.period_end)::INTEGER
+      - CASE WHEN to_char(pd.period_end, 'MM-DD') < s.fiscal_year_start THEN 1 ELSE 0 END AS fiscal_year
+  FROM payroll_approved pa
+  JOIN payroll_drafts pd ON pd.id = pa.draft_id
+  CROSS JOIN settings s
+  WHERE EXISTS (
+    SELECT 1 FROM jsonb_array_elements(pa.gl_entries) gl
+    WHERE ROUND((gl->>'debit')::NUMERIC - (gl->>'credit')::NUMERIC, 2) <> 0
+  )
+),
+numbered AS (
+  SELECT a.*, ROW_NUMBER() OVER (PARTITION BY a.fiscal_year ORDER BY a.period_end, a.approved_at, a.fiche_paie_number) AS seq
+  FROM approved a
+)
+INSERT INTO journal_entries (entry_number, journal, entry_date, description, source_type, source_id,
+  total_debit, total_credit, posted_by, posted_at)
+SELECT 'PAIE-' || LPAD(fiscal_year::TEXT, 4, '0') || '-' || LPAD(seq::TEXT, 5, '0'), 'PAIE', period_end,
+  'Paie ' || to_char(period_start, 'MM/YYYY') || ' - ' || fiche_paie_number, 'payroll_approved', id,
+  1, 1, accountant_id, COALESCE(approved_at, NOW())
+FROM numbered;
+
+INSERT INTO journal_lines (entry_id, line_number, account_code, account_name, debit, credit, description)
+SELECT je.id,
+  ROW_NUMBER() OVER (PARTITION BY je.id ORDER BY gl.ordinality),
+  gl.value->>'account_code',
+  gl.value->>'account_name',
+  GREATEST(ROUND((gl.value->>'debit')::NUMERIC - (gl.value->>'credit')::NUMERIC, 2), 0),
+  GREATEST(ROUND((gl.value->>'credit')::NUMERIC - (gl.value->>'debit')::NUMERIC, 2), 0),
+  gl.value->>'description'
+FROM journal_entries je
+JOIN payroll_approved pa ON pa.id = je.source_id AND je.source_type = 'payroll_approved'
+CROSS JOIN LATERAL jsonb_array_elements(pa.gl_entries) WITH ORDINALITY AS gl(value, ordinality)
+WHERE ROUND((gl.value->>'debit')::NUMERIC - (gl.value->>'credit')::NUMERIC, 2) <> 0;
+
+UPDATE journal_entries je SET total_debit = t.debit, total_credit = t.credit
+FROM (SELECT entry_id, SUM(debit) AS debit, SUM(credit) AS credit FROM journal_lines GROUP BY entry_id) t
+WHERE je.id = t.entry_id;
+
+INSERT INTO document_sequences (name, fiscal_year, last_value)
+SELECT 'journal_paie', split_part(entry_number, '-', 2)::INTEGER, COUNT(*)
+FROM journal_entries
+WHERE journal = 'PAIE'
+GROUP BY split_part(entry_number, '-', 2)
+ON CONFLICT (name, fiscal_year) DO UPDATE SET last_value = GREATEST(document_sequences.last_value, EXCLUDED.last_value);
+
+-- Every entry must balance, checked when the transaction that posts it commits
+CREATE OR REPLACE FUNCTION check_journal_entry_balance() RETURNS TRIGGER AS $$
+DECLARE
+  checked_entry UUID;
+  line_debit NUMERIC(15,2);
+  line_credit NUMERIC(15,2);
+  entry_total NUMERIC(15,2);
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    checked_entry := OLD.entry_id;
+  ELSE
+    checked_entry := NEW.entry_id;
+  END IF;
+
+  SELECT COALESCE(SUM(debit), 0), COALESCE(SUM(credit), 0) INTO line_debit, line_credit
+  FROM journal_lines WHERE entry_id = checked_entry;
+  SELECT total_debit INTO entry_total FROM journal_entries WHERE id = checked_entry;
+
+  IF line_debit <> line_credit OR line_debit <> entry_total THEN
+    RAISE EXCEPTION 'journal entry % is unbalanced: debits %, credits %, entry total %',
+      checked_entry, line_debit, line_credit, entry_total;
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS journal_lines_balanced ON journal_lines;
+CREATE CONSTRAINT TRIGGER journal_lines_balanced
+  AFTER INSERT OR UPDATE OR DELETE ON journal_lines
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION check_journal_entry_balance();
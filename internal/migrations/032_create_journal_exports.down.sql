@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS journal_export_entries;
+DROP TABLE IF EXISTS journal_exports;
+DROP TABLE IF EXISTS ledger_account_mappings;
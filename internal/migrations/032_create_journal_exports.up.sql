@@ -0,0 +1,45 @@
+-- Ledger accounts mapped to the codes of the company's external accounting software
+CREATE TABLE IF NOT EXISTS ledger_account_mappings (
+  account_code VARCHAR(20) PRIMARY KEY CHECK (account_code ~ '^[0-9]+$'),
+  external_code VARCHAR(50) NOT NULL,
+  external_name VARCHAR(255),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_by UUID REFERENCES users(id)
+);
+
+-- Export log: CSV and FEC files with their content, and JSON feed pages with their cursors
+CREATE TABLE IF NOT EXISTS journal_exports (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'fec', 'json')),
+  journal VARCHAR(10) NOT NULL,
+  period_start DATE,
+  period_end DATE,
+  include_exported BOOLEAN NOT NULL DEFAULT FALSE,
+  cursor VARCHAR(100),
+  next_cursor VARCHAR(100),
+  entry_count INTEGER NOT NULL CHECK (entry_count > 0),
+  first_entry VARCHAR(50),
+  last_entry VARCHAR(50),
+  total_debit NUMERIC(15,2) NOT NULL,
+  total_credit NUMERIC(15,2) NOT NULL,
+  filename VARCHAR(255),
+  content_type VARCHAR(100),
+  content BYTEA,
+  checksum VARCHAR(64),
+  exported_by UUID NOT NULL REFERENCES users(id),
+  exported_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_exports_exported_at ON journal_exports(exported_at);
+
+-- Entries included in each file export; outside re-exports an entry is exported once per format
+CREATE TABLE IF NOT EXISTS journal_export_entries (
+  export_id UUID NOT NULL REFERENCES journal_exports(id) ON DELETE RESTRICT,
+  entry_id UUID NOT NULL REFERENCES journal_entries(id) ON DELETE RESTRICT,
+  format VARCHAR(10) NOT NULL,
+  is_reexport BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (export_id, entry_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_export_entries_first_export ON journal_export_entries(entry_id, format)
+  WHERE NOT is_reexport;
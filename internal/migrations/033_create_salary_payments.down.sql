@@ -0,0 +1,13 @@
+ALTER TABLE payroll_approved
+  DROP COLUMN IF EXISTS payment_batch_id,
+  DROP COLUMN IF EXISTS paid_at;
+
+DROP TABLE IF EXISTS payroll_payment_items;
+DROP TABLE IF EXISTS payroll_payment_batches;
+
+ALTER TABLE company_settings
+  DROP COLUMN IF EXISTS payroll_bank_bic,
+  DROP COLUMN IF EXISTS payroll_bank_iban,
+  DROP COLUMN IF EXISTS payroll_bank_name;
+
+DROP TABLE IF EXISTS employee_payment_accounts;
@@ -0,0 +1,84 @@
+-- Bank account or mobile money number each employee's net salary is paid to
+CREATE TABLE IF NOT EXISTS employee_payment_accounts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  employee_id UUID NOT NULL UNIQUE REFERENCES employees(id) ON DELETE CASCADE,
+  method VARCHAR(20) NOT NULL CHECK (method IN ('bank_transfer', 'mobile_money')),
+  account_holder VARCHAR(140) NOT NULL,
+  bank_name VARCHAR(100),
+  iban VARCHAR(34),
+  bic VARCHAR(11),
+  mobile_operator VARCHAR(20),
+  mobile_number VARCHAR(15),
+  updated_by UUID REFERENCES users(id),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  CONSTRAINT employee_payment_accounts_details CHECK (
+    (method = 'bank_transfer' AND COALESCE(iban, '') <> '' AND COALESCE(mobile_number, '') = '') OR
+    (method = 'mobile_money' AND mobile_operator IN ('mvola', 'orange_money', 'airtel_money')
+      AND COALESCE(mobile_number, '') <> '' AND COALESCE(iban, '') = '')
+  )
+);
+
+-- Company account salaries are paid from, the debtor of pain.001 transfer files
+ALTER TABLE company_settings
+  ADD COLUMN IF NOT EXISTS payroll_bank_name VARCHAR(100),
+  ADD COLUMN IF NOT EXISTS payroll_bank_iban VARCHAR(34),
+  ADD COLUMN IF NOT EXISTS payroll_bank_bic VARCHAR(11);
+
+-- Payment batches: generated -> confirmed, or cancelled
+CREATE TABLE IF NOT EXISTS payroll_payment_batches (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  batch_number VARCHAR(50) NOT NULL UNIQUE,
+  period_start DATE NOT NULL,
+  period_end DATE NOT NULL,
+  execution_date DATE NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'generated' CHECK (status IN ('generated', 'confirmed', 'cancelled')),
+  payment_count INTEGER NOT NULL CHECK (payment_count > 0),
+  total_amount NUMERIC(15,2) NOT NULL CHECK (total_amount > 0),
+  paid_amount NUMERIC(15,2) NOT NULL DEFAULT 0 CHECK (paid_amount >= 0),
+  currency VARCHAR(10) NOT NULL,
+  created_by UUID NOT NULL REFERENCES users(id),
+  confirmed_by UUID REFERENCES users(id),
+  confirmed_at TIMESTAMPTZ,
+  paid_on DATE,
+  cancelled_by UUID REFERENCES users(id),
+  cancelled_at TIMESTAMPTZ,
+  reason TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  CHECK (period_end > period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payroll_payment_batches_status ON payroll_payment_batches(status);
+
+-- Net salaries in each batch, with the payment account as it was when the batch was generated
+CREATE TABLE IF NOT EXISTS payroll_payment_items (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  batch_id UUID NOT NULL REFERENCES payroll_payment_batches(id) ON DELETE CASCADE,
+  approved_id UUID NOT NULL REFERENCES payroll_approved(id) ON DELETE RESTRICT,
+  employee_id UUID NOT NULL REFERENCES employees(id),
+  employee_name VARCHAR(200) NOT NULL,
+  fiche_paie_number VARCHAR(50) NOT NULL,
+  amount NUMERIC(15,2) NOT NULL CHECK (amount > 0),
+  method VARCHAR(20) NOT NULL CHECK (method IN ('bank_transfer', 'mobile_money')),
+  account_holder VARCHAR(140) NOT NULL,
+  bank_name VARCHAR(100),
+  iban VARCHAR(34),
+  bic VARCHAR(11),
+  mobile_operator VARCHAR(20),
+  mobile_number VARCHAR(15),
+  status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid', 'failed', 'cancelled')),
+  failure_reason TEXT,
+  paid_at DATE
+);
+
+CREATE INDEX IF NOT EXISTS idx_payroll_payment_items_batch_id ON payroll_payment_items(batch_id);
+
+-- A net salary is in at most one batch awaiting payment or paid; failed and cancelled items can be paid again
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payroll_payment_items_open_approved_id ON payroll_payment_items(approved_id)
+  WHERE status IN ('pending', 'paid');
+
+-- Approved payroll is marked paid once its batch is confirmed
+ALTER TABLE payroll_approved
+  ADD COLUMN IF NOT EXISTS paid_at DATE,
+  ADD COLUMN IF NOT EXISTS payment_batch_id UUID REFERENCES payroll_payment_batches(id);
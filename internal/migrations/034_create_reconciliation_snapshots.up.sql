@@ -0,0 +1,16 @@
+-- Reconciliation reports saved by the daily job, one per pay period and day
+CREATE TABLE IF NOT EXISTS payroll_reconciliation_snapshots (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  period_start DATE NOT NULL,
+  period_end DATE NOT NULL,
+  snapshot_date DATE NOT NULL,
+  status VARCHAR(20) NOT NULL CHECK (status IN ('RECONCILED', 'VARIANCE DETECTED')),
+  variance_percentage NUMERIC(15,4) NOT NULL,
+  report JSONB NOT NULL,
+  alerted BOOLEAN NOT NULL DEFAULT FALSE,
+  alerted_users INTEGER NOT NULL DEFAULT 0,
+  generated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT payroll_reconciliation_snapshots_period_day UNIQUE (period_start, period_end, snapshot_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payroll_reconciliation_snapshots_snapshot_date ON payroll_reconciliation_snapshots(snapshot_date);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_employees_ostie_number;
+DROP INDEX IF EXISTS idx_employees_cnaps_number;
+DROP INDEX IF EXISTS idx_employees_nif;
+
+ALTER TABLE employees
+  DROP COLUMN IF EXISTS ostie_number,
+  DROP COLUMN IF EXISTS cnaps_number,
+  DROP COLUMN IF EXISTS nif,
+  DROP COLUMN IF EXISTS cin_issued_at,
+  DROP COLUMN IF EXISTS cin_issued_on;
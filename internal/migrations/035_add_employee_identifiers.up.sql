@@ -0,0 +1,15 @@
+-- Social security and tax identifiers of each employee, and where and when their CIN was issued
+ALTER TABLE employees
+  ADD COLUMN IF NOT EXISTS cin_issued_on DATE,
+  ADD COLUMN IF NOT EXISTS cin_issued_at VARCHAR(100),
+  ADD COLUMN IF NOT EXISTS nif VARCHAR(10),
+  ADD COLUMN IF NOT EXISTS cnaps_number VARCHAR(15),
+  ADD COLUMN IF NOT EXISTS ostie_number VARCHAR(30);
+
+-- An identifier belongs to a single current employee
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_nif ON employees(nif)
+  WHERE deleted_at IS NULL AND nif <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_cnaps_number ON employees(cnaps_number)
+  WHERE deleted_at IS NULL AND cnaps_number <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_ostie_number ON employees(ostie_number)
+  WHERE deleted_at IS NULL AND ostie_number <> '';
@@ -0,0 +1,38 @@
+-- Categorized employee documents; each uploaded file is a version and the document carries its current dates
+CREATE TABLE IF NOT EXISTS employee_documents (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  employee_id UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+  category VARCHAR(30) NOT NULL CHECK (category IN ('contract', 'diploma', 'id_document', 'medical_certificate', 'other')),
+  title VARCHAR(200) NOT NULL,
+  current_version INTEGER NOT NULL DEFAULT 1 CHECK (current_version >= 1),
+  issued_on DATE,
+  expires_on DATE,
+  expiry_notice VARCHAR(20) CHECK (COALESCE(expiry_notice, '') IN ('', 'expiring', 'expired')),
+  created_by UUID NOT NULL REFERENCES users(id),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ,
+  CONSTRAINT employee_documents_dates CHECK (expires_on IS NULL OR issued_on IS NULL OR expires_on >= issued_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_documents_employee_id ON employee_documents(employee_id);
+CREATE INDEX IF NOT EXISTS idx_employee_documents_deleted_at ON employee_documents(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_employee_documents_expires_on ON employee_documents(expires_on)
+  WHERE expires_on IS NOT NULL AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS employee_document_versions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  document_id UUID NOT NULL REFERENCES employee_documents(id) ON DELETE CASCADE,
+  version INTEGER NOT NULL CHECK (version >= 1),
+  file_name VARCHAR(255) NOT NULL,
+  content_type VARCHAR(100) NOT NULL,
+  size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+  checksum CHAR(64) NOT NULL,
+  storage_key VARCHAR(255) NOT NULL,
+  issued_on DATE,
+  expires_on DATE,
+  notes TEXT,
+  uploaded_by UUID NOT NULL REFERENCES users(id),
+  uploaded_at TIMESTAMPTZ DEFAULT NOW(),
+  CONSTRAINT employee_document_versions_number UNIQUE (document_id, version)
+);
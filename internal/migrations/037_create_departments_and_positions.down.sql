@@ -0,0 +1,10 @@
+ALTER TABLE kpis
+  DROP COLUMN IF EXISTS position_id,
+  DROP COLUMN IF EXISTS department_id;
+
+ALTER TABLE employees
+  DROP COLUMN IF EXISTS position_id,
+  DROP COLUMN IF EXISTS department_id;
+
+DROP TABLE IF EXISTS positions;
+DROP TABLE IF EXISTS departments;
@@ -0,0 +1,84 @@
+-- Departments managed by HR, which employees and KPIs reference instead of typing a name
+CREATE TABLE IF NOT EXISTS departments (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name VARCHAR(100) NOT NULL,
+  code VARCHAR(20),
+  cost_center VARCHAR(50),
+  parent_id UUID REFERENCES departments(id),
+  head_id UUID REFERENCES employees(id) ON DELETE SET NULL,
+  is_active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ,
+  CHECK (parent_id <> id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_departments_name ON departments(LOWER(name)) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_departments_code ON departments(code) WHERE deleted_at IS NULL AND code <> '';
+CREATE INDEX IF NOT EXISTS idx_departments_parent_id ON departments(parent_id);
+CREATE INDEX IF NOT EXISTS idx_departments_deleted_at ON departments(deleted_at);
+
+-- Positions, with the grade and gross salary band employees holding them are paid within
+CREATE TABLE IF NOT EXISTS positions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  title VARCHAR(100) NOT NULL,
+  department_id UUID REFERENCES departments(id),
+  grade VARCHAR(20),
+  min_salary NUMERIC(15,2) CHECK (min_salary >= 0),
+  max_salary NUMERIC(15,2) CHECK (max_salary >= 0),
+  is_active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ,
+  CHECK (min_salary IS NULL OR max_salary IS NULL OR min_salary <= max_salary)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_positions_title ON positions(LOWER(title)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_positions_department_id ON positions(department_id);
+CREATE INDEX IF NOT EXISTS idx_positions_deleted_at ON positions(deleted_at);
+
+-- The names already typed on employees and KPIs become departments and positions. Spellings that differ only
+-- by case or surrounding spaces are merged under the most used one.
+INSERT INTO departments (name)
+SELECT DISTINCT ON (LOWER(name)) name
+FROM (
+  SELECT BTRIM(department) AS name, COUNT(*) AS uses FROM employees WHERE BTRIM(COALESCE(department, '')) <> '' GROUP BY 1
+  UNION ALL
+  SELECT BTRIM(department), COUNT(*) FROM kpis WHERE BTRIM(COALESCE(department, '')) <> '' GROUP BY 1
+) names
+ORDER BY LOWER(name), uses DESC, name;
+
+INSERT INTO positions (title)
+SELECT DISTINCT ON (LOWER(title)) title
+FROM (
+  SELECT BTRIM(position) AS title, COUNT(*) AS uses FROM employees WHERE BTRIM(COALESCE(position, '')) <> '' GROUP BY 1
+  UNION ALL
+  SELECT BTRIM(position), COUNT(*) FROM kpis WHERE BTRIM(COALESCE(position, '')) <> '' GROUP BY 1
+) titles
+ORDER BY LOWER(title), uses DESC, title;
+
+-- Employees and KPIs reference them; the name columns are kept as the referenced name
+ALTER TABLE employees
+  ADD COLUMN IF NOT EXISTS department_id UUID REFERENCES departments(id),
+  ADD COLUMN IF NOT EXISTS position_id UUID REFERENCES positions(id);
+
+ALTER TABLE kpis
+  ADD COLUMN IF NOT EXISTS department_id UUID REFERENCES departments(id),
+  ADD COLUMN IF NOT EXISTS position_id UUID REFERENCES positions(id);
+
+UPDATE employees e SET department_id = d.id, department = d.name
+FROM departments d WHERE LOWER(BTRIM(e.department)) = LOWER(d.name);
+
+UPDATE employees e SET position_id = p.id, position = p.title
+FROM positions p WHERE LOWER(BTRIM(e.position)) = LOWER(p.title);
+
+UPDATE kpis k SET department_id = d.id, department = d.name
+FROM departments d WHERE LOWER(BTRIM(k.department)) = LOWER(d.name);
+
+UPDATE kpis k SET position_id = p.id, position = p.title
+FROM positions p WHERE LOWER(BTRIM(k.position)) = LOWER(p.title);
+
+CREATE INDEX IF NOT EXISTS idx_employees_department_id ON employees(department_id);
+CREATE INDEX IF NOT EXISTS idx_employees_position_id ON employees(position_id);
+CREATE INDEX IF NOT EXISTS idx_kpis_department_id ON kpis(department_id);
+CREATE INDEX IF NOT EXISTS idx_kpis_position_id ON kpis(position_id);
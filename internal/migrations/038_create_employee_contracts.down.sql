@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS employee_contract_amendments;
+DROP TABLE IF EXISTS employee_contracts;
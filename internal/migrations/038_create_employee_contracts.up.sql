@@ -0,0 +1,62 @@
+-- Employment contracts of each employee. One contract is active at a time, and one may be pending until its
+-- start date; a renewal links to the contract it renews. The terms are those of the contract as amended so far.
+CREATE TABLE IF NOT EXISTS employee_contracts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  employee_id UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+  contract_type VARCHAR(50) NOT NULL CHECK (contract_type IN ('permanent', 'fixed_term', 'intern', 'contractor')),
+  start_date DATE NOT NULL,
+  end_date DATE,
+  probation_end_date DATE,
+  gross_salary NUMERIC(15,2) NOT NULL CHECK (gross_salary >= 200000),
+  position_id UUID REFERENCES positions(id),
+  position VARCHAR(100),
+  document_id UUID REFERENCES employee_documents(id),
+  signed_on DATE,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'renewed', 'ended')),
+  ended_on DATE,
+  renewal_of_id UUID REFERENCES employee_contracts(id),
+  notes TEXT,
+  end_notice VARCHAR(20) CHECK (COALESCE(end_notice, '') IN ('', 'expiring', 'ended')),
+  probation_notice VARCHAR(20) CHECK (COALESCE(probation_notice, '') IN ('', 'ending')),
+  created_by UUID NOT NULL REFERENCES users(id),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  CONSTRAINT employee_contracts_dates CHECK (end_date IS NULL OR end_date >= start_date),
+  CONSTRAINT employee_contracts_probation CHECK (probation_end_date IS NULL OR probation_end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_contracts_employee_id ON employee_contracts(employee_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employee_contracts_active ON employee_contracts(employee_id)
+  WHERE status = 'active';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employee_contracts_pending ON employee_contracts(employee_id)
+  WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_employee_contracts_start_date ON employee_contracts(start_date)
+  WHERE status = 'pending';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employee_contracts_renewal_of_id ON employee_contracts(renewal_of_id)
+  WHERE renewal_of_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_employee_contracts_end_date ON employee_contracts(end_date)
+  WHERE status = 'active' AND end_date IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_employee_contracts_probation_end_date ON employee_contracts(probation_end_date)
+  WHERE status = 'active' AND probation_end_date IS NOT NULL;
+
+-- Amendments change a contract's terms from their effective date; applied_at is set once they took effect
+CREATE TABLE IF NOT EXISTS employee_contract_amendments (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  contract_id UUID NOT NULL REFERENCES employee_contracts(id) ON DELETE CASCADE,
+  number INTEGER NOT NULL CHECK (number >= 1),
+  effective_date DATE NOT NULL,
+  reason TEXT NOT NULL,
+  gross_salary NUMERIC(15,2) CHECK (gross_salary >= 200000),
+  position_id UUID REFERENCES positions(id),
+  position VARCHAR(100),
+  end_date DATE,
+  probation_end_date DATE,
+  document_id UUID REFERENCES employee_documents(id),
+  applied_at TIMESTAMPTZ,
+  created_by UUID NOT NULL REFERENCES users(id),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  CONSTRAINT employee_contract_amendments_number UNIQUE (contract_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_contract_amendments_pending ON employee_contract_amendments(effective_date)
+  WHERE applied_at IS NULL;
@@ -0,0 +1,12 @@
+-- Signed PDF summaries of declarations, kept so a summary can be verified against its declaration
+CREATE TABLE IF NOT EXISTS declaration_exports (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  declaration_id UUID NOT NULL REFERENCES monthly_declarations(id) ON DELETE CASCADE,
+  payload_hash VARCHAR(64) NOT NULL,
+  signature TEXT NOT NULL,
+  signature_key_id VARCHAR(32) NOT NULL,
+  exported_by UUID NOT NULL REFERENCES users(id),
+  exported_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_declaration_exports_declaration_id ON declaration_exports(declaration_id);
@@ -0,0 +1,348 @@
+package organization
+
+import (
+	"errors"
+	"net/http"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler handles department and position requests
+type Handler struct {
+	repo *Repo
+}
+
+// NewHandler creates a new organization handler
+func NewHandler(repo *Repo) *Handler {
+	return &Handler{repo: repo}
+}
+
+// CreateDepartment handles department creation (HR/Admin only)
+func (h *Handler) CreateDepartment(c *gin.Context) {
+	if !requireHR(c, "Only HR can manage departments") {
+		return
+	}
+
+	var input CreateDepartmentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	department := &Department{
+		Name:       input.Name,
+		Code:       input.Code,
+		CostCenter: input.CostCenter,
+		ParentID:   input.ParentID,
+		HeadID:     input.HeadID,
+	}
+	if err := h.repo.CheckDepartment(c.Request.Context(), department, nil); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.CreateDepartment(c.Request.Context(), department); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create department"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, department)
+}
+
+// ListDepartments retrieves departments
+func (h *Handler) ListDepartments(c *gin.Context) {
+	var query DepartmentListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	departments, err := h.repo.ListDepartments(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list departments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"departments": departments})
+}
+
+// GetDepartment retrieves a department by ID
+func (h *Handler) GetDepartment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid department ID"})
+		return
+	}
+
+	department, err := h.repo.GetDepartment(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, department)
+}
+
+// UpdateDepartment updates a department (HR/Admin only)
+func (h *Handler) UpdateDepartment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid department ID"})
+		return
+	}
+	if !requireHR(c, "Only HR can manage departments") {
+		return
+	}
+
+	var input UpdateDepartmentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	department, err := h.repo.GetDepartment(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+
+	stored := *department
+
+	// Update fields if provided
+	if input.Name != nil {
+		department.Name = *input.Name
+	}
+	if input.Code != nil {
+		department.Code = *input.Code
+	}
+	if input.CostCenter != nil {
+		department.CostCenter = *input.CostCenter
+	}
+	if input.ParentID != nil {
+		department.ParentID = optionalID(*input.ParentID)
+	}
+	if input.HeadID != nil {
+		department.HeadID = optionalID(*input.HeadID)
+	}
+	if input.IsActive != nil {
+		department.IsActive = *input.IsActive
+	}
+
+	if err := h.repo.CheckDepartment(c.Request.Context(), department, &stored); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdateDepartment(c.Request.Context(), department); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update department"})
+		return
+	}
+
+	c.JSON(http.StatusOK, department)
+}
+
+// DeleteDepartment deletes a department nothing refers to any more (HR/Admin only)
+func (h *Handler) DeleteDepartment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid department ID"})
+		return
+	}
+	if !requireHR(c, "Only HR can manage departments") {
+		return
+	}
+
+	if err := h.repo.DeleteDepartment(c.Request.Context(), id); err != nil {
+		if errors.Is(err, errInUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error() + "; deactivate it instead"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Department deleted successfully"})
+}
+
+// CreatePosition handles position creation (HR/Admin only)
+func (h *Handler) CreatePosition(c *gin.Context) {
+	if !requireHR(c, "Only HR can manage positions") {
+		return
+	}
+
+	var input CreatePositionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	position := &Position{
+		Title:        input.Title,
+		DepartmentID: input.DepartmentID,
+		Grade:        input.Grade,
+		MinSalary:    input.MinSalary,
+		MaxSalary:    input.MaxSalary,
+	}
+	if err := h.repo.CheckPosition(c.Request.Context(), position, nil); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.CreatePosition(c.Request.Context(), position); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create position"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, position)
+}
+
+// ListPositions retrieves positions
+func (h *Handler) ListPositions(c *gin.Context) {
+	var query PositionListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	positions, err := h.repo.ListPositions(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list positions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"positions": positions})
+}
+
+// GetPosition retrieves a position by ID
+func (h *Handler) GetPosition(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position ID"})
+		return
+	}
+
+	position, err := h.repo.GetPosition(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, position)
+}
+
+// UpdatePosition updates a position (HR/Admin only). A new salary band applies to later employee changes;
+// employees already paid outside it are not changed.
+func (h *Handler) UpdatePosition(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position ID"})
+		return
+	}
+	if !requireHR(c, "Only HR can manage positions") {
+		return
+	}
+
+	var input UpdatePositionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	position, err := h.repo.GetPosition(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+		return
+	}
+
+	stored := *position
+
+	// Update fields if provided
+	if input.Title != nil {
+		position.Title = *input.Title
+	}
+	if input.DepartmentID != nil {
+		position.DepartmentID = optionalID(*input.DepartmentID)
+	}
+	if input.Grade != nil {
+		position.Grade = *input.Grade
+	}
+	if input.MinSalary != nil {
+		position.MinSalary = optionalAmount(*input.MinSalary)
+	}
+	if input.MaxSalary != nil {
+		position.MaxSalary = optionalAmount(*input.MaxSalary)
+	}
+	if input.IsActive != nil {
+		position.IsActive = *input.IsActive
+	}
+
+	if err := h.repo.CheckPosition(c.Request.Context(), position, &stored); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdatePosition(c.Request.Context(), position); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update position"})
+		return
+	}
+
+	c.JSON(http.StatusOK, position)
+}
+
+// DeletePosition deletes a position nothing refers to any more (HR/Admin only)
+func (h *Handler) DeletePosition(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position ID"})
+		return
+	}
+	if !requireHR(c, "Only HR can manage positions") {
+		return
+	}
+
+	if err := h.repo.DeletePosition(c.Request.Context(), id); err != nil {
+		if errors.Is(err, errInUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error() + "; deactivate it instead"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Position deleted successfully"})
+}
+
+// requireHR checks the caller is HR or an admin, answering the request itself when not
+func requireHR(c *gin.Context, message string) bool {
+	if _, err := middleware.GetUserID(c); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return false
+	}
+
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" && userRole != "hr" {
+		c.JSON(http.StatusForbidden, gin.H{"error": message})
+		return false
+	}
+	return true
+}
+
+// optionalID turns the nil UUID sent to clear a reference into nil
+func optionalID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+// optionalAmount turns the 0 sent to remove a salary bound into nil
+func optionalAmount(amount float64) *float64 {
+	if amount == 0 {
+		return nil
+	}
+	return &amount
+}
@@ -0,0 +1,100 @@
+package organization
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Department is a department of the company, optionally within a parent department and headed by an employee
+type Department struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name       string         `gorm:"type:varchar(100);not null" json:"name"`
+	Code       string         `gorm:"type:varchar(20)" json:"code,omitempty"`
+	CostCenter string         `gorm:"type:varchar(50)" json:"cost_center,omitempty"`
+	ParentID   *uuid.UUID     `gorm:"type:uuid" json:"parent_id,omitempty"`
+	HeadID     *uuid.UUID     `gorm:"type:uuid" json:"head_id,omitempty"`
+	IsActive   bool           `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt  time.Time      `gorm:"default:now()" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"default:now()" json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// Position is a job title with the grade and gross salary band of the employees holding it
+type Position struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Title        string         `gorm:"type:varchar(100);not null" json:"title"`
+	DepartmentID *uuid.UUID     `gorm:"type:uuid" json:"department_id,omitempty"`
+	Grade        string         `gorm:"type:varchar(20)" json:"grade,omitempty"`
+	MinSalary    *float64       `gorm:"type:numeric(15,2)" json:"min_salary,omitempty"`
+	MaxSalary    *float64       `gorm:"type:numeric(15,2)" json:"max_salary,omitempty"`
+	IsActive     bool           `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt    time.Time      `gorm:"default:now()" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"default:now()" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// CreateDepartmentRequest represents department creation request
+type CreateDepartmentRequest struct {
+	Name       string     `json:"name" binding:"required,max=100"`
+	Code       string     `json:"code,omitempty" binding:"omitempty,max=20"`
+	CostCenter string     `json:"cost_center,omitempty" binding:"omitempty,max=50"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	HeadID     *uuid.UUID `json:"head_id,omitempty"`
+}
+
+// UpdateDepartmentRequest represents department update request; a nil UUID clears parent_id or head_id
+type UpdateDepartmentRequest struct {
+	Name       *string    `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Code       *string    `json:"code,omitempty" binding:"omitempty,max=20"`
+	CostCenter *string    `json:"cost_center,omitempty" binding:"omitempty,max=50"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	HeadID     *uuid.UUID `json:"head_id,omitempty"`
+	IsActive   *bool      `json:"is_active,omitempty"`
+}
+
+// DepartmentListQuery represents query parameters for listing departments
+type DepartmentListQuery struct {
+	Search   string `form:"search"`
+	ParentID string `form:"parent_id" binding:"omitempty,uuid"`
+	IsActive *bool  `form:"is_active"`
+}
+
+// CreatePositionRequest represents position creation request
+type CreatePositionRequest struct {
+	Title        string     `json:"title" binding:"required,max=100"`
+	DepartmentID *uuid.UUID `json:"department_id,omitempty"`
+	Grade        string     `json:"grade,omitempty" binding:"omitempty,max=20"`
+	MinSalary    *float64   `json:"min_salary,omitempty" binding:"omitempty,min=0"`
+	MaxSalary    *float64   `json:"max_salary,omitempty" binding:"omitempty,min=0"`
+}
+
+// UpdatePositionRequest represents position update request; a nil UUID clears department_id, and a salary of 0
+// removes that end of the band
+type UpdatePositionRequest struct {
+	Title        *string    `json:"title,omitempty" binding:"omitempty,min=1,max=100"`
+	DepartmentID *uuid.UUID `json:"department_id,omitempty"`
+	Grade        *string    `json:"grade,omitempty" binding:"omitempty,max=20"`
+	MinSalary    *float64   `json:"min_salary,omitempty" binding:"omitempty,min=0"`
+	MaxSalary    *float64   `json:"max_salary,omitempty" binding:"omitempty,min=0"`
+	IsActive     *bool      `json:"is_active,omitempty"`
+}
+
+// PositionListQuery represents query parameters for listing positions
+type PositionListQuery struct {
+	Search       string `form:"search"`
+	DepartmentID string `form:"department_id" binding:"omitempty,uuid"`
+	Grade        string `form:"grade"`
+	IsActive     *bool  `form:"is_active"`
+}
+
+// TableName specifies the table name for Department model
+func (Department) TableName() string {
+	return "departments"
+}
+
+// TableName specifies the table name for Position model
+func (Position) TableName() string {
+	return "positions"
+}
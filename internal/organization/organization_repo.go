@@ -0,0 +1,391 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// errInUse is returned when deleting a department or position that is still referenced
+var errInUse = errors.New("still in use")
+
+// Repo handles database operations for departments and positions
+type Repo struct {
+	db *gorm.DB
+}
+
+// NewRepo creates a new organization repository
+func NewRepo(database *gorm.DB) *Repo {
+	return &Repo{db: database}
+}
+
+// CreateDepartment creates a new department
+func (r *Repo) CreateDepartment(ctx context.Context, department *Department) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	department.IsActive = true
+	if err := r.db.WithContext(ctx).Create(department).Error; err != nil {
+		return fmt.Errorf("create department: %w", err)
+	}
+	return nil
+}
+
+// GetDepartment retrieves a department by ID
+func (r *Repo) GetDepartment(ctx context.Context, id uuid.UUID) (*Department, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var department Department
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&department).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("department not found")
+		}
+		return nil, fmt.Errorf("get department by id: %w", err)
+	}
+	return &department, nil
+}
+
+// ListDepartments retrieves departments by name
+func (r *Repo) ListDepartments(ctx context.Context, query DepartmentListQuery) ([]Department, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Model(&Department{})
+	if query.Search != "" {
+		search := "%" + query.Search + "%"
+		db = db.Where("name ILIKE ? OR code ILIKE ? OR cost_center ILIKE ?", search, search, search)
+	}
+	if query.ParentID != "" {
+		db = db.Where("parent_id = ?", query.ParentID)
+	}
+	if query.IsActive != nil {
+		db = db.Where("is_active = ?", *query.IsActive)
+	}
+
+	var departments []Department
+	if err := db.Order("name ASC").Find(&departments).Error; err != nil {
+		return nil, fmt.Errorf("list departments: %w", err)
+	}
+	return departments, nil
+}
+
+// UpdateDepartment updates a department. A new name is copied to the employees and KPIs of the department.
+func (r *Repo) UpdateDepartment(ctx context.Context, department *Department) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	department.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(department).Error; err != nil {
+			return fmt.Errorf("update department: %w", err)
+		}
+		for _, table := range []string{"employees", "kpis"} {
+			if err := tx.Table(table).Where("department_id = ? AND department IS DISTINCT FROM ?", department.ID, department.Name).
+				Update("department", department.Name).Error; err != nil {
+				return fmt.Errorf("rename department of %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteDepartment soft deletes a department no employee, KPI, position or other department refers to
+func (r *Repo) DeleteDepartment(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, ref := range []struct {
+			table  string
+			column string
+			label  string
+		}{
+			{"employees", "department_id", "employee(s)"},
+			{"kpis", "department_id", "KPI(s)"},
+			{"positions", "department_id", "position(s)"},
+			{"departments", "parent_id", "sub-department(s)"},
+		} {
+			db := tx.Table(ref.table).Where(ref.column+" = ?", id)
+			if ref.table != "kpis" {
+				db = db.Where("deleted_at IS NULL")
+			}
+			var count int64
+			if err := db.Count(&count).Error; err != nil {
+				return fmt.Errorf("count %s of department: %w", ref.table, err)
+			}
+			if count > 0 {
+				return fmt.Errorf("department is %w by %d %s", errInUse, count, ref.label)
+			}
+		}
+
+		result := tx.Delete(&Department{}, "id = ?", id)
+		if result.Error != nil {
+			return fmt.Errorf("delete department: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("department not found")
+		}
+		return nil
+	})
+}
+
+// CheckDepartment trims a department's fields and checks that its name and code are free, that its parent is an
+// active department outside its own sub-departments, and that its head is a current employee. Unchanged
+// parents and heads are not checked again.
+func (r *Repo) CheckDepartment(ctx context.Context, d, previous *Department) error {
+	d.Name = strings.TrimSpace(d.Name)
+	d.Code = strings.ToUpper(strings.TrimSpace(d.Code))
+	d.CostCenter = strings.TrimSpace(d.CostCenter)
+	if d.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	var stored Department
+	if previous != nil {
+		stored = *previous
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if previous == nil || !strings.EqualFold(d.Name, stored.Name) {
+		taken, err := r.taken(ctx, &Department{}, "LOWER(name) = LOWER(?)", d.Name, d.ID)
+		if err != nil {
+			return fmt.Errorf("check department name: %w", err)
+		}
+		if taken {
+			return fmt.Errorf("department %s already exists", d.Name)
+		}
+	}
+	if d.Code != "" && (previous == nil || d.Code != stored.Code) {
+		taken, err := r.taken(ctx, &Department{}, "code = ?", d.Code, d.ID)
+		if err != nil {
+			return fmt.Errorf("check department code: %w", err)
+		}
+		if taken {
+			return fmt.Errorf("department code %s is already used", d.Code)
+		}
+	}
+
+	if d.ParentID != nil && (previous == nil || stored.ParentID == nil || *stored.ParentID != *d.ParentID) {
+		if d.ID != uuid.Nil && *d.ParentID == d.ID {
+			return fmt.Errorf("a department cannot be its own parent")
+		}
+		parent, err := r.GetDepartment(ctx, *d.ParentID)
+		if err != nil {
+			return fmt.Errorf("parent department not found")
+		}
+		if !parent.IsActive {
+			return fmt.Errorf("parent department %s is inactive", parent.Name)
+		}
+		if d.ID != uuid.Nil {
+			// Walk up the new parent's line; UNION drops repeated rows, so lines that already loop still end
+			var cycle bool
+			if err := r.db.WithContext(ctx).Raw(`
+				WITH RECURSIVE line AS (
+					SELECT id, parent_id FROM departments WHERE id = ? AND deleted_at IS NULL
+					UNION
+					SELECT d.id, d.parent_id FROM departments d JOIN line l ON d.id = l.parent_id WHERE d.deleted_at IS NULL
+				)
+				SELECT EXISTS (SELECT 1 FROM line WHERE id = ?)`, parent.ID, d.ID).Scan(&cycle).Error; err != nil {
+				return fmt.Errorf("check parent department: %w", err)
+			}
+			if cycle {
+				return fmt.Errorf("parent_id would create a cycle: %s is a sub-department of %s", parent.Name, d.Name)
+			}
+		}
+	}
+
+	if d.HeadID != nil && (previous == nil || stored.HeadID == nil || *stored.HeadID != *d.HeadID) {
+		var head struct {
+			ID     uuid.UUID
+			Status string
+		}
+		if err := r.db.WithContext(ctx).Table("employees").Select("id", "status").
+			Where("id = ? AND deleted_at IS NULL", *d.HeadID).Limit(1).Scan(&head).Error; err != nil {
+			return fmt.Errorf("check department head: %w", err)
+		}
+		if head.ID == uuid.Nil {
+			return fmt.Errorf("department head not found")
+		}
+		if head.Status == "terminated" {
+			return fmt.Errorf("department head is terminated")
+		}
+	}
+	return nil
+}
+
+// CreatePosition creates a new position
+func (r *Repo) CreatePosition(ctx context.Context, position *Position) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	position.IsActive = true
+	if err := r.db.WithContext(ctx).Create(position).Error; err != nil {
+		return fmt.Errorf("create position: %w", err)
+	}
+	return nil
+}
+
+// GetPosition retrieves a position by ID
+func (r *Repo) GetPosition(ctx context.Context, id uuid.UUID) (*Position, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var position Position
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&position).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("position not found")
+		}
+		return nil, fmt.Errorf("get position by id: %w", err)
+	}
+	return &position, nil
+}
+
+// ListPositions retrieves positions by title
+func (r *Repo) ListPositions(ctx context.Context, query PositionListQuery) ([]Position, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Model(&Position{})
+	if query.Search != "" {
+		db = db.Where("title ILIKE ?", "%"+query.Search+"%")
+	}
+	if query.DepartmentID != "" {
+		db = db.Where("department_id = ?", query.DepartmentID)
+	}
+	if query.Grade != "" {
+		db = db.Where("grade = ?", query.Grade)
+	}
+	if query.IsActive != nil {
+		db = db.Where("is_active = ?", *query.IsActive)
+	}
+
+	var positions []Position
+	if err := db.Order("title ASC").Find(&positions).Error; err != nil {
+		return nil, fmt.Errorf("list positions: %w", err)
+	}
+	return positions, nil
+}
+
+// UpdatePosition updates a position. A new title is copied to the employees and KPIs of the position.
+func (r *Repo) UpdatePosition(ctx context.Context, position *Position) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	position.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(position).Error; err != nil {
+			return fmt.Errorf("update position: %w", err)
+		}
+		for _, table := range []string{"employees", "kpis"} {
+			if err := tx.Table(table).Where("position_id = ? AND position IS DISTINCT FROM ?", position.ID, position.Title).
+				Update("position", position.Title).Error; err != nil {
+				return fmt.Errorf("rename position of %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeletePosition soft deletes a position no employee or KPI refers to
+func (r *Repo) DeletePosition(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var employees, kpis int64
+		if err := tx.Table("employees").Where("position_id = ? AND deleted_at IS NULL", id).Count(&employees).Error; err != nil {
+			return fmt.Errorf("count employees of position: %w", err)
+		}
+		if err := tx.Table("kpis").Where("position_id = ?", id).Count(&kpis).Error; err != nil {
+			return fmt.Errorf("count KPIs of position: %w", err)
+		}
+		if employees > 0 {
+			return fmt.Errorf("position is %w by %d employee(s)", errInUse, employees)
+		}
+		if kpis > 0 {
+			return fmt.Errorf("position is %w by %d KPI(s)", errInUse, kpis)
+		}
+
+		result := tx.Delete(&Position{}, "id = ?", id)
+		if result.Error != nil {
+			return fmt.Errorf("delete position: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("position not found")
+		}
+		return nil
+	})
+}
+
+// CheckPosition trims a position's fields and checks that its title is free, that its department is active and
+// that its salary band is consistent. An unchanged department is not checked again.
+func (r *Repo) CheckPosition(ctx context.Context, p, previous *Position) error {
+	p.Title = strings.TrimSpace(p.Title)
+	p.Grade = strings.TrimSpace(p.Grade)
+	if p.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if p.MinSalary != nil && p.MaxSalary != nil && *p.MinSalary > *p.MaxSalary {
+		return fmt.Errorf("min_salary cannot be above max_salary")
+	}
+
+	var stored Position
+	if previous != nil {
+		stored = *previous
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if previous == nil || !strings.EqualFold(p.Title, stored.Title) {
+		taken, err := r.taken(ctx, &Position{}, "LOWER(title) = LOWER(?)", p.Title, p.ID)
+		if err != nil {
+			return fmt.Errorf("check position title: %w", err)
+		}
+		if taken {
+			return fmt.Errorf("position %s already exists", p.Title)
+		}
+	}
+	if p.DepartmentID != nil && (previous == nil || stored.DepartmentID == nil || *stored.DepartmentID != *p.DepartmentID) {
+		department, err := r.GetDepartment(ctx, *p.DepartmentID)
+		if err != nil {
+			return fmt.Errorf("department not found")
+		}
+		if !department.IsActive {
+			return fmt.Errorf("department %s is inactive", department.Name)
+		}
+	}
+	return nil
+}
+
+// CheckSalary checks that a gross salary lies within the position's salary band
+func (p *Position) CheckSalary(gross float64) error {
+	if p.MinSalary != nil && gross < *p.MinSalary {
+		return fmt.Errorf("gross_salary %.2f is below the %.2f minimum of position %s", gross, *p.MinSalary, p.Title)
+	}
+	if p.MaxSalary != nil && gross > *p.MaxSalary {
+		return fmt.Errorf("gross_salary %.2f is above the %.2f maximum of position %s", gross, *p.MaxSalary, p.Title)
+	}
+	return nil
+}
+
+// taken reports whether a row of model other than id matches a uniqueness condition
+func (r *Repo) taken(ctx context.Context, model interface{}, condition string, value string, id uuid.UUID) (bool, error) {
+	db := r.db.WithContext(ctx).Model(model).Where(condition, value)
+	if id != uuid.Nil {
+		db = db.Where("id <> ?", id)
+	}
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,36 @@
+package organization
+
+import (
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterRoutes registers department and position routes
+func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
+	repo := NewRepo(gormDB)
+	handler := NewHandler(repo)
+
+	departments := rg.Group("/departments")
+	departments.Use(middleware.AuthMiddleware())
+	{
+		// Departments (HR/Admin write, authenticated read)
+		departments.GET("", handler.ListDepartments)
+		departments.POST("", middleware.RequireRole("admin", "hr"), handler.CreateDepartment)
+		departments.GET("/:id", handler.GetDepartment)
+		departments.PUT("/:id", middleware.RequireRole("admin", "hr"), handler.UpdateDepartment)
+		departments.DELETE("/:id", middleware.RequireRole("admin", "hr"), handler.DeleteDepartment)
+	}
+
+	positions := rg.Group("/positions")
+	positions.Use(middleware.AuthMiddleware())
+	{
+		// Positions and their salary bands (HR/Admin write, authenticated read)
+		positions.GET("", handler.ListPositions)
+		positions.POST("", middleware.RequireRole("admin", "hr"), handler.CreatePosition)
+		positions.GET("/:id", handler.GetPosition)
+		positions.PUT("/:id", middleware.RequireRole("admin", "hr"), handler.UpdatePosition)
+		positions.DELETE("/:id", middleware.RequireRole("admin", "hr"), handler.DeletePosition)
+	}
+}
@@ -0,0 +1,143 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go-server/internal/attendance"
+	"go-server/internal/company"
+	"go-server/internal/leave"
+
+	"gorm.io/gorm"
+)
+
+// absenceLineItems withholds pay for the employee's approved unpaid leave and unjustified absences
+// between start and end, the part of the draft period the employee was employed, prorated on the
+// period's working days
+func (r *Repo) absenceLineItems(ctx context.Context, draft *PayrollDraft, start, end time.Time) ([]PayrollLineItem, error) {
+	calendar, workDaysPerWeek, workingDays, err := r.getPeriodWorkingDays(ctx, draft)
+	if err != nil || workingDays == 0 {
+		return nil, err
+	}
+
+	var leaves []leave.Leave
+	if err := r.db.WithContext(ctx).
+		Where("employee_id = ? AND status = ? AND start_date <= ? AND end_date >= ?",
+			draft.EmployeeID, "approved", end, start).
+		Order("start_date ASC").Find(&leaves).Error; err != nil {
+		return nil, fmt.Errorf("get approved leaves: %w", err)
+	}
+
+	// Days covered by any approved leave are justified; unpaid leave days are withheld
+	onLeave := map[string]bool{}
+	unpaidDays := 0.0
+	for _, l := range leaves {
+		leaveDays := 0.0
+		eachDay(maxTime(l.StartDate, start), minTime(l.EndDate, end), func(day time.Time) {
+			if calendar.IsWorkingDay(day, workDaysPerWeek) {
+				onLeave[day.Format("2006-01-02")] = true
+				leaveDays++
+			}
+		})
+		if l.LeaveType == "unpaid" {
+			// Half-day leaves request fewer days than the dates they span
+			unpaidDays += math.Min(leaveDays, l.DaysRequested)
+		}
+	}
+
+	var absences []attendance.Attendance
+	if err := r.db.WithContext(ctx).
+		Where("employee_id = ? AND status = ? AND date BETWEEN ? AND ?",
+			draft.EmployeeID, "absent", start, end).
+		Find(&absences).Error; err != nil {
+		return nil, fmt.Errorf("get absences: %w", err)
+	}
+
+	absentDays := 0.0
+	for _, a := range absences {
+		if calendar.IsWorkingDay(a.Date, workDaysPerWeek) && !onLeave[a.Date.Format("2006-01-02")] {
+			absentDays++
+		}
+	}
+
+	dailyRate := draft.BaseSalary / float64(workingDays)
+
+	var items []PayrollLineItem
+	for _, absence := range []struct {
+		code   string
+		label  string
+		source string
+		days   float64
+	}{
+		{"unpaid_leave", "Congé sans solde", LineItemSourceLeave, unpaidDays},
+		{"unjustified_absence", "Absences non justifiées", LineItemSourceAttendance, absentDays},
+	} {
+		amount := math.Round(absence.days*dailyRate*100) / 100
+		if amount <= 0 {
+			continue
+		}
+
+		items = append(items, PayrollLineItem{
+			Type:      LineItemAbsence,
+			Code:      absence.code,
+			Label:     fmt.Sprintf("%s (%g j sur %d j ouvrés)", absence.label, absence.days, workingDays),
+			Amount:    amount,
+			Source:    absence.source,
+			CreatedBy: draft.CreatedBy,
+		})
+	}
+	return items, nil
+}
+
+// getPeriodWorkingDays loads the holiday calendar and working week for a draft and counts the period's working days
+func (r *Repo) getPeriodWorkingDays(ctx context.Context, draft *PayrollDraft) (*holidayCalendar, int, int, error) {
+	calendar, err := r.getHolidayCalendar(ctx, draft.PeriodStart, draft.PeriodEnd)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	workDaysPerWeek, err := r.getWorkDaysPerWeek(ctx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	workingDays := 0
+	eachDay(draft.PeriodStart, draft.PeriodEnd, func(day time.Time) {
+		if calendar.IsWorkingDay(day, workDaysPerWeek) {
+			workingDays++
+		}
+	})
+	return calendar, workDaysPerWeek, workingDays, nil
+}
+
+// getWorkDaysPerWeek reads the working week length from company settings, using the schema default when unset
+func (r *Repo) getWorkDaysPerWeek(ctx context.Context) (int, error) {
+	var settings company.CompanySettings
+	if err := r.db.WithContext(ctx).First(&settings).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 5, nil
+		}
+		return 0, fmt.Errorf("get company settings: %w", err)
+	}
+	if settings.WorkDaysPerWeek < 1 || settings.WorkDaysPerWeek > 7 {
+		return 5, nil
+	}
+	return settings.WorkDaysPerWeek, nil
+}
+
+// minTime returns the earlier of two times
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// maxTime returns the later of two times
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
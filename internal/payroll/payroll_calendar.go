@@ -0,0 +1,63 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// holidayCalendar answers whether a date is a company holiday, including recurring ones
+type holidayCalendar struct {
+	dates     map[string]bool
+	recurring map[string]bool
+}
+
+// IsHoliday reports whether t falls on a company holiday
+func (h *holidayCalendar) IsHoliday(t time.Time) bool {
+	return h.dates[t.Format("2006-01-02")] || h.recurring[t.Format("01-02")]
+}
+
+// IsWorkingDay reports whether t is a working day for a company working workDaysPerWeek days from Monday
+func (h *holidayCalendar) IsWorkingDay(t time.Time, workDaysPerWeek int) bool {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return weekday <= workDaysPerWeek && !h.IsHoliday(t)
+}
+
+// eachDay calls fn for every calendar day from start to end inclusive
+func eachDay(start, end time.Time, fn func(day time.Time)) {
+	day, last := dateOnly(start), dateOnly(end)
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		fn(day)
+	}
+}
+
+// dateOnly strips the time of day so dates stored with different locations compare equal
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// getHolidayCalendar loads the company holidays relevant to a period
+func (r *Repo) getHolidayCalendar(ctx context.Context, periodStart, periodEnd time.Time) (*holidayCalendar, error) {
+	var rows []struct {
+		Date        time.Time
+		IsRecurring bool
+	}
+	if err := r.db.WithContext(ctx).Table("company_holidays").Select("date", "is_recurring").
+		Where("(date BETWEEN ? AND ?) OR is_recurring = ?", periodStart, periodEnd, true).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("get company holidays: %w", err)
+	}
+
+	calendar := &holidayCalendar{dates: map[string]bool{}, recurring: map[string]bool{}}
+	for _, row := range rows {
+		if row.IsRecurring {
+			calendar.recurring[row.Date.Format("01-02")] = true
+		} else {
+			calendar.dates[row.Date.Format("2006-01-02")] = true
+		}
+	}
+	return calendar, nil
+}
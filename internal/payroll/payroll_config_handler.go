@@ -1,7 +1,9 @@
 package payroll
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"go-server/internal/middleware"
 
@@ -36,14 +38,20 @@ func (h *ConfigHandler) CreatePayrollConfiguration(c *gin.Context) {
 		return
 	}
 
+	effectiveFrom := time.Now()
+	if input.EffectiveFrom != nil {
+		effectiveFrom = *input.EffectiveFrom
+	}
+
 	config := &PayrollConfiguration{
-		Key:         input.Key,
-		Value:       input.Value,
-		Description: input.Description,
-		DataType:    input.DataType,
-		Category:    input.Category,
-		IsActive:    true,
-		CreatedBy:   userID,
+		Key:           input.Key,
+		Value:         input.Value,
+		Description:   input.Description,
+		DataType:      input.DataType,
+		Category:      input.Category,
+		IsActive:      true,
+		EffectiveFrom: dateOnly(effectiveFrom),
+		CreatedBy:     userID,
 	}
 
 	if err := h.configRepo.CreateConfig(c.Request.Context(), config); err != nil {
@@ -76,14 +84,36 @@ func (h *ConfigHandler) UpdatePayrollConfiguration(c *gin.Context) {
 		return
 	}
 
-	// Update fields if provided
-	if input.Value != nil {
-		config.Value = *input.Value
+	// A new value never overwrites a version: it starts a new one so past periods keep their rules
+	if input.Value != nil && *input.Value != config.Value {
+		effectiveFrom := time.Now()
+		if input.EffectiveFrom != nil {
+			effectiveFrom = *input.EffectiveFrom
+		}
+		config, err = h.configRepo.ReviseConfig(c.Request.Context(), config, *input.Value, effectiveFrom, userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else if input.EffectiveFrom != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "effective_from can only be given with a new value"})
+		return
 	}
+
+	// Update fields if provided
 	if input.Description != nil {
 		config.Description = *input.Description
 	}
-	if input.IsActive != nil {
+	if input.IsActive != nil && *input.IsActive != config.IsActive {
+		locked, err := h.configRepo.IsVersionLocked(c.Request.Context(), config)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check configuration version"})
+			return
+		}
+		if locked {
+			c.JSON(http.StatusConflict, gin.H{"error": errConfigVersionLocked.Error()})
+			return
+		}
 		config.IsActive = *input.IsActive
 	}
 	config.UpdatedBy = &userID
@@ -96,11 +126,18 @@ func (h *ConfigHandler) UpdatePayrollConfiguration(c *gin.Context) {
 	c.JSON(http.StatusOK, config)
 }
 
-// DeletePayrollConfiguration deletes a payroll configuration (Admin only)
+// DeletePayrollConfiguration deletes a payroll configuration version no past period relies on (Admin only)
 func (h *ConfigHandler) DeletePayrollConfiguration(c *gin.Context) {
 	id := c.Param("id")
 	if err := h.configRepo.DeleteConfig(c.Request.Context(), parseUUID(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, errConfigVersionLocked):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err.Error() == "configuration not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Configuration not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -141,143 +178,6 @@ func (h *ConfigHandler) GetPayrollConfiguration(c *gin.Context) {
 	c.JSON(http.StatusOK, config)
 }
 
-// CreateIRSABracket creates a new IRSA tax bracket (Admin only)
-func (h *ConfigHandler) CreateIRSABracket(c *gin.Context) {
-	var input CreateIRSATaxBracketRequest
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Get user info from context
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	bracket := &IRSATaxBracket{
-		MinIncome:     input.MinIncome,
-		MaxIncome:     input.MaxIncome,
-		TaxRate:       input.TaxRate,
-		MinTax:        input.MinTax,
-		BracketName:   input.BracketName,
-		IsActive:      true,
-		SortOrder:     input.SortOrder,
-		EffectiveDate: input.EffectiveDate,
-		CreatedBy:     userID,
-	}
-
-	if err := h.configRepo.CreateIRSABracket(c.Request.Context(), bracket); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusCreated, bracket)
-}
-
-// GetIRSABracketByID retrieves an IRSA tax bracket by ID
-func (h *ConfigHandler) GetIRSABracketByID(c *gin.Context) {
-	id := c.Param("id")
-	bracket, err := h.configRepo.GetIRSABracketByID(c.Request.Context(), parseUUID(id))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Tax bracket not found"})
-		return
-	}
-
-	c.JSON(http.StatusOK, bracket)
-}
-
-// UpdateIRSABracket updates an IRSA tax bracket (Admin only)
-func (h *ConfigHandler) UpdateIRSABracket(c *gin.Context) {
-	id := c.Param("id")
-	var input UpdateIRSATaxBracketRequest
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Get user info from context
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	bracket, err := h.configRepo.GetIRSABracketByID(c.Request.Context(), parseUUID(id))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Tax bracket not found"})
-		return
-	}
-
-	// Update fields if provided
-	if input.MinIncome != nil {
-		bracket.MinIncome = *input.MinIncome
-	}
-	if input.MaxIncome != nil {
-		bracket.MaxIncome = input.MaxIncome
-	}
-	if input.TaxRate != nil {
-		bracket.TaxRate = *input.TaxRate
-	}
-	if input.MinTax != nil {
-		bracket.MinTax = *input.MinTax
-	}
-	if input.BracketName != nil {
-		bracket.BracketName = *input.BracketName
-	}
-	if input.SortOrder != nil {
-		bracket.SortOrder = *input.SortOrder
-	}
-	if input.EffectiveDate != nil {
-		bracket.EffectiveDate = *input.EffectiveDate
-	}
-	if input.IsActive != nil {
-		bracket.IsActive = *input.IsActive
-	}
-	bracket.UpdatedBy = &userID
-
-	if err := h.configRepo.UpdateIRSABracket(c.Request.Context(), bracket); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, bracket)
-}
-
-// DeleteIRSABracket deletes an IRSA tax bracket (Admin only)
-func (h *ConfigHandler) DeleteIRSABracket(c *gin.Context) {
-	id := c.Param("id")
-	if err := h.configRepo.DeleteIRSABracket(c.Request.Context(), parseUUID(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusNoContent, nil)
-}
-
-// ListIRSABrackets lists all IRSA tax brackets
-func (h *ConfigHandler) ListIRSABrackets(c *gin.Context) {
-	var query IRSATaxBracketListQuery
-	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	brackets, total, err := h.configRepo.ListIRSABrackets(c.Request.Context(), query)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tax brackets"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"brackets": brackets,
-		"total":    total,
-		"limit":    query.Limit,
-		"offset":   query.Offset,
-	})
-}
-
 // parseUUID converts a string to a UUID
 func parseUUID(s string) uuid.UUID {
 	id, _ := uuid.Parse(s)
@@ -2,12 +2,14 @@ package payroll
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ConfigRepo handles database operations for payroll configurations
@@ -20,53 +22,78 @@ func NewConfigRepo(database *gorm.DB) *ConfigRepo {
 	return &ConfigRepo{db: database}
 }
 
-// GetConfigValue retrieves a configuration value by key
-func (r *ConfigRepo) GetConfigValue(ctx context.Context, key string) (string, error) {
+// inForceAt restricts a query to rule versions whose validity range contains at
+func inForceAt(db *gorm.DB, fromColumn string, at time.Time) *gorm.DB {
+	day := dateOnly(at)
+	return db.Where(fromColumn+" <= ? AND (effective_to IS NULL OR effective_to >= ?)", day, day)
+}
+
+// GetConfigAt retrieves the version of a configuration key in force on a date
+func (r *ConfigRepo) GetConfigAt(ctx context.Context, key string, at time.Time) (*PayrollConfiguration, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var config PayrollConfiguration
-	if err := r.db.WithContext(ctx).Where("key = ? AND is_active = ?", key, true).First(&config).Error; err != nil {
+	if err := inForceAt(r.db.WithContext(ctx), "effective_from", at).
+		Where("key = ? AND is_active = ?", key, true).
+		Order("effective_from DESC").First(&config).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return "", fmt.Errorf("configuration key '%s' not found", key)
+			return nil, fmt.Errorf("configuration key '%s' not found for %s", key, at.Format("2006-01-02"))
 		}
-		return "", fmt.Errorf("get configuration: %w", err)
+		return nil, fmt.Errorf("get configuration: %w", err)
+	}
+	return &config, nil
+}
+
+// GetConfigValue retrieves the value of a configuration key in force on a date
+func (r *ConfigRepo) GetConfigValue(ctx context.Context, key string, at time.Time) (string, error) {
+	config, err := r.GetConfigAt(ctx, key, at)
+	if err != nil {
+		return "", err
 	}
 	return config.Value, nil
 }
 
-// GetConfigValueAsFloat retrieves a configuration value as a float64
-func (r *ConfigRepo) GetConfigValueAsFloat(ctx context.Context, key string) (float64, error) {
-	value, err := r.GetConfigValue(ctx, key)
+// GetConfigValueAsFloat retrieves a configuration value in force on a date as a float64
+func (r *ConfigRepo) GetConfigValueAsFloat(ctx context.Context, key string, at time.Time) (float64, error) {
+	value, err := r.GetConfigValue(ctx, key, at)
 	if err != nil {
 		return 0, err
 	}
 	return strconv.ParseFloat(value, 64)
 }
 
-// GetConfigValueAsInt retrieves a configuration value as an int
-func (r *ConfigRepo) GetConfigValueAsInt(ctx context.Context, key string) (int, error) {
-	value, err := r.GetConfigValue(ctx, key)
+// GetConfigValueAsInt retrieves a configuration value in force on a date as an int
+func (r *ConfigRepo) GetConfigValueAsInt(ctx context.Context, key string, at time.Time) (int, error) {
+	value, err := r.GetConfigValue(ctx, key, at)
 	if err != nil {
 		return 0, err
 	}
 	return strconv.Atoi(value)
 }
 
-// GetConfigValueAsBool retrieves a configuration value as a bool
-func (r *ConfigRepo) GetConfigValueAsBool(ctx context.Context, key string) (bool, error) {
-	value, err := r.GetConfigValue(ctx, key)
+// GetConfigValueAsBool retrieves a configuration value in force on a date as a bool
+func (r *ConfigRepo) GetConfigValueAsBool(ctx context.Context, key string, at time.Time) (bool, error) {
+	value, err := r.GetConfigValue(ctx, key, at)
 	if err != nil {
 		return false, err
 	}
 	return strconv.ParseBool(value)
 }
 
-// CreateConfig creates a new payroll configuration
+// CreateConfig creates the first version of a payroll configuration key
 func (r *ConfigRepo) CreateConfig(ctx context.Context, config *PayrollConfiguration) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&PayrollConfiguration{}).Where("key = ?", config.Key).Count(&count).Error; err != nil {
+		return fmt.Errorf("check existing configuration: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("configuration key '%s' already exists; update it to create a new version", config.Key)
+	}
+
 	config.CreatedAt = time.Now()
 	config.UpdatedAt = time.Now()
 
@@ -76,6 +103,57 @@ func (r *ConfigRepo) CreateConfig(ctx context.Context, config *PayrollConfigurat
 	return nil
 }
 
+// ReviseConfig closes the current version of a configuration the day before effectiveFrom
+// and creates a new version holding value from that date
+func (r *ConfigRepo) ReviseConfig(ctx context.Context, current *PayrollConfiguration, value string, effectiveFrom time.Time, updatedBy uuid.UUID) (*PayrollConfiguration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	effectiveFrom = dateOnly(effectiveFrom)
+	revised := &PayrollConfiguration{
+		Key:           current.Key,
+		Value:         value,
+		Description:   current.Description,
+		DataType:      current.DataType,
+		Category:      current.Category,
+		IsActive:      current.IsActive,
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     updatedBy,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked PayrollConfiguration
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", current.ID).First(&locked).Error; err != nil {
+			return fmt.Errorf("lock configuration: %w", err)
+		}
+		if locked.EffectiveTo != nil {
+			return fmt.Errorf("only the current version of a configuration can be changed")
+		}
+		if !effectiveFrom.After(dateOnly(locked.EffectiveFrom)) {
+			return fmt.Errorf("effective_from must be after %s, when the current version took effect",
+				locked.EffectiveFrom.Format("2006-01-02"))
+		}
+
+		closedOn := effectiveFrom.AddDate(0, 0, -1)
+		if err := tx.Model(&locked).Updates(map[string]interface{}{
+			"effective_to": closedOn,
+			"updated_by":   updatedBy,
+			"updated_at":   time.Now(),
+		}).Error; err != nil {
+			return fmt.Errorf("close configuration version: %w", err)
+		}
+
+		if err := tx.Create(revised).Error; err != nil {
+			return fmt.Errorf("create configuration version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revised, nil
+}
+
 // UpdateConfig updates a payroll configuration
 func (r *ConfigRepo) UpdateConfig(ctx context.Context, config *PayrollConfiguration) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -124,6 +202,9 @@ func (r *ConfigRepo) ListConfigs(ctx context.Context, query PayrollConfiguration
 	if query.IsActive != nil {
 		db = db.Where("is_active = ?", *query.IsActive)
 	}
+	if query.EffectiveAt != nil {
+		db = inForceAt(db, "effective_from", *query.EffectiveAt)
+	}
 
 	// Count total
 	if err := db.Count(&total).Error; err != nil {
@@ -136,129 +217,64 @@ func (r *ConfigRepo) ListConfigs(ctx context.Context, query PayrollConfiguration
 		limit = 50
 	}
 
-	if err := db.Limit(limit).Offset(query.Offset).Order("category, key").Find(&configs).Error; err != nil {
+	if err := db.Limit(limit).Offset(query.Offset).Order("category, key, effective_from DESC").Find(&configs).Error; err != nil {
 		return nil, 0, fmt.Errorf("list configurations: %w", err)
 	}
 
 	return configs, total, nil
 }
 
-// DeleteConfig soft deletes a payroll configuration
-func (r *ConfigRepo) DeleteConfig(ctx context.Context, id uuid.UUID) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
+// errConfigVersionLocked is returned when deleting or deactivating a configuration version that past periods rely on
+var errConfigVersionLocked = errors.New("closed or used configuration versions cannot be deleted or deactivated: revise the value instead")
 
-	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&PayrollConfiguration{})
-	if result.Error != nil {
-		return fmt.Errorf("delete configuration: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("configuration not found")
-	}
-	return nil
-}
-
-// GetActiveIRSABrackets retrieves all active IRSA tax brackets ordered by sort_order
-func (r *ConfigRepo) GetActiveIRSABrackets(ctx context.Context) ([]IRSATaxBracket, error) {
+// IsVersionLocked reports whether a configuration version was closed by a successor or used by a payroll
+// calculation. Such versions keep the rules of past periods and are not deleted or deactivated.
+func (r *ConfigRepo) IsVersionLocked(ctx context.Context, config *PayrollConfiguration) (bool, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	var brackets []IRSATaxBracket
-	if err := r.db.WithContext(ctx).
-		Where("is_active = ?", true).
-		Order("sort_order ASC").
-		Find(&brackets).Error; err != nil {
-		return nil, fmt.Errorf("get IRSA brackets: %w", err)
-	}
-	return brackets, nil
+	return configVersionLocked(r.db.WithContext(ctx), config)
 }
 
-// CreateIRSABracket creates a new IRSA tax bracket
-func (r *ConfigRepo) CreateIRSABracket(ctx context.Context, bracket *IRSATaxBracket) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	bracket.CreatedAt = time.Now()
-	bracket.UpdatedAt = time.Now()
-
-	if err := r.db.WithContext(ctx).Create(bracket).Error; err != nil {
-		return fmt.Errorf("create IRSA bracket: %w", err)
-	}
-	return nil
-}
-
-// UpdateIRSABracket updates an IRSA tax bracket
-func (r *ConfigRepo) UpdateIRSABracket(ctx context.Context, bracket *IRSATaxBracket) error {
+// DeleteConfig deletes a configuration version that is neither closed nor used by a payroll calculation
+func (r *ConfigRepo) DeleteConfig(ctx context.Context, id uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	bracket.UpdatedAt = time.Now()
-
-	if err := r.db.WithContext(ctx).Save(bracket).Error; err != nil {
-		return fmt.Errorf("update IRSA bracket: %w", err)
-	}
-	return nil
-}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var config PayrollConfiguration
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&config).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("configuration not found")
+			}
+			return fmt.Errorf("get configuration: %w", err)
+		}
 
-// GetIRSABracketByID retrieves an IRSA tax bracket by ID
-func (r *ConfigRepo) GetIRSABracketByID(ctx context.Context, id uuid.UUID) (*IRSATaxBracket, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+		locked, err := configVersionLocked(tx, &config)
+		if err != nil {
+			return err
+		}
+		if locked {
+			return errConfigVersionLocked
+		}
 
-	var bracket IRSATaxBracket
-	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&bracket).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("IRSA bracket not found")
+		if err := tx.Delete(&config).Error; err != nil {
+			return fmt.Errorf("delete configuration: %w", err)
 		}
-		return nil, fmt.Errorf("get IRSA bracket: %w", err)
-	}
-	return &bracket, nil
+		return nil
+	})
 }
 
-// ListIRSABrackets retrieves IRSA tax brackets with filtering
-func (r *ConfigRepo) ListIRSABrackets(ctx context.Context, query IRSATaxBracketListQuery) ([]IRSATaxBracket, int64, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	var brackets []IRSATaxBracket
-	var total int64
-
-	db := r.db.WithContext(ctx).Model(&IRSATaxBracket{})
-
-	// Apply filters
-	if query.IsActive != nil {
-		db = db.Where("is_active = ?", *query.IsActive)
-	}
-
-	// Count total
-	if err := db.Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("count IRSA brackets: %w", err)
+// configVersionLocked reports whether a configuration version is closed or recorded on a payroll draft
+func configVersionLocked(db *gorm.DB, config *PayrollConfiguration) (bool, error) {
+	if config.EffectiveTo != nil {
+		return true, nil
 	}
 
-	// Apply pagination
-	limit := query.Limit
-	if limit == 0 {
-		limit = 50
+	var used int64
+	if err := db.Model(&PayrollRuleVersion{}).
+		Where("rule_type = ? AND rule_id = ?", "configuration", config.ID).Count(&used).Error; err != nil {
+		return false, fmt.Errorf("check configuration version use: %w", err)
 	}
-
-	if err := db.Limit(limit).Offset(query.Offset).Order("sort_order ASC").Find(&brackets).Error; err != nil {
-		return nil, 0, fmt.Errorf("list IRSA brackets: %w", err)
-	}
-
-	return brackets, total, nil
-}
-
-// DeleteIRSABracket soft deletes an IRSA tax bracket
-func (r *ConfigRepo) DeleteIRSABracket(ctx context.Context, id uuid.UUID) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&IRSATaxBracket{})
-	if result.Error != nil {
-		return fmt.Errorf("delete IRSA bracket: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("IRSA bracket not found")
-	}
-	return nil
+	return used > 0, nil
 }
@@ -18,15 +18,4 @@ func RegisterConfigRoutes(router *gin.RouterGroup, handler *ConfigHandler) {
 		configGroup.PUT("/:id", handler.UpdatePayrollConfiguration)
 		configGroup.DELETE("/:id", handler.DeletePayrollConfiguration)
 	}
-
-	// IRSA tax bracket routes (Admin only)
-	bracketGroup := router.Group("/irsa-brackets")
-	bracketGroup.Use(middleware.RequireRole("admin"))
-	{
-		bracketGroup.POST("", handler.CreateIRSABracket)
-		bracketGroup.GET("", handler.ListIRSABrackets)
-		bracketGroup.GET("/:id", handler.GetIRSABracketByID)
-		bracketGroup.PUT("/:id", handler.UpdateIRSABracket)
-		bracketGroup.DELETE("/:id", handler.DeleteIRSABracket)
-	}
 }
@@ -1,11 +1,12 @@
 package payroll
 
 import (
-	"encoding/json"
+	"math"
 	"net/http"
 	"time"
 
 	"go-server/internal/middleware"
+	"go-server/internal/notifications"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,12 +14,13 @@ import (
 
 // Handler handles payroll requests
 type Handler struct {
-	repo *Repo
+	repo              *Repo
+	notificationsRepo *notifications.Repo
 }
 
 // NewHandler creates a new payroll handler
-func NewHandler(repo *Repo) *Handler {
-	return &Handler{repo: repo}
+func NewHandler(repo *Repo, notificationsRepo *notifications.Repo) *Handler {
+	return &Handler{repo: repo, notificationsRepo: notificationsRepo}
 }
 
 // CreateDraft handles creation of a new payroll draft (HR only)
@@ -47,10 +49,19 @@ func (h *Handler) CreateDraft(c *gin.Context) {
 		PeriodStart: input.PeriodStart,
 		PeriodEnd:   input.PeriodEnd,
 		EmployeeID:  input.EmployeeID,
-		GrossSalary: input.GrossSalary,
+		BaseSalary:  input.GrossSalary,
 		CreatedBy:   userID,
 	}
 
+	for _, itemInput := range input.Items {
+		item, err := h.repo.BuildLineItem(c.Request.Context(), itemInput, draft, userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		draft.Items = append(draft.Items, *item)
+	}
+
 	if err := h.repo.CreateDraft(c.Request.Context(), draft); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -77,6 +88,36 @@ func (h *Handler) GetDraftByID(c *gin.Context) {
 	c.JSON(http.StatusOK, draft)
 }
 
+// GetDraftRuleVersions lists the configuration and IRSA bracket versions a draft was calculated with (HR/Accountant only)
+func (h *Handler) GetDraftRuleVersions(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "hr" && userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR or Accountant can view payroll rule versions"})
+		return
+	}
+
+	draft, err := h.repo.GetDraftByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll draft not found"})
+		return
+	}
+
+	versions, err := h.repo.GetDraftRuleVersions(c.Request.Context(), draft)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
 // UpdateDraft handles update of a payroll draft (HR only)
 func (h *Handler) UpdateDraft(c *gin.Context) {
 	idParam := c.Param("id")
@@ -105,9 +146,14 @@ func (h *Handler) UpdateDraft(c *gin.Context) {
 		return
 	}
 
+	if !isEditable(draft.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only draft or reopened payroll drafts can be modified"})
+		return
+	}
+
 	// Update fields if provided
 	if input.GrossSalary != nil {
-		draft.GrossSalary = *input.GrossSalary
+		draft.BaseSalary = *input.GrossSalary
 	}
 
 	if err := h.repo.UpdateDraft(c.Request.Context(), draft); err != nil {
@@ -115,6 +161,13 @@ func (h *Handler) UpdateDraft(c *gin.Context) {
 		return
 	}
 
+	if draft.RunID != nil {
+		if err := h.repo.refreshRunTotalsByID(c.Request.Context(), *draft.RunID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payroll run totals"})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, draft)
 }
 
@@ -138,11 +191,29 @@ func (h *Handler) DeleteDraft(c *gin.Context) {
 		return
 	}
 
+	draft, err := h.repo.GetDraftByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll draft not found"})
+		return
+	}
+
+	if !isEditable(draft.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only draft or reopened payroll drafts can be deleted"})
+		return
+	}
+
 	if err := h.repo.DeleteDraft(c.Request.Context(), id); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if draft.RunID != nil {
+		if err := h.repo.refreshRunTotalsByID(c.Request.Context(), *draft.RunID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payroll run totals"})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Payroll draft deleted successfully"})
 }
 
@@ -190,6 +261,11 @@ func (h *Handler) ApproveDraft(c *gin.Context) {
 		return
 	}
 
+	var input ApprovePayrollDraftRequest
+	if !bindOptionalJSON(c, &input) {
+		return
+	}
+
 	// Get the draft
 	draft, err := h.repo.GetDraftByID(c.Request.Context(), id)
 	if err != nil {
@@ -197,44 +273,36 @@ func (h *Handler) ApproveDraft(c *gin.Context) {
 		return
 	}
 
-	// Check if already approved by querying the approved table
-	// This is a simplified check - in production you'd query the database properly
-
-	// Generate OHADA-compliant GL entries
-	glEntries := generateGLEntries(draft)
-	glEntriesJSON, err := json.Marshal(glEntries)
+	// Approval locks the draft, so a draft can only ever be approved once
+	approved, err := h.repo.ApproveDraft(c.Request.Context(), id, accountantID, input.Comment)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate GL entries"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Generate digital signature
-	digitalSignature := generateDigitalSignature(accountantID, id)
-
-	// Create approved record
-	approved := &PayrollApproved{
-		DraftID:          id,
-		AccountantID:     accountantID,
-		GLEntries:        string(glEntriesJSON),
-		DigitalSignature: digitalSignature,
+	if draft.RunID != nil {
+		if err := h.repo.refreshRunTotalsByID(c.Request.Context(), *draft.RunID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payroll run totals"})
+			return
+		}
 	}
 
-	if err := h.repo.CreateApproved(c.Request.Context(), approved); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to approve payroll draft"})
-		return
-	}
+	h.notifyStatusChange(c.Request.Context(), draft.CreatedBy, "Payroll draft", StatusApproved, input.Comment, "/payroll/drafts/"+draft.ID.String())
 
 	c.JSON(http.StatusCreated, approved)
 }
 
 // generateGLEntries generates OHADA-compliant general ledger entries
 func generateGLEntries(draft *PayrollDraft) []GLEntry {
+	// Non-taxable earnings are booked separately below, so the salary expense covers the rest of the net
+	netFromSalary := draft.NetSalary - draft.NonTaxableEarnings
+
 	entries := []GLEntry{
 		// Debit 641 (Salaires) | Credit 421 (Salaires à payer) - Net salary
 		{
 			AccountCode: "641",
 			AccountName: "Salaires et traitements",
-			Debit:       draft.NetSalary,
+			Debit:       netFromSalary,
 			Credit:      0,
 			Description: "Salaire net à payer",
 		},
@@ -242,7 +310,7 @@ func generateGLEntries(draft *PayrollDraft) []GLEntry {
 			AccountCode: "421",
 			AccountName: "Salaires à payer",
 			Debit:       0,
-			Credit:      draft.NetSalary,
+			Credit:      netFromSalary,
 			Description: "Salaire net à payer",
 		},
 		// Debit 641 (Salaires) | Credit 431 (CNAPS à payer) - Employee CNAPS portion
@@ -322,15 +390,30 @@ func generateGLEntries(draft *PayrollDraft) []GLEntry {
 		},
 	}
 
-	return entries
-}
+	for _, item := range draft.Items {
+		account, ok := lineItemAccounts[item.Type]
+		if !ok {
+			// Taxable earnings and absences are already part of gross salary
+			continue
+		}
+
+		if item.Type == LineItemNonTaxableEarning {
+			// Debit 648 (Autres charges de personnel) | Credit 421 (Salaires à payer) - Non-taxable earning
+			entries = append(entries,
+				GLEntry{AccountCode: account.Code, AccountName: account.Name, Debit: item.Amount, Description: item.Label},
+				GLEntry{AccountCode: "421", AccountName: "Salaires à payer", Credit: item.Amount, Description: item.Label},
+			)
+			continue
+		}
+
+		// Debit 641 (Salaires) | Credit 425/274/427 - Advance, loan repayment or other deduction withheld from pay
+		entries = append(entries,
+			GLEntry{AccountCode: "641", AccountName: "Salaires et traitements", Debit: item.Amount, Description: item.Label},
+			GLEntry{AccountCode: account.Code, AccountName: account.Name, Credit: item.Amount, Description: item.Label},
+		)
+	}
 
-// generateDigitalSignature generates a digital signature for the approved payroll
-func generateDigitalSignature(accountantID, draftID uuid.UUID) string {
-	// In production, this would use proper cryptographic signing
-	// For now, we'll create a simple signature string
-	timestamp := time.Now().Unix()
-	return "SIG-" + accountantID.String()[:8] + "-" + draftID.String()[:8] + "-" + string(rune(timestamp))
+	return entries
 }
 
 // GetApprovedByID retrieves an approved payroll by ID
@@ -430,49 +513,176 @@ func (h *Handler) GetReconciliationReport(c *gin.Context) {
 
 // GenerateFichePaie generates a fiche de paie (payslip) for an approved payroll
 func (h *Handler) GenerateFichePaie(c *gin.Context) {
+	fichePaie, ok := h.loadApprovedFichePaie(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, fichePaie)
+}
+
+// GenerateFichePaiePDF renders the official fiche de paie of an approved payroll as a PDF
+func (h *Handler) GenerateFichePaiePDF(c *gin.Context) {
+	fichePaie, ok := h.loadApprovedFichePaie(c)
+	if !ok {
+		return
+	}
+
+	content, err := renderFichePaiePDF(fichePaie, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render fiche de paie"})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+fichePaie.FichePaieNumber+".pdf")
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// PreviewDraftPDF renders an unapproved payroll draft as a watermarked payslip preview (HR/Accountant only)
+func (h *Handler) PreviewDraftPDF(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid approved payroll ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "hr" && userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR or Accountant can preview payroll drafts"})
+		return
+	}
+
+	draft, err := h.repo.GetDraftByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll draft not found"})
+		return
+	}
+
+	fichePaie, err := h.repo.BuildFichePaie(c.Request.Context(), draft, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	content, err := renderFichePaiePDF(fichePaie, DraftWatermark)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render payroll draft preview"})
 		return
 	}
 
+	c.Header("Content-Disposition", "inline; filename=draft-"+draft.ID.String()+".pdf")
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// loadApprovedFichePaie resolves the approved payroll in the URL into a payslip the caller may view.
+// Employees may only view their own payslips.
+func (h *Handler) loadApprovedFichePaie(c *gin.Context) (*FichePaie, bool) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid approved payroll ID"})
+		return nil, false
+	}
+
 	// Get approved payroll
 	approved, err := h.repo.GetApprovedByID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Approved payroll not found"})
-		return
+		return nil, false
 	}
 
 	// Get the draft
 	draft, err := h.repo.GetDraftByID(c.Request.Context(), approved.DraftID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll draft not found"})
+		return nil, false
+	}
+
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole == "employee" {
+		userID, err := middleware.GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return nil, false
+		}
+		employeeID, err := h.repo.GetUserEmployeeID(c.Request.Context(), userID)
+		if err != nil || employeeID == nil || *employeeID != draft.EmployeeID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own payslips"})
+			return nil, false
+		}
+	}
+
+	fichePaie, err := h.repo.BuildFichePaie(c.Request.Context(), draft, approved)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return fichePaie, true
+}
+
+// VerifyFichePaie checks whether a presented fiche de paie is authentic and unmodified (public)
+func (h *Handler) VerifyFichePaie(c *gin.Context) {
+	fichePaieNumber := c.Param("fiche_paie_number")
+
+	var query PayslipVerificationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// TODO: Get employee details from employee service
-	// For now, we'll use placeholder values
-	fichePaie := &FichePaie{
-		FichePaieNumber:    approved.FichePaieNumber,
-		EmployeeID:         draft.EmployeeID,
-		EmployeeName:       "Employee Name", // TODO: Get from employee service
-		EmployeePosition:   "Position",      // TODO: Get from employee service
-		EmployeeDepartment: "Department",    // TODO: Get from employee service
-		PeriodStart:        draft.PeriodStart,
-		PeriodEnd:          draft.PeriodEnd,
-		GrossSalary:        draft.GrossSalary,
-		CNAPSEmployee:      draft.CNAPSEmployee,
-		CNAPSEmployer:      draft.CNAPSEmployer,
-		OSTIEEmployee:      draft.OSTIEEmployee,
-		OSTIEEmployer:      draft.OSTIEEmployer,
-		IRSA:               draft.IRSA,
-		IRSABracket:        draft.IRSABracket,
-		NetSalary:          draft.NetSalary,
-		AccountantName:     "Accountant Name", // TODO: Get from user service
-		ApprovedAt:         approved.ApprovedAt,
-		DigitalSignature:   approved.DigitalSignature,
+	approved, err := h.repo.GetApprovedByFichePaieNumber(c.Request.Context(), fichePaieNumber)
+	if err != nil {
+		c.JSON(http.StatusNotFound, PayslipVerification{
+			FichePaieNumber: fichePaieNumber,
+			Valid:           false,
+			Reasons:         []string{"fiche de paie not found"},
+		})
+		return
 	}
 
-	c.JSON(http.StatusOK, fichePaie)
+	draft, err := h.repo.GetDraftByID(c.Request.Context(), approved.DraftID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, PayslipVerification{
+			FichePaieNumber: fichePaieNumber,
+			Valid:           false,
+			Reasons:         []string{"payroll record for this fiche de paie no longer exists"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, verifyPresentedPayslip(approved, draft, query))
+}
+
+// verifyPresentedPayslip checks the stored signature and compares any presented figures with the signed record
+func verifyPresentedPayslip(approved *PayrollApproved, draft *PayrollDraft, query PayslipVerificationQuery) PayslipVerification {
+	result := PayslipVerification{
+		FichePaieNumber: approved.FichePaieNumber,
+		PeriodStart:     &draft.PeriodStart,
+		PeriodEnd:       &draft.PeriodEnd,
+		ApprovedAt:      &approved.ApprovedAt,
+		SignatureKeyID:  approved.SignatureKeyID,
+	}
+
+	if ok, reason := verifyPayslipSignature(approved, draft); !ok {
+		result.Reasons = append(result.Reasons, reason)
+	}
+	if query.Signature != "" && query.Signature != approved.DigitalSignature {
+		result.Reasons = append(result.Reasons, "presented signature does not match")
+	}
+	if query.GrossSalary != nil && math.Abs(*query.GrossSalary-draft.GrossSalary) >= 0.01 {
+		result.Reasons = append(result.Reasons, "presented gross salary does not match")
+	}
+	if query.NetSalary != nil && math.Abs(*query.NetSalary-draft.NetSalary) >= 0.01 {
+		result.Reasons = append(result.Reasons, "presented net salary does not match")
+	}
+	if query.PeriodStart != "" && query.PeriodStart != draft.PeriodStart.Format("2006-01-02") {
+		result.Reasons = append(result.Reasons, "presented period start does not match")
+	}
+	if query.PeriodEnd != "" && query.PeriodEnd != draft.PeriodEnd.Format("2006-01-02") {
+		result.Reasons = append(result.Reasons, "presented period end does not match")
+	}
+
+	result.Valid = len(result.Reasons) == 0
+	return result
 }
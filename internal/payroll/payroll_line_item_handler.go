@@ -0,0 +1,123 @@
+package payroll
+
+import (
+	"errors"
+	"net/http"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListLineItems retrieves the line items of a payroll draft
+func (h *Handler) ListLineItems(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+
+	if _, err := h.repo.GetDraftByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll draft not found"})
+		return
+	}
+
+	items, err := h.repo.ListLineItems(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list payroll line items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// AddLineItem handles adding an allowance, bonus or deduction to a payroll draft (HR only)
+func (h *Handler) AddLineItem(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "hr" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can modify payroll line items"})
+		return
+	}
+
+	var input PayrollLineItemRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	draft, err := h.repo.GetDraftByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll draft not found"})
+		return
+	}
+
+	item, err := h.repo.BuildLineItem(c.Request.Context(), input, draft, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.repo.AddLineItem(c.Request.Context(), id, item)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, updated)
+}
+
+// DeleteLineItem handles removal of a manual line item from a payroll draft (HR only)
+func (h *Handler) DeleteLineItem(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid line item ID"})
+		return
+	}
+
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "hr" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can modify payroll line items"})
+		return
+	}
+
+	if _, err := h.repo.GetDraftByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll draft not found"})
+		return
+	}
+
+	updated, err := h.repo.DeleteLineItem(c.Request.Context(), id, itemID)
+	if err != nil {
+		if errors.Is(err, errSystemLineItem) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
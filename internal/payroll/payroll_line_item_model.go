@@ -0,0 +1,95 @@
+package payroll
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payroll line item types
+const (
+	// LineItemTaxableEarning is added to gross salary and subject to CNAPS, OSTIE and IRSA
+	LineItemTaxableEarning = "taxable_earning"
+	// LineItemNonTaxableEarning is paid on top of net salary, outside every contribution and tax base
+	LineItemNonTaxableEarning = "non_taxable_earning"
+	// LineItemAdvance recovers a salary advance already paid to the employee
+	LineItemAdvance = "advance"
+	// LineItemLoanRepayment recovers an instalment of a loan granted to the employee
+	LineItemLoanRepayment = "loan_repayment"
+	// LineItemDeduction is any other deduction from net salary
+	LineItemDeduction = "deduction"
+	// LineItemAbsence withholds pay for unpaid leave and unjustified absences, reducing gross salary
+	LineItemAbsence = "absence"
+)
+
+// Payroll line item sources
+const (
+	// LineItemSourceManual items are entered by HR and kept when a draft is recalculated
+	LineItemSourceManual = "manual"
+	// LineItemSourceAttendance items are derived from approved overtime and regenerated on recalculation
+	LineItemSourceAttendance = "attendance"
+	// LineItemSourceLeave items are derived from approved unpaid leave and regenerated on recalculation
+	LineItemSourceLeave = "leave"
+	// LineItemSourceEmployee items prorate pay for hires and exits within the period and are regenerated on recalculation
+	LineItemSourceEmployee = "employee"
+)
+
+// PayrollLineItem represents a variable element of a payroll draft (allowance, bonus, advance, deduction)
+type PayrollLineItem struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	DraftID   uuid.UUID `gorm:"type:uuid;not null;index" json:"draft_id"`
+	Type      string    `gorm:"type:varchar(30);not null" json:"type"`
+	Code      string    `gorm:"type:varchar(50);not null" json:"code"`
+	Label     string    `gorm:"type:varchar(255);not null" json:"label"`
+	Amount    float64   `gorm:"type:numeric(15,2);not null" json:"amount"`
+	Source    string    `gorm:"type:varchar(20);not null;default:'manual'" json:"source"`
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
+}
+
+// PayrollLineItemRequest represents a line item to add to a payroll draft.
+// Type, label and amount may be omitted for the standard codes, which are read from payroll configuration.
+type PayrollLineItemRequest struct {
+	Type   string   `json:"type" binding:"omitempty,oneof=taxable_earning non_taxable_earning advance loan_repayment deduction"`
+	Code   string   `json:"code" binding:"required,max=50"`
+	Label  string   `json:"label" binding:"omitempty,max=255"`
+	Amount *float64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// standardLineItem describes a line item backed by a payroll configuration key
+type standardLineItem struct {
+	Type      string
+	Label     string
+	ConfigKey string
+	// RateOfBase means the configured value is a rate applied to the base salary rather than an amount
+	RateOfBase bool
+}
+
+// standardLineItems maps the allowance codes seeded in payroll configuration to their tax treatment
+var standardLineItems = map[string]standardLineItem{
+	"transport_allowance": {Type: LineItemNonTaxableEarning, Label: "Indemnité de transport", ConfigKey: "transport_allowance"},
+	"medical_allowance":   {Type: LineItemNonTaxableEarning, Label: "Indemnité médicale", ConfigKey: "medical_allowance"},
+	"housing_allowance":   {Type: LineItemTaxableEarning, Label: "Indemnité de logement", ConfigKey: "housing_allowance"},
+	"family_allowance":    {Type: LineItemNonTaxableEarning, Label: "Allocation familiale", ConfigKey: "family_allowance_rate", RateOfBase: true},
+}
+
+// glAccount identifies a general ledger account
+type glAccount struct {
+	Code string
+	Name string
+}
+
+// lineItemAccounts maps line item types to the account debited for non-taxable earnings
+// and credited for amounts withheld from pay. Taxable earnings and absences are part of gross salary (641).
+var lineItemAccounts = map[string]glAccount{
+	LineItemNonTaxableEarning: {Code: "648", Name: "Autres charges de personnel"},
+	LineItemAdvance:           {Code: "425", Name: "Personnel - avances et acomptes"},
+	LineItemLoanRepayment:     {Code: "274", Name: "Prêts au personnel"},
+	LineItemDeduction:         {Code: "427", Name: "Personnel - oppositions et retenues"},
+}
+
+// TableName specifies the table name for PayrollLineItem model
+func (PayrollLineItem) TableName() string {
+	return "payroll_line_items"
+}
@@ -0,0 +1,208 @@
+package payroll
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// orderLineItems keeps line items in a stable order for payslips, GL entries and signatures
+func orderLineItems(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC, id ASC")
+}
+
+// BuildLineItem resolves a line item request for a draft, filling type, label and amount for the standard codes
+// from the configuration in force at the end of the draft's period
+func (r *Repo) BuildLineItem(ctx context.Context, input PayrollLineItemRequest, draft *PayrollDraft, createdBy uuid.UUID) (*PayrollLineItem, error) {
+	standard, isStandard := standardLineItems[input.Code]
+
+	item := &PayrollLineItem{
+		Type:      input.Type,
+		Code:      input.Code,
+		Label:     input.Label,
+		Source:    LineItemSourceManual,
+		CreatedBy: createdBy,
+	}
+
+	if item.Type == "" {
+		if !isStandard {
+			return nil, fmt.Errorf("type is required for line item %s", input.Code)
+		}
+		item.Type = standard.Type
+	}
+	if item.Label == "" {
+		item.Label = input.Code
+		if isStandard {
+			item.Label = standard.Label
+		}
+	}
+
+	switch {
+	case input.Amount != nil:
+		item.Amount = *input.Amount
+	case isStandard:
+		value, err := r.rulesAt(draft.PeriodEnd).Float(ctx, standard.ConfigKey)
+		if err != nil {
+			return nil, fmt.Errorf("get %s: %w", standard.ConfigKey, err)
+		}
+		item.Amount = value
+		if standard.RateOfBase {
+			item.Amount = draft.BaseSalary * value
+		}
+	default:
+		return nil, fmt.Errorf("amount is required for line item %s", input.Code)
+	}
+
+	item.Amount = math.Round(item.Amount*100) / 100
+	if item.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive for line item %s", input.Code)
+	}
+	return item, nil
+}
+
+// ListLineItems retrieves the line items of a payroll draft
+func (r *Repo) ListLineItems(ctx context.Context, draftID uuid.UUID) ([]PayrollLineItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var items []PayrollLineItem
+	if err := orderLineItems(r.db.WithContext(ctx)).Where("draft_id = ?", draftID).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("list payroll line items: %w", err)
+	}
+	return items, nil
+}
+
+// AddLineItem adds a line item to an editable draft and recalculates it
+func (r *Repo) AddLineItem(ctx context.Context, draftID uuid.UUID, item *PayrollLineItem) (*PayrollDraft, error) {
+	return r.changeLineItems(ctx, draftID, func(tx *gorm.DB) error {
+		item.DraftID = draftID
+		if err := tx.Create(item).Error; err != nil {
+			return fmt.Errorf("create payroll line item: %w", err)
+		}
+		return nil
+	})
+}
+
+// errSystemLineItem is returned when deleting a line item derived from attendance, leave or employment dates
+var errSystemLineItem = errors.New("only manual line items can be deleted: the others follow attendance, leave and employment records")
+
+// DeleteLineItem removes a manual line item from an editable draft and recalculates it
+func (r *Repo) DeleteLineItem(ctx context.Context, draftID, itemID uuid.UUID) (*PayrollDraft, error) {
+	return r.changeLineItems(ctx, draftID, func(tx *gorm.DB) error {
+		var item PayrollLineItem
+		if err := tx.Where("id = ? AND draft_id = ?", itemID, draftID).First(&item).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("payroll line item not found")
+			}
+			return fmt.Errorf("get payroll line item: %w", err)
+		}
+		if item.Source != LineItemSourceManual {
+			return errSystemLineItem
+		}
+
+		if err := tx.Delete(&item).Error; err != nil {
+			return fmt.Errorf("delete payroll line item: %w", err)
+		}
+		return nil
+	})
+}
+
+// systemLineItems computes the line items derived from other modules for a draft's period
+func (r *Repo) systemLineItems(ctx context.Context, draft *PayrollDraft) ([]PayrollLineItem, error) {
+	overtime, err := r.overtimeLineItems(ctx, draft)
+	if err != nil {
+		return nil, err
+	}
+
+	emp, err := r.getDraftEmployee(ctx, draft.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := employmentWindow(draft, emp)
+	if err != nil {
+		return nil, err
+	}
+
+	absences, err := r.absenceLineItems(ctx, draft, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	proration, err := r.prorationLineItems(ctx, draft, emp)
+	if err != nil {
+		return nil, err
+	}
+
+	finalPay, err := r.leaveCompensationLineItems(ctx, draft, emp)
+	if err != nil {
+		return nil, err
+	}
+
+	items := append(overtime, absences...)
+	items = append(items, proration...)
+	return append(items, finalPay...), nil
+}
+
+// recordLineItemRules reads into a draft's rule set the configuration its line items were computed from: the
+// standard working hours behind overtime pay and the configured amount or rate of each standard allowance
+func recordLineItemRules(ctx context.Context, rules *ruleSet, items []PayrollLineItem) error {
+	for _, item := range items {
+		var key string
+		switch item.Source {
+		case LineItemSourceAttendance:
+			key = "standard_working_hours"
+		case LineItemSourceManual:
+			key = standardLineItems[item.Code].ConfigKey
+		}
+		if key == "" {
+			continue
+		}
+		if _, err := rules.Float(ctx, key); err != nil {
+			return fmt.Errorf("get %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// changeLineItems applies a change to a draft's line items and recalculates the draft in one transaction
+func (r *Repo) changeLineItems(ctx context.Context, draftID uuid.UUID, change func(tx *gorm.DB) error) (*PayrollDraft, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var draft PayrollDraft
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDraft(tx, draftID, &draft); err != nil {
+			return err
+		}
+		if !isEditable(draft.Status) {
+			return fmt.Errorf("only draft or reopened payroll drafts can be modified")
+		}
+
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		if err := orderLineItems(tx).Where("draft_id = ?", draftID).Find(&draft.Items).Error; err != nil {
+			return fmt.Errorf("list payroll line items: %w", err)
+		}
+		if err := r.calculateDraft(ctx, &draft); err != nil {
+			return err
+		}
+		return saveCalculatedDraft(tx, &draft)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if draft.RunID != nil {
+		if err := r.refreshRunTotalsByID(ctx, *draft.RunID); err != nil {
+			return nil, err
+		}
+	}
+	return &draft, nil
+}
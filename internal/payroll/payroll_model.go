@@ -3,51 +3,117 @@ package payroll
 import (
 	"time"
 
+	"go-server/internal/taxrules"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // PayrollDraft represents a payroll draft created by HR
 type PayrollDraft struct {
-	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	PeriodStart   time.Time      `gorm:"type:date;not null" json:"period_start"`
-	PeriodEnd     time.Time      `gorm:"type:date;not null" json:"period_end"`
-	EmployeeID    uuid.UUID      `gorm:"type:uuid;not null" json:"employee_id"`
-	GrossSalary   float64        `gorm:"type:numeric(15,2);not null" json:"gross_salary"`
-	CNAPSEmployee float64        `gorm:"type:numeric(15,2);not null" json:"cnaps_employee"`
-	CNAPSEmployer float64        `gorm:"type:numeric(15,2);not null" json:"cnaps_employer"`
-	OSTIEEmployee float64        `gorm:"type:numeric(15,2);not null" json:"ostie_employee"`
-	OSTIEEmployer float64        `gorm:"type:numeric(15,2);not null" json:"ostie_employer"`
-	IRSA          float64        `gorm:"type:numeric(15,2);not null" json:"irsa"`
-	NetSalary     float64        `gorm:"type:numeric(15,2);not null" json:"net_salary"`
-	CNAPSBase     float64        `gorm:"type:numeric(15,2);not null" json:"cnaps_base"`
-	OSTIEBase     float64        `gorm:"type:numeric(15,2);not null" json:"ostie_base"`
-	IRSABracket   string         `gorm:"type:varchar(50)" json:"irsa_bracket"`
-	CreatedBy     uuid.UUID      `gorm:"type:uuid;not null" json:"created_by"`
-	CreatedAt     time.Time      `gorm:"default:now()" json:"created_at"`
-	UpdatedAt     time.Time      `gorm:"default:now()" json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 uuid.UUID               `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	RunID              *uuid.UUID              `gorm:"type:uuid;index" json:"run_id,omitempty"`
+	PeriodStart        time.Time               `gorm:"type:date;not null" json:"period_start"`
+	PeriodEnd          time.Time               `gorm:"type:date;not null" json:"period_end"`
+	EmployeeID         uuid.UUID               `gorm:"type:uuid;not null" json:"employee_id"`
+	BaseSalary         float64                 `gorm:"type:numeric(15,2);not null" json:"base_salary"`
+	GrossSalary        float64                 `gorm:"type:numeric(15,2);not null" json:"gross_salary"`
+	TaxableEarnings    float64                 `gorm:"type:numeric(15,2);not null;default:0" json:"taxable_earnings"`
+	NonTaxableEarnings float64                 `gorm:"type:numeric(15,2);not null;default:0" json:"non_taxable_earnings"`
+	TotalDeductions    float64                 `gorm:"type:numeric(15,2);not null;default:0" json:"total_deductions"`
+	AbsenceDeductions  float64                 `gorm:"type:numeric(15,2);not null;default:0" json:"absence_deductions"`
+	CNAPSEmployee      float64                 `gorm:"type:numeric(15,2);not null" json:"cnaps_employee"`
+	CNAPSEmployer      float64                 `gorm:"type:numeric(15,2);not null" json:"cnaps_employer"`
+	OSTIEEmployee      float64                 `gorm:"type:numeric(15,2);not null" json:"ostie_employee"`
+	OSTIEEmployer      float64                 `gorm:"type:numeric(15,2);not null" json:"ostie_employer"`
+	IRSA               float64                 `gorm:"type:numeric(15,2);not null" json:"irsa"`
+	NetSalary          float64                 `gorm:"type:numeric(15,2);not null" json:"net_salary"`
+	CNAPSBase          float64                 `gorm:"type:numeric(15,2);not null" json:"cnaps_base"`
+	OSTIEBase          float64                 `gorm:"type:numeric(15,2);not null" json:"ostie_base"`
+	IRSABracket        string                  `gorm:"type:varchar(50)" json:"irsa_bracket"`
+	IRSABreakdown      *taxrules.IRSABreakdown `gorm:"type:jsonb" json:"irsa_breakdown,omitempty"`
+	Status             string                  `gorm:"type:varchar(20);not null;default:'draft'" json:"status"`
+	SubmittedAt        *time.Time              `json:"submitted_at,omitempty"`
+	ReviewedBy         *uuid.UUID              `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt         *time.Time              `json:"reviewed_at,omitempty"`
+	RejectionReason    string                  `gorm:"type:text" json:"rejection_reason,omitempty"`
+	Items              []PayrollLineItem       `gorm:"foreignKey:DraftID" json:"items,omitempty"`
+	RuleVersions       []PayrollRuleVersion    `gorm:"foreignKey:DraftID" json:"-"`
+	CreatedBy          uuid.UUID               `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt          time.Time               `gorm:"default:now()" json:"created_at"`
+	UpdatedAt          time.Time               `gorm:"default:now()" json:"updated_at"`
+	DeletedAt          gorm.DeletedAt          `gorm:"index" json:"-"`
+}
+
+// Payroll draft and run workflow statuses
+const (
+	StatusDraft     = "draft"
+	StatusSubmitted = "submitted"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusReopened  = "reopened"
+)
+
+// statusTransitions lists the allowed workflow moves for drafts and runs
+var statusTransitions = map[string][]string{
+	StatusDraft:     {StatusSubmitted},
+	StatusReopened:  {StatusSubmitted},
+	StatusSubmitted: {StatusApproved, StatusRejected},
+	StatusRejected:  {StatusReopened},
+}
+
+// canTransition reports whether a draft or run may move from one status to another
+func canTransition(from, to string) bool {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isEditable reports whether HR may still modify a draft in this status
+func isEditable(status string) bool {
+	return status == StatusDraft || status == StatusReopened
+}
+
+// PayrollStatusChange records a workflow transition and the comment left with it
+type PayrollStatusChange struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	DraftID    *uuid.UUID `gorm:"type:uuid;index" json:"draft_id,omitempty"`
+	RunID      *uuid.UUID `gorm:"type:uuid;index" json:"run_id,omitempty"`
+	FromStatus string     `gorm:"type:varchar(20);not null" json:"from_status"`
+	ToStatus   string     `gorm:"type:varchar(20);not null" json:"to_status"`
+	Comment    string     `gorm:"type:text" json:"comment,omitempty"`
+	ChangedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"changed_by"`
+	CreatedAt  time.Time  `gorm:"default:now()" json:"created_at"`
 }
 
 // PayrollApproved represents an approved payroll record by Accountant
 type PayrollApproved struct {
-	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	DraftID          uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"draft_id"`
-	FichePaieNumber  string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"fiche_paie_number"`
-	AccountantID     uuid.UUID `gorm:"type:uuid;not null" json:"accountant_id"`
-	GLEntries        string    `gorm:"type:jsonb;not null" json:"gl_entries"`
-	ApprovedAt       time.Time `gorm:"default:now()" json:"approved_at"`
-	DigitalSignature string    `gorm:"type:text;not null" json:"digital_signature"`
-	CreatedAt        time.Time `gorm:"default:now()" json:"created_at"`
-	UpdatedAt        time.Time `gorm:"default:now()" json:"updated_at"`
-}
-
-// CreatePayrollDraftRequest represents request to create a payroll draft
+	ID                 uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	DraftID            uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"draft_id"`
+	FichePaieNumber    string     `gorm:"type:varchar(50);uniqueIndex;not null" json:"fiche_paie_number"`
+	AccountantID       uuid.UUID  `gorm:"type:uuid;not null" json:"accountant_id"`
+	GLEntries          string     `gorm:"type:jsonb;not null" json:"gl_entries"`
+	ApprovedAt         time.Time  `gorm:"default:now()" json:"approved_at"`
+	DigitalSignature   string     `gorm:"type:text;not null" json:"digital_signature"`
+	SignatureAlgorithm string     `gorm:"type:varchar(20);not null" json:"signature_algorithm"`
+	SignatureKeyID     string     `gorm:"type:varchar(32)" json:"signature_key_id"`
+	PaidAt             *time.Time `gorm:"type:date" json:"paid_at,omitempty"`
+	PaymentBatchID     *uuid.UUID `gorm:"type:uuid" json:"payment_batch_id,omitempty"`
+	CreatedAt          time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt          time.Time  `gorm:"default:now()" json:"updated_at"`
+}
+
+// CreatePayrollDraftRequest represents request to create a payroll draft.
+// GrossSalary is the base salary; taxable line items are added to it to form the gross.
 type CreatePayrollDraftRequest struct {
-	PeriodStart time.Time `json:"period_start" binding:"required"`
-	PeriodEnd   time.Time `json:"period_end" binding:"required"`
-	EmployeeID  uuid.UUID `json:"employee_id" binding:"required"`
-	GrossSalary float64   `json:"gross_salary" binding:"required,min=200000"`
+	PeriodStart time.Time                `json:"period_start" binding:"required"`
+	PeriodEnd   time.Time                `json:"period_end" binding:"required"`
+	EmployeeID  uuid.UUID                `json:"employee_id" binding:"required"`
+	GrossSalary float64                  `json:"gross_salary" binding:"required,min=200000"`
+	Items       []PayrollLineItemRequest `json:"items" binding:"omitempty,dive"`
 }
 
 // UpdatePayrollDraftRequest represents request to update a payroll draft
@@ -60,12 +126,28 @@ type ApprovePayrollDraftRequest struct {
 	Comment string `json:"comment"`
 }
 
+// SubmitPayrollRequest represents request to submit a draft or run for approval
+type SubmitPayrollRequest struct {
+	Comment string `json:"comment"`
+}
+
+// RejectPayrollRequest represents request to reject a draft or run
+type RejectPayrollRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ReopenPayrollRequest represents request to reopen a rejected draft or run
+type ReopenPayrollRequest struct {
+	Comment string `json:"comment"`
+}
+
 // PayrollDraftListQuery represents query parameters for listing payroll drafts
 type PayrollDraftListQuery struct {
 	PeriodStart *time.Time `form:"period_start"`
 	PeriodEnd   *time.Time `form:"period_end"`
 	EmployeeID  *uuid.UUID `form:"employee_id"`
-	Status      string     `form:"status" binding:"omitempty,oneof=draft approved"`
+	RunID       *uuid.UUID `form:"run_id"`
+	Status      string     `form:"status" binding:"omitempty,oneof=draft submitted approved rejected reopened"`
 	Limit       int        `form:"limit" binding:"omitempty,min=1,max=100"`
 	Offset      int        `form:"offset" binding:"omitempty,min=0"`
 }
@@ -83,24 +165,64 @@ type PayrollApprovedListQuery struct {
 
 // FichePaie represents a payslip (fiche de paie)
 type FichePaie struct {
-	FichePaieNumber    string    `json:"fiche_paie_number"`
-	EmployeeID         uuid.UUID `json:"employee_id"`
-	EmployeeName       string    `json:"employee_name"`
-	EmployeePosition   string    `json:"employee_position"`
-	EmployeeDepartment string    `json:"employee_department"`
-	PeriodStart        time.Time `json:"period_start"`
-	PeriodEnd          time.Time `json:"period_end"`
-	GrossSalary        float64   `json:"gross_salary"`
-	CNAPSEmployee      float64   `json:"cnaps_employee"`
-	CNAPSEmployer      float64   `json:"cnaps_employer"`
-	OSTIEEmployee      float64   `json:"ostie_employee"`
-	OSTIEEmployer      float64   `json:"ostie_employer"`
-	IRSA               float64   `json:"irsa"`
-	IRSABracket        string    `json:"irsa_bracket"`
-	NetSalary          float64   `json:"net_salary"`
-	AccountantName     string    `json:"accountant_name"`
-	ApprovedAt         time.Time `json:"approved_at"`
-	DigitalSignature   string    `json:"digital_signature"`
+	FichePaieNumber    string                  `json:"fiche_paie_number,omitempty"`
+	Status             string                  `json:"status"`
+	CompanyName        string                  `json:"company_name"`
+	CompanyAddress     string                  `json:"company_address,omitempty"`
+	CompanyNIF         string                  `json:"company_nif,omitempty"`
+	CompanySTAT        string                  `json:"company_stat,omitempty"`
+	CompanyCNAPSNumber string                  `json:"company_cnaps_number,omitempty"`
+	CompanyOSTIENumber string                  `json:"company_ostie_number,omitempty"`
+	Currency           string                  `json:"currency"`
+	EmployeeID         uuid.UUID               `json:"employee_id"`
+	EmployeeName       string                  `json:"employee_name"`
+	EmployeePosition   string                  `json:"employee_position"`
+	EmployeeDepartment string                  `json:"employee_department"`
+	EmployeeNationalID string                  `json:"employee_national_id,omitempty"`
+	EmployeeHireDate   time.Time               `json:"employee_hire_date"`
+	PeriodStart        time.Time               `json:"period_start"`
+	PeriodEnd          time.Time               `json:"period_end"`
+	BaseSalary         float64                 `json:"base_salary"`
+	Items              []PayrollLineItem       `json:"items"`
+	GrossSalary        float64                 `json:"gross_salary"`
+	NonTaxableEarnings float64                 `json:"non_taxable_earnings"`
+	TotalDeductions    float64                 `json:"total_deductions"`
+	CNAPSBase          float64                 `json:"cnaps_base"`
+	CNAPSEmployee      float64                 `json:"cnaps_employee"`
+	CNAPSEmployer      float64                 `json:"cnaps_employer"`
+	OSTIEBase          float64                 `json:"ostie_base"`
+	OSTIEEmployee      float64                 `json:"ostie_employee"`
+	OSTIEEmployer      float64                 `json:"ostie_employer"`
+	IRSA               float64                 `json:"irsa"`
+	IRSABracket        string                  `json:"irsa_bracket"`
+	IRSABreakdown      *taxrules.IRSABreakdown `json:"irsa_breakdown,omitempty"`
+	NetSalary          float64                 `json:"net_salary"`
+	AccountantName     string                  `json:"accountant_name,omitempty"`
+	ApprovedAt         *time.Time              `json:"approved_at,omitempty"`
+	DigitalSignature   string                  `json:"digital_signature,omitempty"`
+	SignatureKeyID     string                  `json:"signature_key_id,omitempty"`
+	VerificationURL    string                  `json:"verification_url,omitempty"`
+}
+
+// PayslipVerificationQuery holds the figures printed on a presented fiche de paie.
+// Every field is optional; any that are given are compared with the signed record.
+type PayslipVerificationQuery struct {
+	Signature   string   `form:"signature"`
+	GrossSalary *float64 `form:"gross_salary"`
+	NetSalary   *float64 `form:"net_salary"`
+	PeriodStart string   `form:"period_start"`
+	PeriodEnd   string   `form:"period_end"`
+}
+
+// PayslipVerification is the public answer to a fiche de paie authenticity check
+type PayslipVerification struct {
+	FichePaieNumber string     `json:"fiche_paie_number"`
+	Valid           bool       `json:"valid"`
+	Reasons         []string   `json:"reasons,omitempty"`
+	PeriodStart     *time.Time `json:"period_start,omitempty"`
+	PeriodEnd       *time.Time `json:"period_end,omitempty"`
+	ApprovedAt      *time.Time `json:"approved_at,omitempty"`
+	SignatureKeyID  string     `json:"signature_key_id,omitempty"`
 }
 
 // GLEntry represents a general ledger entry (OHADA compliant)
@@ -116,6 +238,7 @@ type GLEntry struct {
 type ReconciliationReport struct {
 	PeriodStart   time.Time `json:"period_start"`
 	PeriodEnd     time.Time `json:"period_end"`
+	PendingDrafts int       `json:"pending_drafts"`
 	HRDraftTotals struct {
 		GrossSalary   float64 `json:"gross_salary"`
 		CNAPSEmployee float64 `json:"cnaps_employee"`
@@ -152,3 +275,8 @@ func (PayrollDraft) TableName() string {
 func (PayrollApproved) TableName() string {
 	return "payroll_approved"
 }
+
+// TableName specifies the table name for PayrollStatusChange model
+func (PayrollStatusChange) TableName() string {
+	return "payroll_status_changes"
+}
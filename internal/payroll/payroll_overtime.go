@@ -0,0 +1,133 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go-server/internal/attendance"
+	"go-server/internal/company"
+
+	"gorm.io/gorm"
+)
+
+// overtimeDayType groups overtime hours that are paid at the same rate
+type overtimeDayType struct {
+	Code  string
+	Label string
+}
+
+// Overtime day types, in payslip order. Company holidays are paid at the Sunday rate.
+var (
+	overtimeWeekday  = overtimeDayType{Code: "overtime_weekday", Label: "Heures supplémentaires semaine"}
+	overtimeSaturday = overtimeDayType{Code: "overtime_saturday", Label: "Heures supplémentaires samedi"}
+	overtimeSunday   = overtimeDayType{Code: "overtime_sunday_holiday", Label: "Heures supplémentaires dimanche et jours fériés"}
+)
+
+// overtimeRates holds the overtime multipliers from company settings
+type overtimeRates struct {
+	Weekday  float64
+	Saturday float64
+	Sunday   float64
+}
+
+// overtimeLineItems turns the employee's approved overtime in the draft period into one taxable earning per day type
+func (r *Repo) overtimeLineItems(ctx context.Context, draft *PayrollDraft) ([]PayrollLineItem, error) {
+	var records []attendance.Attendance
+	if err := r.db.WithContext(ctx).
+		Where("employee_id = ? AND date BETWEEN ? AND ? AND overtime_approved = ? AND overtime_hours > 0",
+			draft.EmployeeID, draft.PeriodStart, draft.PeriodEnd, true).
+		Order("date ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("get approved overtime: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	calendar, err := r.getHolidayCalendar(ctx, draft.PeriodStart, draft.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := map[overtimeDayType]float64{}
+	for _, record := range records {
+		hours[classifyOvertimeDay(record.Date, calendar)] += record.OvertimeHours
+	}
+
+	rates, err := r.getOvertimeRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	standardHours, err := r.rulesAt(draft.PeriodEnd).Float(ctx, "standard_working_hours")
+	if err != nil {
+		return nil, fmt.Errorf("get standard working hours: %w", err)
+	}
+	if standardHours <= 0 {
+		return nil, fmt.Errorf("standard working hours must be positive")
+	}
+	hourlyRate := draft.BaseSalary / standardHours
+
+	var items []PayrollLineItem
+	for _, dayType := range []struct {
+		overtimeDayType
+		rate float64
+	}{
+		{overtimeWeekday, rates.Weekday},
+		{overtimeSaturday, rates.Saturday},
+		{overtimeSunday, rates.Sunday},
+	} {
+		h := hours[dayType.overtimeDayType]
+		amount := math.Round(h*hourlyRate*dayType.rate*100) / 100
+		if amount <= 0 {
+			continue
+		}
+
+		items = append(items, PayrollLineItem{
+			Type:      LineItemTaxableEarning,
+			Code:      dayType.Code,
+			Label:     fmt.Sprintf("%s (%.2f h à %.0f%%)", dayType.Label, h, dayType.rate*100),
+			Amount:    amount,
+			Source:    LineItemSourceAttendance,
+			CreatedBy: draft.CreatedBy,
+		})
+	}
+	return items, nil
+}
+
+// classifyOvertimeDay returns the day type whose rate applies to overtime worked on date
+func classifyOvertimeDay(date time.Time, calendar *holidayCalendar) overtimeDayType {
+	switch {
+	case date.Weekday() == time.Sunday || calendar.IsHoliday(date):
+		return overtimeSunday
+	case date.Weekday() == time.Saturday:
+		return overtimeSaturday
+	default:
+		return overtimeWeekday
+	}
+}
+
+// getOvertimeRates reads overtime multipliers from company settings, using the schema defaults when unset
+func (r *Repo) getOvertimeRates(ctx context.Context) (overtimeRates, error) {
+	rates := overtimeRates{Weekday: 1.25, Saturday: 1.50, Sunday: 2.00}
+
+	var settings company.CompanySettings
+	if err := r.db.WithContext(ctx).First(&settings).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return rates, nil
+		}
+		return rates, fmt.Errorf("get company settings: %w", err)
+	}
+
+	if settings.OvertimeWeekdayRate > 0 {
+		rates.Weekday = settings.OvertimeWeekdayRate
+	}
+	if settings.OvertimeSaturdayRate > 0 {
+		rates.Saturday = settings.OvertimeSaturdayRate
+	}
+	if settings.OvertimeSundayRate > 0 {
+		rates.Sunday = settings.OvertimeSundayRate
+	}
+	return rates, nil
+}
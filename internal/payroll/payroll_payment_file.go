@@ -0,0 +1,280 @@
+package payroll
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-server/internal/banking"
+	"go-server/internal/company"
+)
+
+// PaymentFile is a rendered bank transfer or mobile money file
+type PaymentFile struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// renderTransferCSV writes the bank transfers of a batch as semicolon-separated UTF-8 for banks' bulk upload,
+// with the domestic RIB next to the IBAN and amounts with a dot decimal
+func renderTransferCSV(batch *PaymentBatch, items []PaymentBatchItem) (*PaymentFile, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	header := []string{"reference", "beneficiary", "bank_name", "iban", "rib", "bic", "amount", "currency",
+		"execution_date", "label"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		record := []string{
+			item.FichePaieNumber, item.AccountHolder, item.BankName, item.IBAN, banking.RIB(item.IBAN), item.BIC,
+			strconv.FormatFloat(item.Amount, 'f', 2, 64), batch.Currency, batch.ExecutionDate.Format("2006-01-02"),
+			paymentLabel(batch),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return &PaymentFile{
+		Filename:    batch.BatchNumber + "_virements.csv",
+		ContentType: "text/csv; charset=utf-8",
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// renderMobileMoneyCSV writes the payments of a batch to one mobile money operator as the semicolon-separated
+// bulk file operators accept: local mobile number, amount in whole ariary when it has no cents, name and reference
+func renderMobileMoneyCSV(batch *PaymentBatch, items []PaymentBatchItem, operator string) (*PaymentFile, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	if err := w.Write([]string{"msisdn", "amount", "name", "reference", "label"}); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		record := []string{
+			banking.LocalMSISDN(item.MobileNumber), strconv.FormatFloat(item.Amount, 'f', -1, 64), item.AccountHolder,
+			item.FichePaieNumber, paymentLabel(batch),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return &PaymentFile{
+		Filename:    batch.BatchNumber + "_" + operator + ".csv",
+		ContentType: "text/csv; charset=utf-8",
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// renderPain001 writes the bank transfers of a batch as an ISO 20022 customer credit transfer initiation
+// (pain.001.001.03): one salary payment instruction debiting the company payroll account
+func renderPain001(batch *PaymentBatch, items []PaymentBatchItem, settings *company.CompanySettings, createdAt time.Time) (*PaymentFile, error) {
+	var total float64
+	transactions := make([]painTransaction, 0, len(items))
+	for _, item := range items {
+		total += item.Amount
+		transaction := painTransaction{
+			PmtID:    painPaymentID{EndToEndID: truncate(item.FichePaieNumber, 35)},
+			Amt:      painAmount{InstdAmt: painCurrencyAmount{Ccy: batch.Currency, Value: painAmountValue(item.Amount)}},
+			Cdtr:     painParty{Nm: truncate(item.AccountHolder, 70)},
+			CdtrAcct: painAccount{ID: painAccountID{IBAN: item.IBAN}},
+			Purp:     &painPurpose{Cd: "SALA"},
+			RmtInf:   &painRemittance{Ustrd: truncate(paymentLabel(batch)+" "+item.FichePaieNumber, 140)},
+		}
+		// The creditor's bank is optional: without a BIC it is derived from the IBAN
+		if item.BIC != "" {
+			agent := painAgent(item.BIC)
+			transaction.CdtrAgt = &agent
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	count := strconv.Itoa(len(items))
+	controlSum := painAmountValue(total)
+	document := painDocument{
+		Xmlns: "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03",
+		Initiation: painInitiation{
+			GrpHdr: painGroupHeader{
+				MsgID:    truncate(batch.BatchNumber, 35),
+				CreDtTm:  createdAt.Format("2006-01-02T15:04:05"),
+				NbOfTxs:  count,
+				CtrlSum:  controlSum,
+				InitgPty: painParty{Nm: truncate(settings.CompanyName, 70)},
+			},
+			PmtInf: painPaymentInfo{
+				PmtInfID:    truncate(batch.BatchNumber, 35),
+				PmtMtd:      "TRF",
+				BtchBookg:   true,
+				NbOfTxs:     count,
+				CtrlSum:     controlSum,
+				PmtTpInf:    painPaymentType{CtgyPurp: painPurpose{Cd: "SALA"}},
+				ReqdExctnDt: batch.ExecutionDate.Format("2006-01-02"),
+				Dbtr:        painParty{Nm: truncate(settings.CompanyName, 70)},
+				DbtrAcct:    painAccount{ID: painAccountID{IBAN: *settings.PayrollBankIBAN}, Ccy: batch.Currency},
+				DbtrAgt:     painAgent(derefString(settings.PayrollBankBIC)),
+				ChrgBr:      "SLEV",
+				CdtTrfTxInf: transactions,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, fmt.Errorf("encode pain.001: %w", err)
+	}
+	buf.WriteString("\n")
+
+	return &PaymentFile{
+		Filename:    batch.BatchNumber + "_pain001.xml",
+		ContentType: "application/xml",
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// paymentLabel is the label of a batch's payments on bank statements, e.g. Salaire 03/2026
+func paymentLabel(batch *PaymentBatch) string {
+	return "Salaire " + batch.PeriodStart.Format("01/2006")
+}
+
+// painAmountValue formats an amount with two decimals and a dot separator, as ISO 20022 requires
+func painAmountValue(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// painAgent identifies a bank by its BIC, or as not provided when the BIC is unknown
+func painAgent(bic string) painFinancialInstitution {
+	if bic == "" {
+		return painFinancialInstitution{FinInstnID: painInstitutionID{Othr: &painOtherID{ID: "NOTPROVIDED"}}}
+	}
+	return painFinancialInstitution{FinInstnID: painInstitutionID{BIC: bic}}
+}
+
+// truncate cuts s to at most n characters, the maximum length of an ISO 20022 text field
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// The pain.001.001.03 elements below are declared in schema order, which encoding/xml preserves.
+
+// painDocument is the root of a pain.001.001.03 message
+type painDocument struct {
+	XMLName    xml.Name       `xml:"Document"`
+	Xmlns      string         `xml:"xmlns,attr"`
+	Initiation painInitiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type painInitiation struct {
+	GrpHdr painGroupHeader `xml:"GrpHdr"`
+	PmtInf painPaymentInfo `xml:"PmtInf"`
+}
+
+type painGroupHeader struct {
+	MsgID    string    `xml:"MsgId"`
+	CreDtTm  string    `xml:"CreDtTm"`
+	NbOfTxs  string    `xml:"NbOfTxs"`
+	CtrlSum  string    `xml:"CtrlSum"`
+	InitgPty painParty `xml:"InitgPty"`
+}
+
+type painPaymentInfo struct {
+	PmtInfID    string                   `xml:"PmtInfId"`
+	PmtMtd      string                   `xml:"PmtMtd"`
+	BtchBookg   bool                     `xml:"BtchBookg"`
+	NbOfTxs     string                   `xml:"NbOfTxs"`
+	CtrlSum     string                   `xml:"CtrlSum"`
+	PmtTpInf    painPaymentType          `xml:"PmtTpInf"`
+	ReqdExctnDt string                   `xml:"ReqdExctnDt"`
+	Dbtr        painParty                `xml:"Dbtr"`
+	DbtrAcct    painAccount              `xml:"DbtrAcct"`
+	DbtrAgt     painFinancialInstitution `xml:"DbtrAgt"`
+	ChrgBr      string                   `xml:"ChrgBr"`
+	CdtTrfTxInf []painTransaction        `xml:"CdtTrfTxInf"`
+}
+
+type painPaymentType struct {
+	CtgyPurp painPurpose `xml:"CtgyPurp"`
+}
+
+type painPurpose struct {
+	Cd string `xml:"Cd"`
+}
+
+type painParty struct {
+	Nm string `xml:"Nm"`
+}
+
+type painAccount struct {
+	ID  painAccountID `xml:"Id"`
+	Ccy string        `xml:"Ccy,omitempty"`
+}
+
+type painAccountID struct {
+	IBAN string `xml:"IBAN"`
+}
+
+type painFinancialInstitution struct {
+	FinInstnID painInstitutionID `xml:"FinInstnId"`
+}
+
+type painInstitutionID struct {
+	BIC  string       `xml:"BIC,omitempty"`
+	Othr *painOtherID `xml:"Othr,omitempty"`
+}
+
+type painOtherID struct {
+	ID string `xml:"Id"`
+}
+
+type painTransaction struct {
+	PmtID    painPaymentID             `xml:"PmtId"`
+	Amt      painAmount                `xml:"Amt"`
+	CdtrAgt  *painFinancialInstitution `xml:"CdtrAgt,omitempty"`
+	Cdtr     painParty                 `xml:"Cdtr"`
+	CdtrAcct painAccount               `xml:"CdtrAcct"`
+	Purp     *painPurpose              `xml:"Purp,omitempty"`
+	RmtInf   *painRemittance           `xml:"RmtInf,omitempty"`
+}
+
+type painPaymentID struct {
+	EndToEndID string `xml:"EndToEndId"`
+}
+
+type painAmount struct {
+	InstdAmt painCurrencyAmount `xml:"InstdAmt"`
+}
+
+type painCurrencyAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type painRemittance struct {
+	Ustrd string `xml:"Ustrd"`
+}
@@ -0,0 +1,234 @@
+package payroll
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreatePaymentBatch generates a salary payment batch from the approved payroll of a period (Accountant only)
+func (h *Handler) CreatePaymentBatch(c *gin.Context) {
+	var input CreatePaymentBatchRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can generate payment batches"})
+		return
+	}
+
+	executionDate := dateOnly(time.Now())
+	if input.ExecutionDate != nil {
+		executionDate = dateOnly(*input.ExecutionDate)
+		if executionDate.Before(dateOnly(time.Now())) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "execution_date cannot be in the past"})
+			return
+		}
+	}
+
+	batch := &PaymentBatch{
+		PeriodStart:   input.PeriodStart,
+		PeriodEnd:     input.PeriodEnd,
+		ExecutionDate: executionDate,
+		CreatedBy:     userID,
+	}
+
+	report, err := h.repo.GeneratePaymentBatch(c.Request.Context(), batch)
+	if err != nil {
+		if errors.Is(err, errNoPayments) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "skipped": report.Skipped})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// ListPaymentBatches retrieves salary payment batches with filtering
+func (h *Handler) ListPaymentBatches(c *gin.Context) {
+	var query PaymentBatchListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	batches, total, err := h.repo.ListPaymentBatches(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list payment batches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"batches": batches,
+		"total":   total,
+		"limit":   query.Limit,
+		"offset":  query.Offset,
+	})
+}
+
+// GetPaymentBatchByID retrieves a salary payment batch with its items, account numbers masked
+func (h *Handler) GetPaymentBatchByID(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment batch ID"})
+		return
+	}
+
+	batch, err := h.repo.GetPaymentBatchByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment batch not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
+
+// DownloadPaymentFile downloads the bank transfer CSV, pain.001 XML or mobile money CSV of a batch (Accountant only)
+func (h *Handler) DownloadPaymentFile(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment batch ID"})
+		return
+	}
+
+	var query PaymentFileQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can download payment files"})
+		return
+	}
+
+	if _, err := h.repo.GetPaymentBatchByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment batch not found"})
+		return
+	}
+
+	file, err := h.repo.GetPaymentFile(c.Request.Context(), id, query.Format, query.Operator)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+file.Filename)
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+// ConfirmPaymentBatch marks the net salaries of a batch as paid, except the items reported as failed (Accountant only)
+func (h *Handler) ConfirmPaymentBatch(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment batch ID"})
+		return
+	}
+
+	var input ConfirmPaymentBatchRequest
+	if !bindOptionalJSON(c, &input) {
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can confirm payment batches"})
+		return
+	}
+
+	if _, err := h.repo.GetPaymentBatchByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment batch not found"})
+		return
+	}
+
+	paidOn := time.Now()
+	if input.PaidOn != nil {
+		paidOn = *input.PaidOn
+	}
+	failed := make(map[uuid.UUID]string, len(input.FailedItems))
+	for _, item := range input.FailedItems {
+		failed[item.ItemID] = item.Reason
+	}
+
+	batch, err := h.repo.ConfirmPaymentBatch(c.Request.Context(), id, userID, paidOn, failed)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
+
+// CancelPaymentBatch cancels a batch that was not sent, so its payroll can be paid in a new batch (Accountant only)
+func (h *Handler) CancelPaymentBatch(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment batch ID"})
+		return
+	}
+
+	var input CancelPaymentBatchRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Accountant role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "accountant" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Accountant can cancel payment batches"})
+		return
+	}
+
+	if _, err := h.repo.GetPaymentBatchByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment batch not found"})
+		return
+	}
+
+	batch, err := h.repo.CancelPaymentBatch(c.Request.Context(), id, userID, input.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
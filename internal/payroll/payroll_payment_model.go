@@ -0,0 +1,142 @@
+package payroll
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment batch statuses: generated -> confirmed, or cancelled
+const (
+	PaymentBatchStatusGenerated = "generated"
+	PaymentBatchStatusConfirmed = "confirmed"
+	PaymentBatchStatusCancelled = "cancelled"
+)
+
+// Payment item statuses. Failed and cancelled items free their payroll for a later batch.
+const (
+	PaymentItemStatusPending   = "pending"
+	PaymentItemStatusPaid      = "paid"
+	PaymentItemStatusFailed    = "failed"
+	PaymentItemStatusCancelled = "cancelled"
+)
+
+// Payment file formats
+const (
+	PaymentFileCSV         = "csv"
+	PaymentFilePain001     = "pain001"
+	PaymentFileMobileMoney = "mobile_money"
+)
+
+// PaymentBatch groups the net salaries of approved payroll paid together by bank transfer and mobile money
+type PaymentBatch struct {
+	ID            uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchNumber   string             `gorm:"type:varchar(50);uniqueIndex;not null" json:"batch_number"`
+	PeriodStart   time.Time          `gorm:"type:date;not null" json:"period_start"`
+	PeriodEnd     time.Time          `gorm:"type:date;not null" json:"period_end"`
+	ExecutionDate time.Time          `gorm:"type:date;not null" json:"execution_date"`
+	Status        string             `gorm:"type:varchar(20);not null;default:'generated'" json:"status"`
+	PaymentCount  int                `gorm:"not null" json:"payment_count"`
+	TotalAmount   float64            `gorm:"type:numeric(15,2);not null" json:"total_amount"`
+	PaidAmount    float64            `gorm:"type:numeric(15,2);not null;default:0" json:"paid_amount"`
+	Currency      string             `gorm:"type:varchar(10);not null" json:"currency"`
+	CreatedBy     uuid.UUID          `gorm:"type:uuid;not null" json:"created_by"`
+	ConfirmedBy   *uuid.UUID         `gorm:"type:uuid" json:"confirmed_by,omitempty"`
+	ConfirmedAt   *time.Time         `json:"confirmed_at,omitempty"`
+	PaidOn        *time.Time         `gorm:"type:date" json:"paid_on,omitempty"`
+	CancelledBy   *uuid.UUID         `gorm:"type:uuid" json:"cancelled_by,omitempty"`
+	CancelledAt   *time.Time         `json:"cancelled_at,omitempty"`
+	Reason        string             `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt     time.Time          `gorm:"default:now()" json:"created_at"`
+	UpdatedAt     time.Time          `gorm:"default:now()" json:"updated_at"`
+	Items         []PaymentBatchItem `gorm:"foreignKey:BatchID" json:"items,omitempty"`
+}
+
+// PaymentBatchItem is the net salary of one approved payroll in a batch, with the employee's payment account
+// as it was when the batch was generated, so files downloaded later match the ones sent
+type PaymentBatchItem struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BatchID             uuid.UUID  `gorm:"type:uuid;not null;index" json:"batch_id"`
+	ApprovedID          uuid.UUID  `gorm:"type:uuid;not null" json:"approved_id"`
+	EmployeeID          uuid.UUID  `gorm:"type:uuid;not null" json:"employee_id"`
+	EmployeeName        string     `gorm:"type:varchar(200);not null" json:"employee_name"`
+	FichePaieNumber     string     `gorm:"type:varchar(50);not null" json:"fiche_paie_number"`
+	Amount              float64    `gorm:"type:numeric(15,2);not null" json:"amount"`
+	Method              string     `gorm:"type:varchar(20);not null" json:"method"`
+	AccountHolder       string     `gorm:"type:varchar(140);not null" json:"account_holder"`
+	BankName            string     `gorm:"type:varchar(100)" json:"bank_name,omitempty"`
+	IBAN                string     `gorm:"type:varchar(34)" json:"-"`
+	BIC                 string     `gorm:"type:varchar(11)" json:"bic,omitempty"`
+	MobileOperator      string     `gorm:"type:varchar(20)" json:"mobile_operator,omitempty"`
+	MobileNumber        string     `gorm:"type:varchar(15)" json:"-"`
+	AccountNumberMasked string     `gorm:"-" json:"account_number_masked,omitempty"`
+	MobileNumberMasked  string     `gorm:"-" json:"mobile_number_masked,omitempty"`
+	Status              string     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	FailureReason       string     `gorm:"type:text" json:"failure_reason,omitempty"`
+	PaidAt              *time.Time `json:"paid_at,omitempty"`
+}
+
+// CreatePaymentBatchRequest represents request to generate a payment batch from the approved payroll of a period.
+// ExecutionDate is the date the bank should execute the transfers, today by default.
+type CreatePaymentBatchRequest struct {
+	PeriodStart   time.Time  `json:"period_start" binding:"required"`
+	PeriodEnd     time.Time  `json:"period_end" binding:"required"`
+	ExecutionDate *time.Time `json:"execution_date"`
+}
+
+// ConfirmPaymentBatchRequest represents request to confirm a batch was paid. Items listed in FailedItems were
+// rejected by the bank or operator; every other item is marked paid on PaidOn, today by default.
+type ConfirmPaymentBatchRequest struct {
+	PaidOn      *time.Time           `json:"paid_on"`
+	FailedItems []FailedPaymentInput `json:"failed_items" binding:"dive"`
+}
+
+// FailedPaymentInput identifies a batch item that was not paid
+type FailedPaymentInput struct {
+	ItemID uuid.UUID `json:"item_id" binding:"required"`
+	Reason string    `json:"reason" binding:"required"`
+}
+
+// CancelPaymentBatchRequest represents request to cancel a batch that was not sent
+type CancelPaymentBatchRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// PaymentBatchListQuery represents query parameters for listing payment batches
+type PaymentBatchListQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=generated confirmed cancelled"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// PaymentFileQuery represents query parameters for downloading a payment file
+type PaymentFileQuery struct {
+	Format   string `form:"format" binding:"required,oneof=csv pain001 mobile_money"`
+	Operator string `form:"operator" binding:"omitempty,oneof=mvola orange_money airtel_money"`
+}
+
+// PaymentBatchReport is returned after a batch has been generated, listing the approved payroll left out of it
+type PaymentBatchReport struct {
+	Batch   *PaymentBatch         `json:"batch"`
+	Skipped []SkippedPaymentEntry `json:"skipped"`
+}
+
+// SkippedPaymentEntry is an approved payroll of the period that could not be added to the batch
+type SkippedPaymentEntry struct {
+	ApprovedID      uuid.UUID `json:"approved_id"`
+	EmployeeID      uuid.UUID `json:"employee_id"`
+	EmployeeName    string    `json:"employee_name"`
+	FichePaieNumber string    `json:"fiche_paie_number"`
+	NetSalary       float64   `json:"net_salary"`
+	Reason          string    `json:"reason"`
+}
+
+// TableName specifies the table name for PaymentBatch model
+func (PaymentBatch) TableName() string {
+	return "payroll_payment_batches"
+}
+
+// TableName specifies the table name for PaymentBatchItem model
+func (PaymentBatchItem) TableName() string {
+	return "payroll_payment_items"
+}
@@ -0,0 +1,392 @@
+package payroll
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go-server/internal/company"
+	"go-server/internal/employee"
+	"go-server/internal/sequence"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errNoPayments is returned when a period has no approved payroll that can be added to a payment batch
+var errNoPayments = errors.New("no approved payroll awaiting payment in this period")
+
+// payableSalary is an approved payroll of a period whose net salary has not been paid or put in a batch yet
+type payableSalary struct {
+	ApprovedID      uuid.UUID
+	FichePaieNumber string
+	EmployeeID      uuid.UUID
+	FirstName       string
+	LastName        string
+	NetSalary       float64
+}
+
+// GeneratePaymentBatch puts the unpaid net salaries of the approved payroll of a period in a new batch, with each
+// employee's payment account. Payroll whose employee has no payment account is left out and reported as skipped;
+// when nothing can be paid, errNoPayments is returned with the report.
+func (r *Repo) GeneratePaymentBatch(ctx context.Context, batch *PaymentBatch) (*PaymentBatchReport, error) {
+	if !batch.PeriodEnd.After(batch.PeriodStart) {
+		return nil, fmt.Errorf("period_end must be after period_start")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	report := &PaymentBatchReport{Skipped: []SkippedPaymentEntry{}}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var salaries []payableSalary
+		if err := tx.Table("payroll_approved pa").
+			Select("pa.id AS approved_id, pa.fiche_paie_number, d.employee_id, e.first_name, e.last_name, d.net_salary").
+			Joins("JOIN payroll_drafts d ON d.id = pa.draft_id").
+			Joins("JOIN employees e ON e.id = d.employee_id").
+			Where("d.deleted_at IS NULL AND d.period_start >= ? AND d.period_end <= ?", batch.PeriodStart, batch.PeriodEnd).
+			Where("pa.paid_at IS NULL").
+			Where("NOT EXISTS (SELECT 1 FROM payroll_payment_items i WHERE i.approved_id = pa.id AND i.status IN ?)",
+				[]string{PaymentItemStatusPending, PaymentItemStatusPaid}).
+			Order("e.last_name ASC, e.first_name ASC").
+			Scan(&salaries).Error; err != nil {
+			return fmt.Errorf("get payable salaries: %w", err)
+		}
+
+		employeeIDs := make([]uuid.UUID, len(salaries))
+		for i, salary := range salaries {
+			employeeIDs[i] = salary.EmployeeID
+		}
+		var accounts []employee.PaymentAccount
+		if err := tx.Where("employee_id IN ?", employeeIDs).Find(&accounts).Error; err != nil {
+			return fmt.Errorf("get payment accounts: %w", err)
+		}
+		accountsByEmployee := make(map[uuid.UUID]employee.PaymentAccount, len(accounts))
+		for _, account := range accounts {
+			accountsByEmployee[account.EmployeeID] = account
+		}
+
+		batch.Items = nil
+		batch.TotalAmount = 0
+		for _, salary := range salaries {
+			name := salary.FirstName + " " + salary.LastName
+			account, ok := accountsByEmployee[salary.EmployeeID]
+			reason := ""
+			switch {
+			case salary.NetSalary <= 0:
+				reason = "net salary is not positive"
+			case !ok:
+				reason = "employee has no payment account"
+			}
+			if reason != "" {
+				report.Skipped = append(report.Skipped, SkippedPaymentEntry{
+					ApprovedID:      salary.ApprovedID,
+					EmployeeID:      salary.EmployeeID,
+					EmployeeName:    name,
+					FichePaieNumber: salary.FichePaieNumber,
+					NetSalary:       salary.NetSalary,
+					Reason:          reason,
+				})
+				continue
+			}
+
+			amount := math.Round(salary.NetSalary*100) / 100
+			batch.Items = append(batch.Items, PaymentBatchItem{
+				ApprovedID:      salary.ApprovedID,
+				EmployeeID:      salary.EmployeeID,
+				EmployeeName:    name,
+				FichePaieNumber: salary.FichePaieNumber,
+				Amount:          amount,
+				Method:          account.Method,
+				AccountHolder:   account.AccountHolder,
+				BankName:        account.BankName,
+				IBAN:            account.IBAN,
+				BIC:             account.BIC,
+				MobileOperator:  account.MobileOperator,
+				MobileNumber:    account.MobileNumber,
+				Status:          PaymentItemStatusPending,
+			})
+			batch.TotalAmount += amount
+		}
+		if len(batch.Items) == 0 {
+			return errNoPayments
+		}
+
+		var settings company.CompanySettings
+		if err := tx.First(&settings).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("get company settings: %w", err)
+		}
+		batch.Currency = settings.Currency
+		if batch.Currency == "" {
+			batch.Currency = "MGA"
+		}
+
+		fiscalYear, seq, err := sequence.Next(tx, sequence.PaymentBatch, batch.ExecutionDate)
+		if err != nil {
+			return err
+		}
+		batch.BatchNumber = sequence.Format("VIR", fiscalYear, seq)
+		batch.Status = PaymentBatchStatusGenerated
+		batch.PaymentCount = len(batch.Items)
+		batch.TotalAmount = math.Round(batch.TotalAmount*100) / 100
+
+		// The unique index on pending and paid items rejects a salary put in two batches concurrently
+		if err := tx.Create(batch).Error; err != nil {
+			return fmt.Errorf("create payment batch: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errNoPayments) {
+			return report, err
+		}
+		return nil, err
+	}
+
+	maskPaymentItems(batch.Items)
+	report.Batch = batch
+	return report, nil
+}
+
+// lockPaymentBatch loads a payment batch and locks its row until the transaction ends
+func lockPaymentBatch(tx *gorm.DB, id uuid.UUID) (*PaymentBatch, error) {
+	var batch PaymentBatch
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&batch).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("payment batch not found")
+		}
+		return nil, fmt.Errorf("get payment batch: %w", err)
+	}
+	return &batch, nil
+}
+
+// ConfirmPaymentBatch records that a generated batch was executed: the listed failed items are marked failed,
+// freeing their payroll for a later batch, and every other item and its approved payroll are marked paid on paidOn
+func (r *Repo) ConfirmPaymentBatch(ctx context.Context, id uuid.UUID, actorID uuid.UUID, paidOn time.Time, failed map[uuid.UUID]string) (*PaymentBatch, error) {
+	paidOn = dateOnly(paidOn)
+	if paidOn.After(dateOnly(time.Now())) {
+		return nil, fmt.Errorf("paid_on cannot be in the future")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		batch, err := lockPaymentBatch(tx, id)
+		if err != nil {
+			return err
+		}
+		if batch.Status != PaymentBatchStatusGenerated {
+			return fmt.Errorf("cannot confirm a %s payment batch", batch.Status)
+		}
+
+		var items []PaymentBatchItem
+		if err := tx.Where("batch_id = ?", batch.ID).Find(&items).Error; err != nil {
+			return fmt.Errorf("get payment items: %w", err)
+		}
+		inBatch := make(map[uuid.UUID]bool, len(items))
+		for _, item := range items {
+			inBatch[item.ID] = true
+		}
+		for itemID := range failed {
+			if !inBatch[itemID] {
+				return fmt.Errorf("payment item %s is not in this batch", itemID)
+			}
+		}
+
+		now := time.Now()
+		batch.PaidAmount = 0
+		for _, item := range items {
+			if reason, ok := failed[item.ID]; ok {
+				item.Status = PaymentItemStatusFailed
+				item.FailureReason = reason
+			} else {
+				result := tx.Model(&PayrollApproved{}).Where("id = ? AND paid_at IS NULL", item.ApprovedID).
+					Updates(map[string]interface{}{"paid_at": paidOn, "payment_batch_id": batch.ID, "updated_at": now})
+				if result.Error != nil {
+					return fmt.Errorf("mark payroll paid: %w", result.Error)
+				}
+				if result.RowsAffected == 0 {
+					return fmt.Errorf("payslip %s is already paid", item.FichePaieNumber)
+				}
+				item.Status = PaymentItemStatusPaid
+				item.PaidAt = &paidOn
+				batch.PaidAmount += item.Amount
+			}
+			if err := tx.Save(&item).Error; err != nil {
+				return fmt.Errorf("update payment item: %w", err)
+			}
+		}
+
+		batch.Status = PaymentBatchStatusConfirmed
+		batch.ConfirmedBy = &actorID
+		batch.ConfirmedAt = &now
+		batch.PaidOn = &paidOn
+		batch.PaidAmount = math.Round(batch.PaidAmount*100) / 100
+		batch.UpdatedAt = now
+		if err := tx.Save(batch).Error; err != nil {
+			return fmt.Errorf("update payment batch: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetPaymentBatchByID(ctx, id)
+}
+
+// CancelPaymentBatch cancels a batch that was not sent, freeing its payroll for a new batch
+func (r *Repo) CancelPaymentBatch(ctx context.Context, id uuid.UUID, actorID uuid.UUID, reason string) (*PaymentBatch, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		batch, err := lockPaymentBatch(tx, id)
+		if err != nil {
+			return err
+		}
+		if batch.Status != PaymentBatchStatusGenerated {
+			return fmt.Errorf("cannot cancel a %s payment batch", batch.Status)
+		}
+
+		if err := tx.Model(&PaymentBatchItem{}).Where("batch_id = ?", batch.ID).
+			Update("status", PaymentItemStatusCancelled).Error; err != nil {
+			return fmt.Errorf("cancel payment items: %w", err)
+		}
+
+		now := time.Now()
+		batch.Status = PaymentBatchStatusCancelled
+		batch.CancelledBy = &actorID
+		batch.CancelledAt = &now
+		batch.Reason = reason
+		batch.UpdatedAt = now
+		if err := tx.Save(batch).Error; err != nil {
+			return fmt.Errorf("update payment batch: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetPaymentBatchByID(ctx, id)
+}
+
+// GetPaymentBatchByID retrieves a payment batch with its items, account numbers masked
+func (r *Repo) GetPaymentBatchByID(ctx context.Context, id uuid.UUID) (*PaymentBatch, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var batch PaymentBatch
+	if err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("employee_name ASC") }).
+		Where("id = ?", id).First(&batch).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("payment batch not found")
+		}
+		return nil, fmt.Errorf("get payment batch: %w", err)
+	}
+	maskPaymentItems(batch.Items)
+	return &batch, nil
+}
+
+// ListPaymentBatches retrieves payment batches, newest first, without their items
+func (r *Repo) ListPaymentBatches(ctx context.Context, query PaymentBatchListQuery) ([]PaymentBatch, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Model(&PaymentBatch{})
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count payment batches: %w", err)
+	}
+
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	var batches []PaymentBatch
+	if err := db.Order("created_at DESC").Limit(query.Limit).Offset(query.Offset).Find(&batches).Error; err != nil {
+		return nil, 0, fmt.Errorf("list payment batches: %w", err)
+	}
+	return batches, total, nil
+}
+
+// GetPaymentFile renders the bank transfer or mobile money file of a batch. Files are rendered from the accounts
+// stored with the batch, so downloading one again gives the same payments.
+func (r *Repo) GetPaymentFile(ctx context.Context, id uuid.UUID, format, operator string) (*PaymentFile, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var batch PaymentBatch
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&batch).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("payment batch not found")
+		}
+		return nil, fmt.Errorf("get payment batch: %w", err)
+	}
+	if batch.Status == PaymentBatchStatusCancelled {
+		return nil, fmt.Errorf("payment batch is cancelled")
+	}
+
+	method := employee.PaymentMethodBankTransfer
+	if format == PaymentFileMobileMoney {
+		if operator == "" {
+			return nil, fmt.Errorf("operator is required for mobile money files")
+		}
+		method = employee.PaymentMethodMobileMoney
+	}
+
+	db := r.db.WithContext(ctx).Where("batch_id = ? AND method = ? AND status IN ?", batch.ID, method,
+		[]string{PaymentItemStatusPending, PaymentItemStatusPaid})
+	if method == employee.PaymentMethodMobileMoney {
+		db = db.Where("mobile_operator = ?", operator)
+	}
+	var items []PaymentBatchItem
+	if err := db.Order("employee_name ASC").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("get payment items: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("payment batch has no %s payments to include in this file", paymentChannel(method, operator))
+	}
+
+	switch format {
+	case PaymentFileCSV:
+		return renderTransferCSV(&batch, items)
+	case PaymentFilePain001:
+		var settings company.CompanySettings
+		if err := r.db.WithContext(ctx).First(&settings).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("get company settings: %w", err)
+		}
+		if settings.PayrollBankIBAN == nil || *settings.PayrollBankIBAN == "" {
+			return nil, fmt.Errorf("set payroll_bank_iban in company settings to generate pain.001 files")
+		}
+		return renderPain001(&batch, items, &settings, time.Now())
+	default:
+		return renderMobileMoneyCSV(&batch, items, operator)
+	}
+}
+
+// maskPaymentItems fills the masked account and mobile numbers of batch items
+func maskPaymentItems(items []PaymentBatchItem) {
+	for i := range items {
+		account := employee.PaymentAccount{IBAN: items[i].IBAN, MobileNumber: items[i].MobileNumber}
+		account.Mask()
+		items[i].AccountNumberMasked = account.AccountNumberMasked
+		items[i].MobileNumberMasked = account.MobileNumberMasked
+	}
+}
+
+// paymentChannel names a payment method, or a mobile money operator, in messages
+func paymentChannel(method, operator string) string {
+	if method == employee.PaymentMethodMobileMoney {
+		return operator
+	}
+	return "bank transfer"
+}
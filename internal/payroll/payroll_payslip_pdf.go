@@ -0,0 +1,221 @@
+package payroll
+
+import (
+	"fmt"
+	"strings"
+
+	"go-server/internal/pdf"
+	"go-server/internal/taxrules"
+)
+
+// DraftWatermark is stamped on payslip previews rendered from unapproved drafts
+const DraftWatermark = "DRAFT - NOT OFFICIAL"
+
+// Column positions of the payslip table, in points from the left edge
+const (
+	payslipLeft        = 40.0
+	payslipRight       = 555.0
+	payslipColBase     = 265.0
+	payslipColGain     = 355.0
+	payslipColEmployee = 450.0
+	payslipColEmployer = 550.0
+)
+
+// payslipRow is one line of the payslip table; zero amounts are left blank
+type payslipRow struct {
+	label    string
+	base     float64
+	gain     float64
+	employee float64
+	employer float64
+	bold     bool
+}
+
+// renderFichePaiePDF lays out a payslip as a single A4 page. A non-empty watermark marks it as unofficial.
+func renderFichePaiePDF(fp *FichePaie, watermark string) ([]byte, error) {
+	doc := pdf.New()
+	doc.AddPage()
+	if watermark != "" {
+		doc.SetWatermark(watermark)
+	}
+
+	title := "Fiche de paie"
+	if fp.FichePaieNumber != "" {
+		title += " " + fp.FichePaieNumber
+	}
+	doc.SetTitle(title)
+
+	// Company identity and document reference
+	y := 55.0
+	doc.Text(payslipLeft, y, 16, true, fp.CompanyName)
+	doc.TextRight(payslipRight, y, 14, true, "BULLETIN DE PAIE")
+	y += 15
+	if fp.CompanyAddress != "" {
+		doc.Text(payslipLeft, y, 9, false, fp.CompanyAddress)
+	}
+	if fp.FichePaieNumber != "" {
+		doc.TextRight(payslipRight, y, 9, false, "N° "+fp.FichePaieNumber)
+	} else {
+		doc.TextRight(payslipRight, y, 9, false, "Aperçu - brouillon non approuvé")
+	}
+	y += 12
+	doc.Text(payslipLeft, y, 9, false, joinNonEmpty("   ", labelled("NIF", fp.CompanyNIF), labelled("STAT", fp.CompanySTAT)))
+	doc.TextRight(payslipRight, y, 9, false, fmt.Sprintf("Période du %s au %s",
+		fp.PeriodStart.Format("02/01/2006"), fp.PeriodEnd.Format("02/01/2006")))
+	y += 12
+	doc.Text(payslipLeft, y, 9, false, joinNonEmpty("   ", labelled("CNAPS", fp.CompanyCNAPSNumber), labelled("OSTIE", fp.CompanyOSTIENumber)))
+	y += 12
+	doc.Line(payslipLeft, y, payslipRight, y)
+
+	// Employee block
+	y += 20
+	doc.Text(payslipLeft, y, 11, true, "Salarié")
+	y += 15
+	doc.Text(payslipLeft, y, 10, false, fp.EmployeeName)
+	doc.Text(300, y, 9, false, "Poste : "+fp.EmployeePosition)
+	y += 13
+	doc.Text(payslipLeft, y, 9, false, "Date d'embauche : "+fp.EmployeeHireDate.Format("02/01/2006"))
+	doc.Text(300, y, 9, false, "Département : "+fp.EmployeeDepartment)
+	if fp.EmployeeNationalID != "" {
+		y += 13
+		doc.Text(payslipLeft, y, 9, false, "CIN : "+fp.EmployeeNationalID)
+	}
+
+	// Earnings and contributions table
+	y += 28
+	doc.FillRect(payslipLeft, y-12, payslipRight-payslipLeft, 17, 0.9)
+	doc.Text(payslipLeft+5, y, 9, true, "Rubrique")
+	doc.TextRight(payslipColBase, y, 9, true, "Base")
+	doc.TextRight(payslipColGain, y, 9, true, "Gain")
+	doc.TextRight(payslipColEmployee, y, 9, true, "Retenue salariale")
+	doc.TextRight(payslipColEmployer, y, 9, true, "Charge patronale")
+
+	for _, row := range payslipRows(fp) {
+		y += 16
+		doc.Text(payslipLeft+5, y, 9, row.bold, row.label)
+		for _, cell := range []struct {
+			x      float64
+			amount float64
+		}{
+			{payslipColBase, row.base},
+			{payslipColGain, row.gain},
+			{payslipColEmployee, row.employee},
+			{payslipColEmployer, row.employer},
+		} {
+			if cell.amount != 0 {
+				doc.TextRight(cell.x, y, 9, row.bold, pdf.FormatAmount(cell.amount))
+			}
+		}
+	}
+
+	y += 8
+	doc.Line(payslipLeft, y, payslipRight, y)
+	y += 14
+	doc.Text(payslipLeft+5, y, 9, true, "Total")
+	doc.TextRight(payslipColGain, y, 9, true, pdf.FormatAmount(fp.GrossSalary+fp.NonTaxableEarnings))
+	doc.TextRight(payslipColEmployee, y, 9, true, pdf.FormatAmount(fp.CNAPSEmployee+fp.OSTIEEmployee+fp.IRSA+fp.TotalDeductions))
+	doc.TextRight(payslipColEmployer, y, 9, true, pdf.FormatAmount(fp.CNAPSEmployer+fp.OSTIEEmployer))
+
+	y += 30
+	doc.FillRect(300, y-14, payslipRight-300, 22, 0.9)
+	doc.Text(305, y, 12, true, "NET À PAYER")
+	doc.TextRight(payslipColEmployer, y, 12, true, pdf.FormatAmount(fp.NetSalary)+" "+fp.Currency)
+
+	// Approval and signature
+	y += 45
+	if fp.ApprovedAt != nil {
+		doc.Text(payslipLeft, y, 9, false, fmt.Sprintf("Approuvé par %s le %s",
+			fp.AccountantName, fp.ApprovedAt.Format("02/01/2006 15:04")))
+		y += 14
+		doc.Text(payslipLeft, y, 8, true, "Signature numérique (Ed25519, clé "+fp.SignatureKeyID+")")
+		y += 11
+		doc.Text(payslipLeft, y, 7, false, fp.DigitalSignature)
+		y += 14
+		doc.Text(payslipLeft, y, 8, false, "Vérifier l'authenticité : "+fp.VerificationURL)
+	} else {
+		doc.Text(payslipLeft, y, 9, true, "Document non officiel - statut : "+fp.Status)
+		y += 13
+		doc.Text(payslipLeft, y, 8, false, "Ce bulletin n'a pas été approuvé par la comptabilité et ne porte aucune signature.")
+	}
+
+	return doc.Bytes()
+}
+
+// payslipRows lists the payslip table: salary, taxable earnings and absences, contributions and tax,
+// then non-taxable earnings and deductions that only affect the net
+func payslipRows(fp *FichePaie) []payslipRow {
+	rows := []payslipRow{{label: "Salaire de base", gain: fp.BaseSalary}}
+	for _, item := range fp.Items {
+		switch item.Type {
+		case LineItemTaxableEarning:
+			rows = append(rows, payslipRow{label: item.Label, gain: item.Amount})
+		case LineItemAbsence:
+			rows = append(rows, payslipRow{label: item.Label, gain: -item.Amount})
+		}
+	}
+
+	taxableBase := fp.GrossSalary - fp.CNAPSEmployee - fp.OSTIEEmployee
+	rows = append(rows,
+		payslipRow{label: "Salaire brut", gain: fp.GrossSalary, bold: true},
+		payslipRow{label: "CNAPS", base: fp.CNAPSBase, employee: fp.CNAPSEmployee, employer: fp.CNAPSEmployer},
+		payslipRow{label: "OSTIE", base: fp.OSTIEBase, employee: fp.OSTIEEmployee, employer: fp.OSTIEEmployer},
+		payslipRow{label: "IRSA " + fp.IRSABracket, base: taxableBase, employee: fp.IRSA},
+	)
+	rows = append(rows, irsaDetailRows(fp.IRSABreakdown)...)
+
+	for _, item := range fp.Items {
+		switch item.Type {
+		case LineItemTaxableEarning, LineItemAbsence:
+			continue
+		case LineItemNonTaxableEarning:
+			rows = append(rows, payslipRow{label: item.Label, gain: item.Amount})
+		default:
+			rows = append(rows, payslipRow{label: item.Label, employee: item.Amount})
+		}
+	}
+	return rows
+}
+
+// irsaDetailRows explains the IRSA line: the tax per bracket, the dependent reduction and the minimum tax.
+// Amounts are part of the labels so they do not add to the column totals.
+func irsaDetailRows(b *taxrules.IRSABreakdown) []payslipRow {
+	if b == nil {
+		return nil
+	}
+
+	var rows []payslipRow
+	for _, bracket := range b.Brackets {
+		rows = append(rows, payslipRow{
+			label: fmt.Sprintf("   %s : %s", bracket.BracketName, pdf.FormatAmount(bracket.Tax)),
+			base:  bracket.TaxableAmount,
+		})
+	}
+	if b.DependentReduction > 0 {
+		rows = append(rows, payslipRow{
+			label: fmt.Sprintf("   Réduction %d personne(s) à charge : -%s", b.Dependents, pdf.FormatAmount(b.DependentReduction)),
+		})
+	}
+	if b.Tax > 0 && b.Tax == b.MinimumTax && b.GrossTax-b.DependentReduction < b.MinimumTax {
+		rows = append(rows, payslipRow{
+			label: "   Minimum de perception : " + pdf.FormatAmount(b.MinimumTax),
+		})
+	}
+	return rows
+}
+
+func labelled(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + " : " + value
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,136 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-server/internal/auth"
+	"go-server/internal/company"
+	"go-server/internal/employee"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BuildFichePaie assembles a payslip from a draft, its approval (nil for a draft preview),
+// the employee record, the approving accountant and the company identity
+func (r *Repo) BuildFichePaie(ctx context.Context, draft *PayrollDraft, approved *PayrollApproved) (*FichePaie, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var emp employee.Employee
+	if err := r.db.WithContext(ctx).Unscoped().Where("id = ?", draft.EmployeeID).First(&emp).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("employee not found")
+		}
+		return nil, fmt.Errorf("get employee: %w", err)
+	}
+
+	var settings company.CompanySettings
+	if err := r.db.WithContext(ctx).First(&settings).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("get company settings: %w", err)
+	}
+
+	currency := settings.Currency
+	if currency == "" {
+		currency = "MGA"
+	}
+
+	fichePaie := &FichePaie{
+		Status:             draft.Status,
+		CompanyName:        settings.CompanyName,
+		CompanyAddress:     derefString(settings.CompanyAddress),
+		CompanyNIF:         derefString(settings.CompanyNIF),
+		CompanySTAT:        derefString(settings.CompanySTAT),
+		CompanyCNAPSNumber: derefString(settings.CNAPSNumber),
+		CompanyOSTIENumber: derefString(settings.OSTIENumber),
+		Currency:           currency,
+		EmployeeID:         emp.ID,
+		EmployeeName:       strings.TrimSpace(emp.FirstName + " " + emp.LastName),
+		EmployeePosition:   emp.Position,
+		EmployeeDepartment: emp.Department,
+		EmployeeNationalID: emp.NationalID,
+		EmployeeHireDate:   emp.HireDate,
+		PeriodStart:        draft.PeriodStart,
+		PeriodEnd:          draft.PeriodEnd,
+		BaseSalary:         draft.BaseSalary,
+		Items:              draft.Items,
+		GrossSalary:        draft.GrossSalary,
+		NonTaxableEarnings: draft.NonTaxableEarnings,
+		TotalDeductions:    draft.TotalDeductions,
+		CNAPSBase:          draft.CNAPSBase,
+		CNAPSEmployee:      draft.CNAPSEmployee,
+		CNAPSEmployer:      draft.CNAPSEmployer,
+		OSTIEBase:          draft.OSTIEBase,
+		OSTIEEmployee:      draft.OSTIEEmployee,
+		OSTIEEmployer:      draft.OSTIEEmployer,
+		IRSA:               draft.IRSA,
+		IRSABracket:        draft.IRSABracket,
+		IRSABreakdown:      draft.IRSABreakdown,
+		NetSalary:          draft.NetSalary,
+	}
+
+	if approved != nil {
+		accountantName, err := r.getUserDisplayName(ctx, approved.AccountantID)
+		if err != nil {
+			return nil, err
+		}
+
+		fichePaie.Status = StatusApproved
+		fichePaie.FichePaieNumber = approved.FichePaieNumber
+		fichePaie.AccountantName = accountantName
+		fichePaie.ApprovedAt = &approved.ApprovedAt
+		fichePaie.DigitalSignature = approved.DigitalSignature
+		fichePaie.SignatureKeyID = approved.SignatureKeyID
+		fichePaie.VerificationURL = "/api/v1/payroll/verify/" + approved.FichePaieNumber
+	}
+
+	return fichePaie, nil
+}
+
+// GetUserEmployeeID returns the employee record linked to a user account, if any
+func (r *Repo) GetUserEmployeeID(ctx context.Context, userID uuid.UUID) (*uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var user auth.User
+	if err := r.db.WithContext(ctx).Select("id", "employee_id").Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	return user.EmployeeID, nil
+}
+
+// getUserDisplayName returns the linked employee's full name for a user, falling back to the email
+func (r *Repo) getUserDisplayName(ctx context.Context, userID uuid.UUID) (string, error) {
+	var user auth.User
+	if err := r.db.WithContext(ctx).Unscoped().Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", fmt.Errorf("user not found")
+		}
+		return "", fmt.Errorf("get user: %w", err)
+	}
+
+	if user.EmployeeID != nil {
+		var emp employee.Employee
+		err := r.db.WithContext(ctx).Unscoped().Select("first_name", "last_name").Where("id = ?", *user.EmployeeID).First(&emp).Error
+		if err == nil {
+			return strings.TrimSpace(emp.FirstName + " " + emp.LastName), nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return "", fmt.Errorf("get employee: %w", err)
+		}
+	}
+	return user.Email, nil
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
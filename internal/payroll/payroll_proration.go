@@ -0,0 +1,133 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go-server/internal/employee"
+	"go-server/internal/leave"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// leaveCompensationDayDivisor converts monthly salary to the daily rate of untaken leave, counted in calendar days
+const leaveCompensationDayDivisor = 30
+
+// getDraftEmployee retrieves the employee a draft is for, including soft-deleted ones
+func (r *Repo) getDraftEmployee(ctx context.Context, employeeID uuid.UUID) (*employee.Employee, error) {
+	var emp employee.Employee
+	if err := r.db.WithContext(ctx).Unscoped().Where("id = ?", employeeID).First(&emp).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("employee not found")
+		}
+		return nil, fmt.Errorf("get employee: %w", err)
+	}
+	return &emp, nil
+}
+
+// employmentWindow returns the part of the draft period during which the employee was employed
+func employmentWindow(draft *PayrollDraft, emp *employee.Employee) (time.Time, time.Time, error) {
+	start, end := dateOnly(draft.PeriodStart), dateOnly(draft.PeriodEnd)
+	if hired := dateOnly(emp.HireDate); hired.After(start) {
+		start = hired
+	}
+	if emp.TerminationDate != nil {
+		if left := dateOnly(*emp.TerminationDate); left.Before(end) {
+			end = left
+		}
+	}
+	if start.After(end) {
+		return start, end, fmt.Errorf("employee is not employed during this payroll period")
+	}
+	return start, end, nil
+}
+
+// prorationLineItems withholds the working days of the period before the hire date and after the termination date
+func (r *Repo) prorationLineItems(ctx context.Context, draft *PayrollDraft, emp *employee.Employee) ([]PayrollLineItem, error) {
+	calendar, workDaysPerWeek, workingDays, err := r.getPeriodWorkingDays(ctx, draft)
+	if err != nil || workingDays == 0 {
+		return nil, err
+	}
+
+	start, end, err := employmentWindow(draft, emp)
+	if err != nil {
+		return nil, err
+	}
+
+	var beforeHire, afterExit int
+	eachDay(draft.PeriodStart, draft.PeriodEnd, func(day time.Time) {
+		if !calendar.IsWorkingDay(day, workDaysPerWeek) {
+			return
+		}
+		if day.Before(start) {
+			beforeHire++
+		} else if day.After(end) {
+			afterExit++
+		}
+	})
+
+	dailyRate := draft.BaseSalary / float64(workingDays)
+
+	var items []PayrollLineItem
+	if beforeHire > 0 {
+		items = append(items, PayrollLineItem{
+			Type:      LineItemAbsence,
+			Code:      "hire_proration",
+			Label:     fmt.Sprintf("Entrée le %s (%d j ouvrés non dus sur %d)", start.Format("02/01/2006"), beforeHire, workingDays),
+			Amount:    math.Round(float64(beforeHire)*dailyRate*100) / 100,
+			Source:    LineItemSourceEmployee,
+			CreatedBy: draft.CreatedBy,
+		})
+	}
+	if afterExit > 0 {
+		items = append(items, PayrollLineItem{
+			Type:      LineItemAbsence,
+			Code:      "exit_proration",
+			Label:     fmt.Sprintf("Sortie le %s (%d j ouvrés non dus sur %d)", end.Format("02/01/2006"), afterExit, workingDays),
+			Amount:    math.Round(float64(afterExit)*dailyRate*100) / 100,
+			Source:    LineItemSourceEmployee,
+			CreatedBy: draft.CreatedBy,
+		})
+	}
+	return items, nil
+}
+
+// leaveCompensationLineItems pays untaken annual leave on the final payslip of an employee leaving in the period.
+// The annual entitlement accrues over the days served in the year of exit.
+func (r *Repo) leaveCompensationLineItems(ctx context.Context, draft *PayrollDraft, emp *employee.Employee) ([]PayrollLineItem, error) {
+	if emp.TerminationDate == nil {
+		return nil, nil
+	}
+	exit := dateOnly(*emp.TerminationDate)
+	if exit.Before(dateOnly(draft.PeriodStart)) || exit.After(dateOnly(draft.PeriodEnd)) {
+		return nil, nil
+	}
+
+	balance, err := leave.NewRepo(r.db).GetLeaveBalance(ctx, emp.ID, exit.Year())
+	if err != nil {
+		return nil, fmt.Errorf("get leave balance: %w", err)
+	}
+
+	yearStart := time.Date(exit.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	servedFrom := maxTime(yearStart, dateOnly(emp.HireDate))
+	daysInYear := yearStart.AddDate(1, 0, 0).Sub(yearStart).Hours() / 24
+	daysServed := exit.Sub(servedFrom).Hours()/24 + 1
+
+	accrued := balance.AnnualTotal * daysServed / daysInYear
+	untaken := math.Round((accrued-balance.AnnualUsed)*100) / 100
+	if untaken <= 0 {
+		return nil, nil
+	}
+
+	return []PayrollLineItem{{
+		Type:      LineItemTaxableEarning,
+		Code:      "leave_compensation",
+		Label:     fmt.Sprintf("Indemnité compensatrice de congés (%g j)", untaken),
+		Amount:    math.Round(untaken*draft.BaseSalary/leaveCompensationDayDivisor*100) / 100,
+		Source:    LineItemSourceLeave,
+		CreatedBy: draft.CreatedBy,
+	}}, nil
+}
@@ -0,0 +1,67 @@
+package payroll
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetReconciliationHistory lists the reconciliation snapshots saved by the daily job (Accountant only)
+func (h *Handler) GetReconciliationHistory(c *gin.Context) {
+	var query ReconciliationHistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var periodStart, periodEnd *time.Time
+	if query.PeriodStart != "" {
+		t, err := time.Parse("2006-01-02", query.PeriodStart)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period_start format, use YYYY-MM-DD"})
+			return
+		}
+		periodStart = &t
+	}
+	if query.PeriodEnd != "" {
+		t, err := time.Parse("2006-01-02", query.PeriodEnd)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period_end format, use YYYY-MM-DD"})
+			return
+		}
+		periodEnd = &t
+	}
+
+	snapshots, total, err := h.repo.ListReconciliationSnapshots(c.Request.Context(), query, periodStart, periodEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list reconciliation history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"snapshots": snapshots,
+		"total":     total,
+		"limit":     query.Limit,
+		"offset":    query.Offset,
+	})
+}
+
+// GetReconciliationSnapshot retrieves a saved reconciliation snapshot with its full report (Accountant only)
+func (h *Handler) GetReconciliationSnapshot(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reconciliation snapshot ID"})
+		return
+	}
+
+	snapshot, err := h.repo.GetReconciliationSnapshotByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reconciliation snapshot not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
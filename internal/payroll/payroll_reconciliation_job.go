@@ -0,0 +1,168 @@
+package payroll
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-server/internal/mailer"
+	"go-server/internal/notifications"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReconciliationJob reconciles the open pay periods every day, saves each report as a snapshot and alerts admins
+// and accountants, in the app and by email, when a period shows a variance
+type ReconciliationJob struct {
+	repo          *Repo
+	notifications *notifications.Repo
+	mailer        *mailer.Mailer
+	location      *time.Location
+}
+
+// NewReconciliationJob creates the daily reconciliation job. mail may be nil, in which case alerts are only
+// sent as notifications; loc is the company time zone that decides the snapshot day.
+func NewReconciliationJob(gormDB *gorm.DB, mail *mailer.Mailer, loc *time.Location) *ReconciliationJob {
+	return &ReconciliationJob{
+		repo:          NewRepo(gormDB),
+		notifications: notifications.NewRepo(gormDB),
+		mailer:        mail,
+		location:      loc,
+	}
+}
+
+// Run reconciles each open period. A period whose report fails does not stop the others; the failures are
+// returned together.
+func (j *ReconciliationJob) Run(ctx context.Context) error {
+	now := time.Now().In(j.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	periods, err := j.repo.GetOpenPeriods(ctx, today)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, period := range periods {
+		if err := j.reconcilePeriod(ctx, period, today); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", period.PeriodStart.Format("2006-01-02"), err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("reconcile %d of %d periods failed: %s", len(failures), len(periods), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// reconcilePeriod saves the day's snapshot of a period and alerts users of a new or changed variance. A variance
+// identical to the one in the previous snapshot was already alerted and is not sent again.
+func (j *ReconciliationJob) reconcilePeriod(ctx context.Context, period payrollPeriod, today time.Time) error {
+	report, err := j.repo.GetReconciliationReport(ctx, period.PeriodStart, period.PeriodEnd)
+	if err != nil {
+		return err
+	}
+
+	snapshot := &ReconciliationSnapshot{
+		PeriodStart:        period.PeriodStart,
+		PeriodEnd:          period.PeriodEnd,
+		SnapshotDate:       today,
+		Status:             report.Status,
+		VariancePercentage: report.VariancePercentage,
+		Report:             *report,
+		GeneratedAt:        time.Now(),
+	}
+	created, previous, err := j.repo.SaveReconciliationSnapshot(ctx, snapshot)
+	if err != nil || !created {
+		return err
+	}
+
+	if snapshot.Status != ReconciliationStatusVariance || sameVariance(previous, snapshot) {
+		return nil
+	}
+	return j.alert(ctx, snapshot)
+}
+
+// alert notifies every admin and accountant of a snapshot's variance and emails them when email is configured.
+// Email failures are logged: the notifications already carry the alert.
+func (j *ReconciliationJob) alert(ctx context.Context, snapshot *ReconciliationSnapshot) error {
+	users, err := j.repo.getAlertRecipients(ctx)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	title, message := reconciliationAlert(snapshot)
+	link := "/payroll/reconciliation/history/" + snapshot.ID.String()
+
+	userIDs := make([]uuid.UUID, len(users))
+	emails := make([]string, 0, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+		if user.Email != "" {
+			emails = append(emails, user.Email)
+		}
+	}
+	if err := j.notifications.CreateForMultipleUsers(ctx, userIDs, title, message, "warning", &link); err != nil {
+		return fmt.Errorf("notify reconciliation variance: %w", err)
+	}
+
+	if j.mailer != nil {
+		if err := j.mailer.Send(emails, title, message); err != nil {
+			log.Printf("reconciliation: email alert for %s failed: %v", snapshot.PeriodStart.Format("01/2006"), err)
+		}
+	}
+
+	return j.repo.MarkSnapshotAlerted(ctx, snapshot.ID, len(users))
+}
+
+// sameVariance reports whether the previous snapshot of a period showed the same variance, with identical figures
+func sameVariance(previous, snapshot *ReconciliationSnapshot) bool {
+	if previous == nil || previous.Status != ReconciliationStatusVariance {
+		return false
+	}
+	before, err := json.Marshal(previous.Report)
+	if err != nil {
+		return false
+	}
+	after, err := json.Marshal(snapshot.Report)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(before, after)
+}
+
+// reconciliationAlert writes the title and message of a variance alert, listing the figures that differ
+func reconciliationAlert(snapshot *ReconciliationSnapshot) (string, string) {
+	report := snapshot.Report
+	title := fmt.Sprintf("Payroll reconciliation variance %s", snapshot.PeriodStart.Format("01/2006"))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "The reconciliation of the pay period %s to %s on %s detected a variance.\n",
+		snapshot.PeriodStart.Format("2006-01-02"), snapshot.PeriodEnd.Format("2006-01-02"), snapshot.SnapshotDate.Format("2006-01-02"))
+	fmt.Fprintf(&b, "Gross salary: HR drafts %.2f, approved %.2f (variance %.2f%%).\n",
+		report.HRDraftTotals.GrossSalary, report.AccountantApprovedTotals.GrossSalary, report.VariancePercentage)
+
+	approved := report.AccountantApprovedTotals
+	checks := []struct {
+		account  string
+		recorded float64
+		expected float64
+	}{
+		{"431 CNAPS", report.GLRecordedAmounts.Account431CNAPS, approved.CNAPSEmployee + approved.CNAPSEmployer},
+		{"438 OSTIE", report.GLRecordedAmounts.Account438OSTIE, approved.OSTIEEmployee + approved.OSTIEEmployer},
+		{"437 IRSA", report.GLRecordedAmounts.Account437IRSA, approved.IRSAWithheld},
+	}
+	for _, check := range checks {
+		if !withinTolerance(check.recorded, check.expected) {
+			fmt.Fprintf(&b, "GL %s: recorded %.2f, approved %.2f.\n", check.account, check.recorded, check.expected)
+		}
+	}
+	return title, strings.TrimSpace(b.String())
+}
@@ -0,0 +1,71 @@
+package payroll
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reconciliation report statuses
+const (
+	ReconciliationStatusReconciled = "RECONCILED"
+	ReconciliationStatusVariance   = "VARIANCE DETECTED"
+)
+
+// reconciliationLookback bounds the open periods the daily job reconciles, so payroll paid before payment
+// batches existed, which is never marked paid, does not stay open forever
+const reconciliationLookback = 12 // months
+
+// ReconciliationSnapshot is a reconciliation report saved by the daily job, one per period and day
+type ReconciliationSnapshot struct {
+	ID                 uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PeriodStart        time.Time            `gorm:"type:date;not null" json:"period_start"`
+	PeriodEnd          time.Time            `gorm:"type:date;not null" json:"period_end"`
+	SnapshotDate       time.Time            `gorm:"type:date;not null" json:"snapshot_date"`
+	Status             string               `gorm:"type:varchar(20);not null" json:"status"`
+	VariancePercentage float64              `gorm:"type:numeric(15,4);not null" json:"variance_percentage"`
+	Report             ReconciliationReport `gorm:"type:jsonb;not null" json:"report"`
+	Alerted            bool                 `gorm:"not null;default:false" json:"alerted"`
+	AlertedUsers       int                  `gorm:"not null;default:0" json:"alerted_users"`
+	GeneratedAt        time.Time            `gorm:"not null" json:"generated_at"`
+}
+
+// ReconciliationHistoryQuery represents query parameters for listing reconciliation snapshots
+type ReconciliationHistoryQuery struct {
+	PeriodStart string `form:"period_start"`
+	PeriodEnd   string `form:"period_end"`
+	Status      string `form:"status" binding:"omitempty,oneof=reconciled variance_detected"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset      int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// payrollPeriod is a pay period with drafts
+type payrollPeriod struct {
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+}
+
+// Value stores the report as JSON
+func (r ReconciliationReport) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan reads a report stored as JSON
+func (r *ReconciliationReport) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, r)
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	default:
+		return fmt.Errorf("unsupported reconciliation report type %T", value)
+	}
+}
+
+// TableName specifies the table name for ReconciliationSnapshot model
+func (ReconciliationSnapshot) TableName() string {
+	return "payroll_reconciliation_snapshots"
+}
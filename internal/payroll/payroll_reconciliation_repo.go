@@ -0,0 +1,134 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-server/internal/auth"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetOpenPeriods lists the pay periods still open as of a date: periods with a draft not yet approved or an
+// approved net salary not yet paid, that ended within the reconciliation lookback
+func (r *Repo) GetOpenPeriods(ctx context.Context, asOf time.Time) ([]payrollPeriod, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var periods []payrollPeriod
+	if err := r.db.WithContext(ctx).Table("payroll_drafts d").
+		Select("DISTINCT d.period_start, d.period_end").
+		Joins("LEFT JOIN payroll_approved pa ON pa.draft_id = d.id").
+		Where("d.deleted_at IS NULL AND d.period_end >= ?", dateOnly(asOf).AddDate(0, -reconciliationLookback, 0)).
+		Where("d.status <> ? OR pa.paid_at IS NULL", StatusApproved).
+		Order("d.period_start ASC, d.period_end ASC").
+		Scan(&periods).Error; err != nil {
+		return nil, fmt.Errorf("get open payroll periods: %w", err)
+	}
+	return periods, nil
+}
+
+// SaveReconciliationSnapshot stores the snapshot of a period for its day and returns the period's previous
+// snapshot. created is false when the period already has a snapshot for that day, for instance one saved by
+// another API instance, and the snapshot is then not stored.
+func (r *Repo) SaveReconciliationSnapshot(ctx context.Context, snapshot *ReconciliationSnapshot) (created bool, previous *ReconciliationSnapshot, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(snapshot)
+	if result.Error != nil {
+		return false, nil, fmt.Errorf("save reconciliation snapshot: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil, nil
+	}
+
+	var earlier ReconciliationSnapshot
+	err = r.db.WithContext(ctx).
+		Where("period_start = ? AND period_end = ? AND snapshot_date < ?", snapshot.PeriodStart, snapshot.PeriodEnd, snapshot.SnapshotDate).
+		Order("snapshot_date DESC").First(&earlier).Error
+	if err == gorm.ErrRecordNotFound {
+		return true, nil, nil
+	}
+	if err != nil {
+		return true, nil, fmt.Errorf("get previous reconciliation snapshot: %w", err)
+	}
+	return true, &earlier, nil
+}
+
+// MarkSnapshotAlerted records that a snapshot's variance was sent to users
+func (r *Repo) MarkSnapshotAlerted(ctx context.Context, id uuid.UUID, users int) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := r.db.WithContext(ctx).Model(&ReconciliationSnapshot{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"alerted": true, "alerted_users": users}).Error; err != nil {
+		return fmt.Errorf("mark reconciliation snapshot alerted: %w", err)
+	}
+	return nil
+}
+
+// ListReconciliationSnapshots retrieves saved reconciliation snapshots, newest first
+func (r *Repo) ListReconciliationSnapshots(ctx context.Context, query ReconciliationHistoryQuery, periodStart, periodEnd *time.Time) ([]ReconciliationSnapshot, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Model(&ReconciliationSnapshot{})
+	if periodStart != nil {
+		db = db.Where("period_start >= ?", *periodStart)
+	}
+	if periodEnd != nil {
+		db = db.Where("period_end <= ?", *periodEnd)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", strings.ToUpper(strings.ReplaceAll(query.Status, "_", " ")))
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count reconciliation snapshots: %w", err)
+	}
+
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	var snapshots []ReconciliationSnapshot
+	if err := db.Order("snapshot_date DESC, period_start DESC").Limit(query.Limit).Offset(query.Offset).
+		Find(&snapshots).Error; err != nil {
+		return nil, 0, fmt.Errorf("list reconciliation snapshots: %w", err)
+	}
+	return snapshots, total, nil
+}
+
+// GetReconciliationSnapshotByID retrieves a saved reconciliation snapshot
+func (r *Repo) GetReconciliationSnapshotByID(ctx context.Context, id uuid.UUID) (*ReconciliationSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var snapshot ReconciliationSnapshot
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&snapshot).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("reconciliation snapshot not found")
+		}
+		return nil, fmt.Errorf("get reconciliation snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// getAlertRecipients retrieves the active admin and accountant users alerted of reconciliation variances
+func (r *Repo) getAlertRecipients(ctx context.Context) ([]auth.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var users []auth.User
+	if err := r.db.WithContext(ctx).Where("role IN ? AND is_active = ?", []string{"admin", "accountant"}, true).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("get reconciliation alert recipients: %w", err)
+	}
+	return users, nil
+}
@@ -3,16 +3,23 @@ package payroll
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
+	"go-server/internal/ledger"
+	"go-server/internal/taxrules"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repo handles database operations for payroll
 type Repo struct {
 	db         *gorm.DB
 	configRepo *ConfigRepo
+	taxRules   *taxrules.Repo
+	ledger     *ledger.Repo
 }
 
 // NewRepo creates a new payroll repository
@@ -20,23 +27,25 @@ func NewRepo(database *gorm.DB) *Repo {
 	return &Repo{
 		db:         database,
 		configRepo: NewConfigRepo(database),
+		taxRules:   taxrules.NewRepo(database),
+		ledger:     ledger.NewRepo(database),
 	}
 }
 
 // calculateCNAPS calculates CNAPS contributions using configurable rates from database
-func (r *Repo) calculateCNAPS(ctx context.Context, grossSalary float64) (base, employee, employer float64, err error) {
+func (r *Repo) calculateCNAPS(ctx context.Context, rules *ruleSet, grossSalary float64) (base, employee, employer float64, err error) {
 	// Get configuration values from database
-	ceiling, err := r.configRepo.GetConfigValueAsFloat(ctx, "cnaps_ostie_ceiling")
+	ceiling, err := rules.Float(ctx, "cnaps_ostie_ceiling")
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("get CNAPS ceiling: %w", err)
 	}
 
-	employeeRate, err := r.configRepo.GetConfigValueAsFloat(ctx, "cnaps_employee_rate")
+	employeeRate, err := rules.Float(ctx, "cnaps_employee_rate")
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("get CNAPS employee rate: %w", err)
 	}
 
-	employerRate, err := r.configRepo.GetConfigValueAsFloat(ctx, "cnaps_employer_rate")
+	employerRate, err := rules.Float(ctx, "cnaps_employer_rate")
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("get CNAPS employer rate: %w", err)
 	}
@@ -51,19 +60,19 @@ func (r *Repo) calculateCNAPS(ctx context.Context, grossSalary float64) (base, e
 }
 
 // calculateOSTIE calculates OSTIE contributions using configurable rates from database
-func (r *Repo) calculateOSTIE(ctx context.Context, grossSalary float64) (base, employee, employer float64, err error) {
+func (r *Repo) calculateOSTIE(ctx context.Context, rules *ruleSet, grossSalary float64) (base, employee, employer float64, err error) {
 	// Get configuration values from database
-	ceiling, err := r.configRepo.GetConfigValueAsFloat(ctx, "cnaps_ostie_ceiling")
+	ceiling, err := rules.Float(ctx, "cnaps_ostie_ceiling")
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("get CNAPS/OSTIE ceiling: %w", err)
 	}
 
-	employeeRate, err := r.configRepo.GetConfigValueAsFloat(ctx, "ostie_employee_rate")
+	employeeRate, err := rules.Float(ctx, "ostie_employee_rate")
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("get OSTIE employee rate: %w", err)
 	}
 
-	employerRate, err := r.configRepo.GetConfigValueAsFloat(ctx, "ostie_employer_rate")
+	employerRate, err := rules.Float(ctx, "ostie_employer_rate")
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("get OSTIE employer rate: %w", err)
 	}
@@ -77,74 +86,93 @@ func (r *Repo) calculateOSTIE(ctx context.Context, grossSalary float64) (base, e
 	return
 }
 
-// calculateIRSA calculates IRSA withholding based on configurable tax brackets from database
-func (r *Repo) calculateIRSA(ctx context.Context, grossSalary, cnapsEmployee, ostieEmployee float64) (amount float64, bracket string, err error) {
-	// Get IRSA tax brackets from database
-	brackets, err := r.configRepo.GetActiveIRSABrackets(ctx)
+// calculateIRSA calculates IRSA withholding on the progressive scale in force, with the dependent reduction and minimum tax
+func (r *Repo) calculateIRSA(ctx context.Context, rules *ruleSet, grossSalary, cnapsEmployee, ostieEmployee float64, dependents int) (*taxrules.IRSABreakdown, string, error) {
+	brackets, err := rules.IRSABrackets(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("get IRSA brackets: %w", err)
+	}
+
+	reduction, err := rules.Float(ctx, "irsa_dependent_reduction")
 	if err != nil {
-		return 0, "", fmt.Errorf("get IRSA brackets: %w", err)
+		return nil, "", fmt.Errorf("get IRSA dependent reduction: %w", err)
+	}
+
+	minimumTax, err := rules.Float(ctx, "irsa_min_tax")
+	if err != nil {
+		return nil, "", fmt.Errorf("get IRSA minimum tax: %w", err)
 	}
 
 	// Taxable income = gross - CNAPS employee - OSTIE employee
 	taxableIncome := grossSalary - cnapsEmployee - ostieEmployee
 
-	// Find the applicable bracket
-	for _, b := range brackets {
-		// Check if income falls within this bracket
-		inRange := taxableIncome >= b.MinIncome
-		if b.MaxIncome != nil {
-			inRange = inRange && taxableIncome <= *b.MaxIncome
-		}
+	return taxrules.ComputeIRSA(brackets, taxableIncome, dependents, reduction, minimumTax)
+}
 
-		if inRange {
-			amount = taxableIncome*b.TaxRate + b.MinTax
-			bracket = b.BracketName
-			return amount, bracket, nil
+// calculateDraft derives gross salary, contributions, IRSA and net salary from the base salary and line items.
+// Taxable earnings enter every base; non-taxable earnings and deductions only change the net.
+// The rule versions used are left in draft.RuleVersions for saveCalculatedDraft.
+func (r *Repo) calculateDraft(ctx context.Context, draft *PayrollDraft) error {
+	var taxableEarnings, nonTaxableEarnings, absences, deductions float64
+	for _, item := range draft.Items {
+		switch item.Type {
+		case LineItemTaxableEarning:
+			taxableEarnings += item.Amount
+		case LineItemAbsence:
+			absences += item.Amount
+		case LineItemNonTaxableEarning:
+			nonTaxableEarnings += item.Amount
+		default:
+			deductions += item.Amount
 		}
 	}
 
-	// If no bracket found, return error
-	return 0, "", fmt.Errorf("no applicable IRSA bracket found for income: %.2f", taxableIncome)
-}
-
-// CreateDraft creates a new payroll draft
-func (r *Repo) CreateDraft(ctx context.Context, draft *PayrollDraft) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	// Check if draft already exists for this employee and period
-	var existing PayrollDraft
-	err := r.db.WithContext(ctx).Where("employee_id = ? AND period_start = ? AND period_end = ? AND deleted_at IS NULL",
-		draft.EmployeeID, draft.PeriodStart, draft.PeriodEnd).First(&existing).Error
-	if err == nil {
-		return fmt.Errorf("payroll draft already exists for this employee and period")
+	// Rates and brackets are those in force at the end of the period, as is the configuration behind line items
+	rules := r.rulesAt(draft.PeriodEnd)
+	if err := recordLineItemRules(ctx, rules, draft.Items); err != nil {
+		return err
 	}
-	if err != gorm.ErrRecordNotFound {
-		return fmt.Errorf("check existing draft: %w", err)
+
+	grossSalary := draft.BaseSalary + taxableEarnings - absences
+	if grossSalary < 0 {
+		return fmt.Errorf("absence deductions of %.2f exceed the salary earned (%.2f)", absences, draft.BaseSalary+taxableEarnings)
 	}
 
 	// Calculate CNAPS
-	cnapsBase, cnapsEmployee, cnapsEmployer, err := r.calculateCNAPS(ctx, draft.GrossSalary)
+	cnapsBase, cnapsEmployee, cnapsEmployer, err := r.calculateCNAPS(ctx, rules, grossSalary)
 	if err != nil {
 		return fmt.Errorf("calculate CNAPS: %w", err)
 	}
 
 	// Calculate OSTIE
-	ostieBase, ostieEmployee, ostieEmployer, err := r.calculateOSTIE(ctx, draft.GrossSalary)
+	ostieBase, ostieEmployee, ostieEmployer, err := r.calculateOSTIE(ctx, rules, grossSalary)
 	if err != nil {
 		return fmt.Errorf("calculate OSTIE: %w", err)
 	}
 
-	// Calculate IRSA
-	irsa, irsaBracket, err := r.calculateIRSA(ctx, draft.GrossSalary, cnapsEmployee, ostieEmployee)
+	// Calculate IRSA with the employee's current dependents
+	emp, err := r.getDraftEmployee(ctx, draft.EmployeeID)
+	if err != nil {
+		return err
+	}
+	irsaBreakdown, irsaBracket, err := r.calculateIRSA(ctx, rules, grossSalary, cnapsEmployee, ostieEmployee, emp.Dependents)
 	if err != nil {
 		return fmt.Errorf("calculate IRSA: %w", err)
 	}
+	irsa := irsaBreakdown.Tax
 
 	// Calculate net salary
-	netSalary := draft.GrossSalary - cnapsEmployee - ostieEmployee - irsa
+	netSalary := grossSalary - cnapsEmployee - ostieEmployee - irsa + nonTaxableEarnings - deductions
+	if netSalary < 0 {
+		return fmt.Errorf("deductions of %.2f exceed the pay available (%.2f)", deductions, netSalary+deductions)
+	}
 
 	// Set calculated values
+	draft.GrossSalary = grossSalary
+	draft.TaxableEarnings = taxableEarnings
+	draft.NonTaxableEarnings = nonTaxableEarnings
+	draft.TotalDeductions = deductions
+	draft.AbsenceDeductions = absences
 	draft.CNAPSEmployee = cnapsEmployee
 	draft.CNAPSEmployer = cnapsEmployer
 	draft.CNAPSBase = cnapsBase
@@ -153,8 +181,43 @@ func (r *Repo) CreateDraft(ctx context.Context, draft *PayrollDraft) error {
 	draft.OSTIEBase = ostieBase
 	draft.IRSA = irsa
 	draft.IRSABracket = irsaBracket
+	draft.IRSABreakdown = irsaBreakdown
 	draft.NetSalary = netSalary
+	draft.RuleVersions = rules.used
+	return nil
+}
+
+// CreateDraft creates a new payroll draft
+func (r *Repo) CreateDraft(ctx context.Context, draft *PayrollDraft) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// Check if draft already exists for this employee and period
+	var existing PayrollDraft
+	err := r.db.WithContext(ctx).Where("employee_id = ? AND period_start = ? AND period_end = ? AND deleted_at IS NULL",
+		draft.EmployeeID, draft.PeriodStart, draft.PeriodEnd).First(&existing).Error
+	if err == nil {
+		return fmt.Errorf("payroll draft already exists for this employee and period")
+	}
+	if err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("check existing draft: %w", err)
+	}
 
+	if draft.BaseSalary == 0 {
+		draft.BaseSalary = draft.GrossSalary
+	}
+
+	systemItems, err := r.systemLineItems(ctx, draft)
+	if err != nil {
+		return err
+	}
+	draft.Items = append(draft.Items, systemItems...)
+
+	if err := r.calculateDraft(ctx, draft); err != nil {
+		return err
+	}
+
+	// Line items in draft.Items are inserted together with the draft
 	if err := r.db.WithContext(ctx).Create(draft).Error; err != nil {
 		return fmt.Errorf("create payroll draft: %w", err)
 	}
@@ -167,7 +230,8 @@ func (r *Repo) GetDraftByID(ctx context.Context, id uuid.UUID) (*PayrollDraft, e
 	defer cancel()
 
 	var draft PayrollDraft
-	if err := r.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&draft).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Items", orderLineItems).
+		Where("id = ? AND deleted_at IS NULL", id).First(&draft).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("payroll draft not found")
 		}
@@ -181,40 +245,54 @@ func (r *Repo) UpdateDraft(ctx context.Context, draft *PayrollDraft) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	// Recalculate if gross salary changed
-	if draft.GrossSalary > 0 {
-		cnapsBase, cnapsEmployee, cnapsEmployer, err := r.calculateCNAPS(ctx, draft.GrossSalary)
-		if err != nil {
-			return fmt.Errorf("calculate CNAPS: %w", err)
-		}
+	systemItems, err := r.systemLineItems(ctx, draft)
+	if err != nil {
+		return err
+	}
 
-		ostieBase, ostieEmployee, ostieEmployer, err := r.calculateOSTIE(ctx, draft.GrossSalary)
-		if err != nil {
-			return fmt.Errorf("calculate OSTIE: %w", err)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// System items are regenerated so they follow the latest salary and source data
+		if err := tx.Where("draft_id = ? AND source <> ?", draft.ID, LineItemSourceManual).
+			Delete(&PayrollLineItem{}).Error; err != nil {
+			return fmt.Errorf("delete system line items: %w", err)
 		}
-
-		irsa, irsaBracket, err := r.calculateIRSA(ctx, draft.GrossSalary, cnapsEmployee, ostieEmployee)
-		if err != nil {
-			return fmt.Errorf("calculate IRSA: %w", err)
+		for i := range systemItems {
+			systemItems[i].DraftID = draft.ID
+		}
+		if len(systemItems) > 0 {
+			if err := tx.Create(&systemItems).Error; err != nil {
+				return fmt.Errorf("create system line items: %w", err)
+			}
 		}
 
-		netSalary := draft.GrossSalary - cnapsEmployee - ostieEmployee - irsa
-
-		draft.CNAPSEmployee = cnapsEmployee
-		draft.CNAPSEmployer = cnapsEmployer
-		draft.CNAPSBase = cnapsBase
-		draft.OSTIEEmployee = ostieEmployee
-		draft.OSTIEEmployer = ostieEmployer
-		draft.OSTIEBase = ostieBase
-		draft.IRSA = irsa
-		draft.IRSABracket = irsaBracket
-		draft.NetSalary = netSalary
-	}
+		if err := orderLineItems(tx).Where("draft_id = ?", draft.ID).Find(&draft.Items).Error; err != nil {
+			return fmt.Errorf("list payroll line items: %w", err)
+		}
+		if err := r.calculateDraft(ctx, draft); err != nil {
+			return err
+		}
+		return saveCalculatedDraft(tx, draft)
+	})
+}
 
+// saveCalculatedDraft saves a recalculated draft and replaces the rule versions recorded for it
+func saveCalculatedDraft(tx *gorm.DB, draft *PayrollDraft) error {
 	draft.UpdatedAt = time.Now()
-	if err := r.db.WithContext(ctx).Save(draft).Error; err != nil {
+	if err := tx.Omit(clause.Associations).Save(draft).Error; err != nil {
 		return fmt.Errorf("update payroll draft: %w", err)
 	}
+
+	if err := tx.Where("draft_id = ?", draft.ID).Delete(&PayrollRuleVersion{}).Error; err != nil {
+		return fmt.Errorf("delete payroll rule versions: %w", err)
+	}
+	for i := range draft.RuleVersions {
+		draft.RuleVersions[i].DraftID = draft.ID
+	}
+	if len(draft.RuleVersions) > 0 {
+		if err := tx.Create(&draft.RuleVersions).Error; err != nil {
+			return fmt.Errorf("record payroll rule versions: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -253,6 +331,12 @@ func (r *Repo) ListDrafts(ctx context.Context, query PayrollDraftListQuery) ([]P
 	if query.EmployeeID != nil {
 		db = db.Where("employee_id = ?", *query.EmployeeID)
 	}
+	if query.RunID != nil {
+		db = db.Where("run_id = ?", *query.RunID)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
 
 	// Count total
 	if err := db.Count(&total).Error; err != nil {
@@ -285,22 +369,6 @@ func (r *Repo) GetDraftsByPeriod(ctx context.Context, periodStart, periodEnd tim
 	return drafts, nil
 }
 
-// CreateApproved creates a new approved payroll record
-func (r *Repo) CreateApproved(ctx context.Context, approved *PayrollApproved) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	// Generate fiche paie number if not provided
-	if approved.FichePaieNumber == "" {
-		approved.FichePaieNumber = generateFichePaieNumber(approved.ApprovedAt)
-	}
-
-	if err := r.db.WithContext(ctx).Create(approved).Error; err != nil {
-		return fmt.Errorf("create approved payroll: %w", err)
-	}
-	return nil
-}
-
 // GetApprovedByID retrieves an approved payroll by ID
 func (r *Repo) GetApprovedByID(ctx context.Context, id uuid.UUID) (*PayrollApproved, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -377,16 +445,6 @@ func (r *Repo) ListApproved(ctx context.Context, query PayrollApprovedListQuery)
 	return approved, total, nil
 }
 
-// generateFichePaieNumber generates a unique fiche de paie number
-func generateFichePaieNumber(approvedAt time.Time) string {
-	// Format: FDPAIE-YYYYMMDD-Random(4)
-	// This is a simple implementation. In production, you'd want to ensure uniqueness
-	year := approvedAt.Year()
-	month := int(approvedAt.Month())
-	day := approvedAt.Day()
-	return fmt.Sprintf("FDPAIE-%04d%02d%02d-%04d", year, month, day, 1000+int(approvedAt.Unix()%9000))
-}
-
 // GetReconciliationReport generates a reconciliation report for a period
 func (r *Repo) GetReconciliationReport(ctx context.Context, periodStart, periodEnd time.Time) (*ReconciliationReport, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -403,8 +461,13 @@ func (r *Repo) GetReconciliationReport(ctx context.Context, periodStart, periodE
 		return nil, fmt.Errorf("get drafts for reconciliation: %w", err)
 	}
 
-	// Calculate HR draft totals
+	// Calculate HR draft totals over the drafts HR submitted, approved or awaiting approval, so a submitted draft
+	// not yet approved shows as a variance; drafts HR is still preparing are counted apart
 	for _, draft := range drafts {
+		if draft.Status != StatusSubmitted && draft.Status != StatusApproved {
+			report.PendingDrafts++
+			continue
+		}
 		report.HRDraftTotals.GrossSalary += draft.GrossSalary
 		report.HRDraftTotals.CNAPSEmployee += draft.CNAPSEmployee
 		report.HRDraftTotals.CNAPSEmployer += draft.CNAPSEmployer
@@ -448,10 +511,14 @@ func (r *Repo) GetReconciliationReport(ctx context.Context, periodStart, periodE
 		report.AccountantApprovedTotals.NetPayable += draft.NetSalary
 	}
 
-	// Calculate GL recorded amounts from approved payrolls
-	report.GLRecordedAmounts.Account431CNAPS = report.AccountantApprovedTotals.CNAPSEmployee + report.AccountantApprovedTotals.CNAPSEmployer
-	report.GLRecordedAmounts.Account438OSTIE = report.AccountantApprovedTotals.OSTIEEmployee + report.AccountantApprovedTotals.OSTIEEmployer
-	report.GLRecordedAmounts.Account437IRSA = report.AccountantApprovedTotals.IRSAWithheld
+	// Read the GL recorded amounts from the payroll journal: the net credit of each liability account over the period
+	trialBalance, err := r.ledger.GetTrialBalance(ctx, &periodStart, &periodEnd, ledger.JournalPayroll)
+	if err != nil {
+		return nil, fmt.Errorf("get trial balance for reconciliation: %w", err)
+	}
+	report.GLRecordedAmounts.Account431CNAPS = -trialBalance.Account("431").Balance
+	report.GLRecordedAmounts.Account438OSTIE = -trialBalance.Account("438").Balance
+	report.GLRecordedAmounts.Account437IRSA = -trialBalance.Account("437").Balance
 
 	// Calculate variance
 	expectedTotal := report.HRDraftTotals.GrossSalary
@@ -460,12 +527,20 @@ func (r *Repo) GetReconciliationReport(ctx context.Context, periodStart, periodE
 		report.VariancePercentage = ((actualTotal - expectedTotal) / expectedTotal) * 100
 	}
 
-	// Determine status
-	if report.VariancePercentage <= 0.1 && report.VariancePercentage >= -0.1 {
-		report.Status = "RECONCILED"
+	// Determine status; the GL must also match the approved contributions
+	glMatches := withinTolerance(report.GLRecordedAmounts.Account431CNAPS, report.AccountantApprovedTotals.CNAPSEmployee+report.AccountantApprovedTotals.CNAPSEmployer) &&
+		withinTolerance(report.GLRecordedAmounts.Account438OSTIE, report.AccountantApprovedTotals.OSTIEEmployee+report.AccountantApprovedTotals.OSTIEEmployer) &&
+		withinTolerance(report.GLRecordedAmounts.Account437IRSA, report.AccountantApprovedTotals.IRSAWithheld)
+	if report.VariancePercentage <= 0.1 && report.VariancePercentage >= -0.1 && glMatches {
+		report.Status = ReconciliationStatusReconciled
 	} else {
-		report.Status = "VARIANCE DETECTED"
+		report.Status = ReconciliationStatusVariance
 	}
 
 	return report, nil
 }
+
+// withinTolerance reports whether a recorded amount is within the 0.1% variance threshold, or a cent, of the expected one
+func withinTolerance(recorded, expected float64) bool {
+	return math.Abs(recorded-expected) <= math.Max(math.Abs(expected)*0.001, 0.01)
+}
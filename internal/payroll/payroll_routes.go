@@ -2,6 +2,7 @@ package payroll
 
 import (
 	"go-server/internal/middleware"
+	"go-server/internal/notifications"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -10,12 +11,15 @@ import (
 // RegisterRoutes registers payroll routes
 func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
 	repo := NewRepo(gormDB)
-	handler := NewHandler(repo)
+	handler := NewHandler(repo, notifications.NewRepo(gormDB))
 
 	// Create config handler for payroll configuration
 	configRepo := NewConfigRepo(gormDB)
 	configHandler := NewConfigHandler(configRepo)
 
+	// Public payslip verification for banks and labour inspectors (no authentication)
+	rg.GET("/payroll/verify/:fiche_paie_number", handler.VerifyFichePaie)
+
 	payroll := rg.Group("/payroll")
 	payroll.Use(middleware.AuthMiddleware())
 	{
@@ -25,18 +29,51 @@ func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
 		payroll.GET("/drafts/:id", handler.GetDraftByID)
 		payroll.PUT("/drafts/:id", middleware.RequireRole("admin", "hr"), handler.UpdateDraft)
 		payroll.DELETE("/drafts/:id", middleware.RequireRole("admin", "hr"), handler.DeleteDraft)
+		payroll.PUT("/drafts/:id/submit", middleware.RequireRole("admin", "hr"), handler.SubmitDraft)
+		payroll.PUT("/drafts/:id/reopen", middleware.RequireRole("admin", "hr"), handler.ReopenDraft)
+		payroll.GET("/drafts/:id/history", handler.GetDraftHistory)
+		payroll.GET("/drafts/:id/rule-versions", middleware.RequireRole("admin", "hr", "accountant"), handler.GetDraftRuleVersions)
+		payroll.GET("/drafts/:id/items", handler.ListLineItems)
+		payroll.POST("/drafts/:id/items", middleware.RequireRole("admin", "hr"), handler.AddLineItem)
+		payroll.DELETE("/drafts/:id/items/:item_id", middleware.RequireRole("admin", "hr"), handler.DeleteLineItem)
+		payroll.GET("/drafts/:id/fiche-paie/preview", middleware.RequireRole("admin", "hr", "accountant"), handler.PreviewDraftPDF)
+
+		// Payroll Run Routes (HR/Admin create a batch of drafts for all active employees)
+		payroll.POST("/runs", middleware.RequireRole("admin", "hr"), handler.CreateRun)
+		payroll.GET("/runs", handler.ListRuns)
+		payroll.GET("/runs/:id", handler.GetRunByID)
+		payroll.GET("/runs/:id/history", handler.GetRunHistory)
+		payroll.PUT("/runs/:id/submit", middleware.RequireRole("admin", "hr"), handler.SubmitRun)
+		payroll.PUT("/runs/:id/reopen", middleware.RequireRole("admin", "hr"), handler.ReopenRun)
+
+		// Payroll Simulation (HR/Admin price salaries and raises without creating drafts)
+		payroll.POST("/simulate", middleware.RequireRole("admin", "hr"), handler.SimulatePayroll)
 
 		// Accountant Approval Routes (Accountant/Admin only)
 		payroll.PUT("/drafts/:id/approve", middleware.RequireRole("admin", "accountant"), handler.ApproveDraft)
+		payroll.PUT("/drafts/:id/reject", middleware.RequireRole("admin", "accountant"), handler.RejectDraft)
+		payroll.PUT("/runs/:id/approve", middleware.RequireRole("admin", "accountant"), handler.ApproveRun)
+		payroll.PUT("/runs/:id/reject", middleware.RequireRole("admin", "accountant"), handler.RejectRun)
 
 		// Approved Payroll Routes (Accountant/Admin only for write, authenticated for read)
 		payroll.GET("/approved", handler.ListApproved)
 		payroll.GET("/approved/:id", handler.GetApprovedByID)
 		payroll.GET("/approved/fiche/:fiche_paie_number", handler.GetApprovedByFichePaieNumber)
 		payroll.GET("/approved/:id/fiche-paie", handler.GenerateFichePaie)
+		payroll.GET("/approved/:id/fiche-paie/pdf", handler.GenerateFichePaiePDF)
+
+		// Salary Payment Batches (Accountant/Admin pay approved net salaries by bank transfer and mobile money)
+		payroll.POST("/payments", middleware.RequireRole("admin", "accountant"), handler.CreatePaymentBatch)
+		payroll.GET("/payments", middleware.RequireRole("admin", "accountant"), handler.ListPaymentBatches)
+		payroll.GET("/payments/:id", middleware.RequireRole("admin", "accountant"), handler.GetPaymentBatchByID)
+		payroll.GET("/payments/:id/file", middleware.RequireRole("admin", "accountant"), handler.DownloadPaymentFile)
+		payroll.PUT("/payments/:id/confirm", middleware.RequireRole("admin", "accountant"), handler.ConfirmPaymentBatch)
+		payroll.PUT("/payments/:id/cancel", middleware.RequireRole("admin", "accountant"), handler.CancelPaymentBatch)
 
 		// Reconciliation Report (Accountant/Admin only)
 		payroll.GET("/reconciliation", middleware.RequireRole("admin", "accountant"), handler.GetReconciliationReport)
+		payroll.GET("/reconciliation/history", middleware.RequireRole("admin", "accountant"), handler.GetReconciliationHistory)
+		payroll.GET("/reconciliation/history/:id", middleware.RequireRole("admin", "accountant"), handler.GetReconciliationSnapshot)
 	}
 
 	// Register payroll configuration routes
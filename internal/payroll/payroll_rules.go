@@ -0,0 +1,124 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-server/internal/taxrules"
+)
+
+// ruleSet resolves payroll configuration and IRSA brackets in force on one date
+// and records every version it resolves, so a calculation can be traced to its rules.
+// Resolved values are cached, so one rule set can price many salaries with a single lookup per rule.
+type ruleSet struct {
+	configRepo *ConfigRepo
+	taxRules   *taxrules.Repo
+	at         time.Time
+	used       []PayrollRuleVersion
+	seen       map[string]bool
+	values     map[string]float64
+	brackets   []taxrules.IRSATaxBracket
+}
+
+// rulesAt returns a rule set resolving versions in force on at
+func (r *Repo) rulesAt(at time.Time) *ruleSet {
+	return &ruleSet{configRepo: r.configRepo, taxRules: r.taxRules, at: at, seen: map[string]bool{}, values: map[string]float64{}}
+}
+
+// Float returns the numeric value of a configuration key in force on the rule set's date
+func (s *ruleSet) Float(ctx context.Context, key string) (float64, error) {
+	if value, ok := s.values[key]; ok {
+		return value, nil
+	}
+
+	config, err := s.configRepo.GetConfigAt(ctx, key, s.at)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseFloat(config.Value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("configuration key '%s' is not a number: %w", key, err)
+	}
+
+	s.record(PayrollRuleVersion{
+		RuleType:      RuleTypeConfiguration,
+		RuleID:        config.ID,
+		Name:          config.Key,
+		Value:         config.Value,
+		EffectiveFrom: config.EffectiveFrom,
+		EffectiveTo:   config.EffectiveTo,
+	})
+	s.values[key] = value
+	return value, nil
+}
+
+// IRSABrackets returns the IRSA brackets in force on the rule set's date
+func (s *ruleSet) IRSABrackets(ctx context.Context) ([]taxrules.IRSATaxBracket, error) {
+	if s.brackets != nil {
+		return s.brackets, nil
+	}
+
+	brackets, err := s.taxRules.GetIRSABracketsInForce(ctx, s.at)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range brackets {
+		maxIncome := "+"
+		if b.MaxIncome != nil {
+			maxIncome = fmt.Sprintf("%.2f", *b.MaxIncome)
+		}
+		s.record(PayrollRuleVersion{
+			RuleType:      RuleTypeIRSABracket,
+			RuleID:        b.ID,
+			Name:          b.BracketName,
+			Value:         fmt.Sprintf("%.2f-%s rate=%g min_tax=%.2f", b.MinIncome, maxIncome, b.TaxRate, b.MinTax),
+			EffectiveFrom: b.EffectiveDate,
+			EffectiveTo:   b.EffectiveTo,
+		})
+	}
+	s.brackets = brackets
+	return brackets, nil
+}
+
+// record adds a version to the rule set once
+func (s *ruleSet) record(version PayrollRuleVersion) {
+	if s.seen[version.RuleID.String()] {
+		return
+	}
+	s.seen[version.RuleID.String()] = true
+	s.used = append(s.used, version)
+}
+
+// GetDraftRuleVersions lists the rule versions recorded when a draft was last calculated.
+// Drafts calculated before versions were recorded have their versions resolved again from the period end.
+func (r *Repo) GetDraftRuleVersions(ctx context.Context, draft *PayrollDraft) (*PayrollRuleVersionsResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	response := &PayrollRuleVersionsResponse{
+		DraftID:    draft.ID,
+		ResolvedOn: draft.PeriodEnd,
+		Recorded:   true,
+	}
+	if err := r.db.WithContext(ctx).Where("draft_id = ?", draft.ID).
+		Order("rule_type ASC, name ASC").Find(&response.Versions).Error; err != nil {
+		return nil, fmt.Errorf("get payroll rule versions: %w", err)
+	}
+	if len(response.Versions) > 0 {
+		return response, nil
+	}
+
+	resolved := *draft
+	if err := r.calculateDraft(ctx, &resolved); err != nil {
+		return nil, fmt.Errorf("resolve payroll rule versions: %w", err)
+	}
+	for i := range resolved.RuleVersions {
+		resolved.RuleVersions[i].DraftID = draft.ID
+	}
+	response.Recorded = false
+	response.Versions = resolved.RuleVersions
+	return response, nil
+}
@@ -0,0 +1,96 @@
+package payroll
+
+import (
+	"net/http"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateRun handles a batch payroll run for all active employees (HR only)
+func (h *Handler) CreateRun(c *gin.Context) {
+	var input CreatePayrollRunRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "hr" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can create payroll runs"})
+		return
+	}
+
+	run := &PayrollRun{
+		PeriodStart: input.PeriodStart,
+		PeriodEnd:   input.PeriodEnd,
+		CreatedBy:   userID,
+	}
+
+	report, err := h.repo.CreateRun(c.Request.Context(), run)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// GetRunByID retrieves a payroll run with its drafts
+func (h *Handler) GetRunByID(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll run ID"})
+		return
+	}
+
+	run, err := h.repo.GetRunByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll run not found"})
+		return
+	}
+
+	drafts, err := h.repo.GetRunDrafts(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payroll run drafts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"run":    run,
+		"drafts": drafts,
+	})
+}
+
+// ListRuns retrieves payroll runs with filtering
+func (h *Handler) ListRuns(c *gin.Context) {
+	var query PayrollRunListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	runs, total, err := h.repo.ListRuns(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list payroll runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"runs":   runs,
+		"total":  total,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	})
+}
@@ -0,0 +1,69 @@
+package payroll
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayrollRun groups the drafts created for every active employee in one period
+type PayrollRun struct {
+	ID                 uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PeriodStart        time.Time `gorm:"type:date;not null" json:"period_start"`
+	PeriodEnd          time.Time `gorm:"type:date;not null" json:"period_end"`
+	Status             string    `gorm:"type:varchar(20);not null;default:'draft'" json:"status"`
+	TotalEmployees     int       `gorm:"not null;default:0" json:"total_employees"`
+	TotalGrossSalary   float64   `gorm:"type:numeric(15,2);not null;default:0" json:"total_gross_salary"`
+	TotalCNAPSEmployee float64   `gorm:"type:numeric(15,2);not null;default:0" json:"total_cnaps_employee"`
+	TotalCNAPSEmployer float64   `gorm:"type:numeric(15,2);not null;default:0" json:"total_cnaps_employer"`
+	TotalOSTIEEmployee float64   `gorm:"type:numeric(15,2);not null;default:0" json:"total_ostie_employee"`
+	TotalOSTIEEmployer float64   `gorm:"type:numeric(15,2);not null;default:0" json:"total_ostie_employer"`
+	TotalIRSA          float64   `gorm:"type:numeric(15,2);not null;default:0" json:"total_irsa"`
+	TotalNetSalary     float64   `gorm:"type:numeric(15,2);not null;default:0" json:"total_net_salary"`
+	CreatedBy          uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt          time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt          time.Time `gorm:"default:now()" json:"updated_at"`
+}
+
+// CreatePayrollRunRequest represents request to start a payroll run for a period
+type CreatePayrollRunRequest struct {
+	PeriodStart time.Time `json:"period_start" binding:"required"`
+	PeriodEnd   time.Time `json:"period_end" binding:"required"`
+}
+
+// PayrollRunListQuery represents query parameters for listing payroll runs
+type PayrollRunListQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=draft submitted approved rejected reopened"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// PayrollRunEmployeeResult reports the outcome of draft creation for one employee
+type PayrollRunEmployeeResult struct {
+	EmployeeID   uuid.UUID  `json:"employee_id"`
+	EmployeeName string     `json:"employee_name"`
+	Status       string     `json:"status"`
+	DraftID      *uuid.UUID `json:"draft_id,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// PayrollRunReport is returned after a payroll run has been executed
+type PayrollRunReport struct {
+	Run     *PayrollRun                `json:"run"`
+	Created int                        `json:"created"`
+	Skipped int                        `json:"skipped"`
+	Failed  int                        `json:"failed"`
+	Results []PayrollRunEmployeeResult `json:"results"`
+}
+
+// Payroll run employee result statuses
+const (
+	RunResultCreated = "created"
+	RunResultSkipped = "skipped"
+	RunResultFailed  = "failed"
+)
+
+// TableName specifies the table name for PayrollRun model
+func (PayrollRun) TableName() string {
+	return "payroll_runs"
+}
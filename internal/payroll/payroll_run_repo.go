@@ -0,0 +1,243 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-server/internal/employee"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateRun creates a payroll run and a draft for every employee employed in the period.
+// Employees who already have a draft for the period are skipped; failures for one
+// employee do not stop the run and are reported per employee.
+func (r *Repo) CreateRun(ctx context.Context, run *PayrollRun) (*PayrollRunReport, error) {
+	if !run.PeriodEnd.After(run.PeriodStart) {
+		return nil, fmt.Errorf("period_end must be after period_start")
+	}
+
+	if err := r.createRunRecord(ctx, run); err != nil {
+		return nil, err
+	}
+
+	employees, err := r.getPayrollEmployees(ctx, run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PayrollRunReport{Results: make([]PayrollRunEmployeeResult, 0, len(employees))}
+	for _, emp := range employees {
+		result := PayrollRunEmployeeResult{
+			EmployeeID:   emp.ID,
+			EmployeeName: emp.FirstName + " " + emp.LastName,
+		}
+
+		exists, err := r.draftExists(ctx, emp.ID, run.PeriodStart, run.PeriodEnd)
+		if err != nil {
+			result.Status = RunResultFailed
+			result.Error = err.Error()
+			report.Failed++
+			report.Results = append(report.Results, result)
+			continue
+		}
+		if exists {
+			result.Status = RunResultSkipped
+			result.Error = "payroll draft already exists for this employee and period"
+			report.Skipped++
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		draft := &PayrollDraft{
+			RunID:       &run.ID,
+			PeriodStart: run.PeriodStart,
+			PeriodEnd:   run.PeriodEnd,
+			EmployeeID:  emp.ID,
+			BaseSalary:  emp.GrossSalary,
+			CreatedBy:   run.CreatedBy,
+		}
+		if err := r.CreateDraft(ctx, draft); err != nil {
+			result.Status = RunResultFailed
+			result.Error = err.Error()
+			report.Failed++
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		result.Status = RunResultCreated
+		result.DraftID = &draft.ID
+		report.Created++
+		report.Results = append(report.Results, result)
+	}
+
+	if err := r.RefreshRunTotals(ctx, run); err != nil {
+		return nil, err
+	}
+	report.Run = run
+
+	return report, nil
+}
+
+// createRunRecord inserts the run after checking no run exists for the same period
+func (r *Repo) createRunRecord(ctx context.Context, run *PayrollRun) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var existing PayrollRun
+	err := r.db.WithContext(ctx).Where("period_start = ? AND period_end = ?", run.PeriodStart, run.PeriodEnd).
+		First(&existing).Error
+	if err == nil {
+		return fmt.Errorf("payroll run already exists for this period")
+	}
+	if err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("check existing payroll run: %w", err)
+	}
+
+	if run.Status == "" {
+		run.Status = "draft"
+	}
+	if err := r.db.WithContext(ctx).Create(run).Error; err != nil {
+		return fmt.Errorf("create payroll run: %w", err)
+	}
+	return nil
+}
+
+// getPayrollEmployees retrieves the employees eligible for a payroll run: active employees hired by the end
+// of the period, and terminated employees whose final pay falls in the period
+func (r *Repo) getPayrollEmployees(ctx context.Context, periodStart, periodEnd time.Time) ([]employee.Employee, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var employees []employee.Employee
+	if err := r.db.WithContext(ctx).
+		Where("hire_date <= ? AND (termination_date IS NULL OR termination_date >= ?)", periodEnd, periodStart).
+		Where("status = ? OR (status = ? AND termination_date IS NOT NULL)", "active", "terminated").
+		Order("last_name ASC, first_name ASC").Find(&employees).Error; err != nil {
+		return nil, fmt.Errorf("get payroll employees: %w", err)
+	}
+	return employees, nil
+}
+
+// draftExists checks whether an employee already has a draft for the period
+func (r *Repo) draftExists(ctx context.Context, employeeID uuid.UUID, periodStart, periodEnd time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&PayrollDraft{}).
+		Where("employee_id = ? AND period_start = ? AND period_end = ? AND deleted_at IS NULL",
+			employeeID, periodStart, periodEnd).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("check existing draft: %w", err)
+	}
+	return count > 0, nil
+}
+
+// RefreshRunTotals recomputes the aggregate totals of a run from its drafts
+func (r *Repo) RefreshRunTotals(ctx context.Context, run *PayrollRun) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var totals struct {
+		TotalEmployees     int
+		TotalGrossSalary   float64
+		TotalCNAPSEmployee float64
+		TotalCNAPSEmployer float64
+		TotalOSTIEEmployee float64
+		TotalOSTIEEmployer float64
+		TotalIRSA          float64
+		TotalNetSalary     float64
+	}
+	if err := r.db.WithContext(ctx).Model(&PayrollDraft{}).
+		Where("run_id = ? AND deleted_at IS NULL", run.ID).
+		Select(`COUNT(*) AS total_employees,
+			COALESCE(SUM(gross_salary), 0) AS total_gross_salary,
+			COALESCE(SUM(cnaps_employee), 0) AS total_cnaps_employee,
+			COALESCE(SUM(cnaps_employer), 0) AS total_cnaps_employer,
+			COALESCE(SUM(ostie_employee), 0) AS total_ostie_employee,
+			COALESCE(SUM(ostie_employer), 0) AS total_ostie_employer,
+			COALESCE(SUM(irsa), 0) AS total_irsa,
+			COALESCE(SUM(net_salary), 0) AS total_net_salary`).
+		Scan(&totals).Error; err != nil {
+		return fmt.Errorf("sum payroll run totals: %w", err)
+	}
+
+	run.TotalEmployees = totals.TotalEmployees
+	run.TotalGrossSalary = totals.TotalGrossSalary
+	run.TotalCNAPSEmployee = totals.TotalCNAPSEmployee
+	run.TotalCNAPSEmployer = totals.TotalCNAPSEmployer
+	run.TotalOSTIEEmployee = totals.TotalOSTIEEmployee
+	run.TotalOSTIEEmployer = totals.TotalOSTIEEmployer
+	run.TotalIRSA = totals.TotalIRSA
+	run.TotalNetSalary = totals.TotalNetSalary
+	run.UpdatedAt = time.Now()
+
+	if err := r.db.WithContext(ctx).Save(run).Error; err != nil {
+		return fmt.Errorf("update payroll run totals: %w", err)
+	}
+	return nil
+}
+
+// GetRunByID retrieves a payroll run by ID
+func (r *Repo) GetRunByID(ctx context.Context, id uuid.UUID) (*PayrollRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var run PayrollRun
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&run).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("payroll run not found")
+		}
+		return nil, fmt.Errorf("get payroll run: %w", err)
+	}
+	return &run, nil
+}
+
+// GetRunDrafts retrieves all drafts belonging to a payroll run
+func (r *Repo) GetRunDrafts(ctx context.Context, runID uuid.UUID) ([]PayrollDraft, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var drafts []PayrollDraft
+	if err := r.db.WithContext(ctx).Where("run_id = ? AND deleted_at IS NULL", runID).
+		Order("created_at ASC").Find(&drafts).Error; err != nil {
+		return nil, fmt.Errorf("get payroll run drafts: %w", err)
+	}
+	return drafts, nil
+}
+
+// ListRuns retrieves payroll runs with filtering
+func (r *Repo) ListRuns(ctx context.Context, query PayrollRunListQuery) ([]PayrollRun, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var runs []PayrollRun
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&PayrollRun{})
+
+	// Apply filters
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	// Count total
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count payroll runs: %w", err)
+	}
+
+	// Apply pagination
+	limit := query.Limit
+	if limit == 0 {
+		limit = 50
+	}
+
+	if err := db.Limit(limit).Offset(query.Offset).Order("period_start DESC").Find(&runs).Error; err != nil {
+		return nil, 0, fmt.Errorf("list payroll runs: %w", err)
+	}
+
+	return runs, total, nil
+}
@@ -0,0 +1,78 @@
+package payroll
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-server/internal/signing"
+
+	"github.com/google/uuid"
+)
+
+// SignatureAlgorithmEd25519 identifies payslips signed with the server Ed25519 key
+const SignatureAlgorithmEd25519 = signing.AlgorithmEd25519
+
+// signaturePayloadVersion is bumped whenever the canonical payload layout changes
+const signaturePayloadVersion = "v1"
+
+// canonicalPayslipPayload serializes the signed payslip fields in a fixed order and format
+func canonicalPayslipPayload(fichePaieNumber string, draft *PayrollDraft, accountantID uuid.UUID, approvedAt time.Time) []byte {
+	fields := []string{
+		"version=" + signaturePayloadVersion,
+		"fiche_paie_number=" + fichePaieNumber,
+		"draft_id=" + draft.ID.String(),
+		"employee_id=" + draft.EmployeeID.String(),
+		"period_start=" + draft.PeriodStart.Format("2006-01-02"),
+		"period_end=" + draft.PeriodEnd.Format("2006-01-02"),
+		fmt.Sprintf("gross_salary=%.2f", draft.GrossSalary),
+		fmt.Sprintf("cnaps_employee=%.2f", draft.CNAPSEmployee),
+		fmt.Sprintf("cnaps_employer=%.2f", draft.CNAPSEmployer),
+		fmt.Sprintf("ostie_employee=%.2f", draft.OSTIEEmployee),
+		fmt.Sprintf("ostie_employer=%.2f", draft.OSTIEEmployer),
+		fmt.Sprintf("irsa=%.2f", draft.IRSA),
+		fmt.Sprintf("net_salary=%.2f", draft.NetSalary),
+		"accountant_id=" + accountantID.String(),
+		"approved_at=" + approvedAt.UTC().Format(time.RFC3339),
+	}
+	// Line items are only appended when present, so payslips without them keep the original payload
+	for _, item := range draft.Items {
+		fields = append(fields, fmt.Sprintf("item=%s|%s|%s|%.2f", item.Type, item.Code, item.Label, item.Amount))
+	}
+	return []byte(strings.Join(fields, "\n"))
+}
+
+// signPayslip signs the canonical payslip payload, returning the base64 signature and key ID
+func signPayslip(fichePaieNumber string, draft *PayrollDraft, accountantID uuid.UUID, approvedAt time.Time) (signature, keyID string, err error) {
+	s, err := signing.Default()
+	if err != nil {
+		return "", "", err
+	}
+
+	signature, keyID = s.Sign(canonicalPayslipPayload(fichePaieNumber, draft, accountantID, approvedAt))
+	return signature, keyID, nil
+}
+
+// verifyPayslipSignature checks an approved payslip's stored signature against its draft
+func verifyPayslipSignature(approved *PayrollApproved, draft *PayrollDraft) (bool, string) {
+	if approved.SignatureAlgorithm != SignatureAlgorithmEd25519 {
+		return false, "payslip was approved before cryptographic signing was enabled"
+	}
+
+	s, err := signing.Default()
+	if err != nil {
+		return false, "signing key is not configured"
+	}
+
+	payload := canonicalPayslipPayload(approved.FichePaieNumber, draft, approved.AccountantID, approved.ApprovedAt)
+	switch err := s.Verify(approved.SignatureKeyID, payload, approved.DigitalSignature); err {
+	case nil:
+		return true, ""
+	case signing.ErrUnknownKey:
+		return false, "payslip was signed with an unknown key"
+	case signing.ErrMalformedSignature:
+		return false, "signature is malformed"
+	default:
+		return false, "payslip data does not match its signature"
+	}
+}
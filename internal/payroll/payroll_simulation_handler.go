@@ -0,0 +1,43 @@
+package payroll
+
+import (
+	"net/http"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SimulatePayroll calculates pay for a gross salary, a target net salary or a raise over a batch of employees
+// without creating drafts (HR only)
+func (h *Handler) SimulatePayroll(c *gin.Context) {
+	var input PayrollSimulationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Verify HR role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "hr" && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only HR can simulate payroll"})
+		return
+	}
+
+	if input.GrossSalary != nil && input.TargetNetSalary != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide either gross_salary or target_net_salary, not both"})
+		return
+	}
+	if input.GrossSalary == nil && input.TargetNetSalary == nil && len(input.Employees) == 0 && input.Department == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide gross_salary, target_net_salary, employees or department"})
+		return
+	}
+
+	response, err := h.repo.Simulate(c.Request.Context(), &input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,83 @@
+package payroll
+
+import (
+	"time"
+
+	"go-server/internal/taxrules"
+
+	"github.com/google/uuid"
+)
+
+// PayrollSimulationRequest represents a stateless payroll calculation. Give gross_salary to compute the net,
+// target_net_salary to solve for the gross giving that net, and employees or department to price a raise.
+type PayrollSimulationRequest struct {
+	AsOf            *time.Time                  `json:"as_of,omitempty"`
+	GrossSalary     *float64                    `json:"gross_salary,omitempty" binding:"omitempty,gt=0"`
+	TargetNetSalary *float64                    `json:"target_net_salary,omitempty" binding:"omitempty,gt=0"`
+	Dependents      int                         `json:"dependents,omitempty" binding:"omitempty,min=0"`
+	Employees       []PayrollSimulationEmployee `json:"employees,omitempty" binding:"omitempty,max=1000,dive"`
+	Department      string                      `json:"department,omitempty" binding:"omitempty,max=100"`
+	RaisePercent    *float64                    `json:"raise_percent,omitempty" binding:"omitempty,gt=-100"`
+	RaiseAmount     *float64                    `json:"raise_amount,omitempty"`
+}
+
+// PayrollSimulationEmployee selects an employee for a raise scenario. GrossSalary replaces the salary outright;
+// otherwise the raise given here, or else the request's raise, is applied to the current salary.
+type PayrollSimulationEmployee struct {
+	EmployeeID   uuid.UUID `json:"employee_id" binding:"required"`
+	GrossSalary  *float64  `json:"gross_salary,omitempty" binding:"omitempty,gt=0"`
+	RaisePercent *float64  `json:"raise_percent,omitempty" binding:"omitempty,gt=-100"`
+	RaiseAmount  *float64  `json:"raise_amount,omitempty"`
+}
+
+// PayrollSimulationResult is the calculation of one monthly salary; nothing is stored
+type PayrollSimulationResult struct {
+	GrossSalary     float64                 `json:"gross_salary"`
+	TargetNetSalary *float64                `json:"target_net_salary,omitempty"`
+	Dependents      int                     `json:"dependents"`
+	CNAPSBase       float64                 `json:"cnaps_base"`
+	CNAPSEmployee   float64                 `json:"cnaps_employee"`
+	CNAPSEmployer   float64                 `json:"cnaps_employer"`
+	OSTIEBase       float64                 `json:"ostie_base"`
+	OSTIEEmployee   float64                 `json:"ostie_employee"`
+	OSTIEEmployer   float64                 `json:"ostie_employer"`
+	IRSA            float64                 `json:"irsa"`
+	IRSABracket     string                  `json:"irsa_bracket"`
+	IRSABreakdown   *taxrules.IRSABreakdown `json:"irsa_breakdown"`
+	NetSalary       float64                 `json:"net_salary"`
+	EmployerCost    float64                 `json:"employer_cost"`
+}
+
+// PayrollSimulationEmployeeResult compares an employee's current pay with the simulated one
+type PayrollSimulationEmployeeResult struct {
+	EmployeeID           uuid.UUID                `json:"employee_id"`
+	EmployeeName         string                   `json:"employee_name"`
+	Department           string                   `json:"department,omitempty"`
+	Current              *PayrollSimulationResult `json:"current,omitempty"`
+	Simulated            *PayrollSimulationResult `json:"simulated,omitempty"`
+	GrossIncrease        float64                  `json:"gross_increase"`
+	NetIncrease          float64                  `json:"net_increase"`
+	EmployerCostIncrease float64                  `json:"employer_cost_increase"`
+	Error                string                   `json:"error,omitempty"`
+}
+
+// PayrollSimulationBatch totals a raise scenario over a batch of employees; failed employees are left out of the totals
+type PayrollSimulationBatch struct {
+	Simulated                  int                               `json:"simulated"`
+	Failed                     int                               `json:"failed"`
+	TotalCurrentGross          float64                           `json:"total_current_gross"`
+	TotalSimulatedGross        float64                           `json:"total_simulated_gross"`
+	TotalCurrentNet            float64                           `json:"total_current_net"`
+	TotalSimulatedNet          float64                           `json:"total_simulated_net"`
+	TotalCurrentEmployerCost   float64                           `json:"total_current_employer_cost"`
+	TotalSimulatedEmployerCost float64                           `json:"total_simulated_employer_cost"`
+	TotalEmployerCostIncrease  float64                           `json:"total_employer_cost_increase"`
+	Employees                  []PayrollSimulationEmployeeResult `json:"employees"`
+}
+
+// PayrollSimulationResponse is returned by a payroll simulation
+type PayrollSimulationResponse struct {
+	RulesAsOf time.Time                `json:"rules_as_of"`
+	Scenario  *PayrollSimulationResult `json:"scenario,omitempty"`
+	Batch     *PayrollSimulationBatch  `json:"batch,omitempty"`
+}
@@ -0,0 +1,242 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go-server/internal/employee"
+
+	"github.com/google/uuid"
+)
+
+// Limits of the net-to-gross solve: the search stops once the gross is known to the cent
+const (
+	simulationPrecision     = 0.005
+	simulationMaxIterations = 100
+)
+
+// Simulate prices the scenarios of a simulation request with the rules in force on its date.
+// Nothing is written: drafts, line items and rule versions are left untouched.
+func (r *Repo) Simulate(ctx context.Context, input *PayrollSimulationRequest) (*PayrollSimulationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	asOf := dateOnly(time.Now())
+	if input.AsOf != nil {
+		asOf = dateOnly(*input.AsOf)
+	}
+	rules := r.rulesAt(asOf)
+	response := &PayrollSimulationResponse{RulesAsOf: asOf}
+
+	switch {
+	case input.GrossSalary != nil:
+		result, err := r.simulateGross(ctx, rules, *input.GrossSalary, input.Dependents)
+		if err != nil {
+			return nil, err
+		}
+		response.Scenario = result
+	case input.TargetNetSalary != nil:
+		result, err := r.simulateNet(ctx, rules, *input.TargetNetSalary, input.Dependents)
+		if err != nil {
+			return nil, err
+		}
+		response.Scenario = result
+	}
+
+	if len(input.Employees) > 0 || input.Department != "" {
+		batch, err := r.simulateBatch(ctx, rules, input)
+		if err != nil {
+			return nil, err
+		}
+		response.Batch = batch
+	}
+
+	return response, nil
+}
+
+// simulateGross calculates contributions, IRSA, net salary and employer cost for a gross salary
+func (r *Repo) simulateGross(ctx context.Context, rules *ruleSet, grossSalary float64, dependents int) (*PayrollSimulationResult, error) {
+	cnapsBase, cnapsEmployee, cnapsEmployer, err := r.calculateCNAPS(ctx, rules, grossSalary)
+	if err != nil {
+		return nil, fmt.Errorf("calculate CNAPS: %w", err)
+	}
+
+	ostieBase, ostieEmployee, ostieEmployer, err := r.calculateOSTIE(ctx, rules, grossSalary)
+	if err != nil {
+		return nil, fmt.Errorf("calculate OSTIE: %w", err)
+	}
+
+	irsaBreakdown, irsaBracket, err := r.calculateIRSA(ctx, rules, grossSalary, cnapsEmployee, ostieEmployee, dependents)
+	if err != nil {
+		return nil, fmt.Errorf("calculate IRSA: %w", err)
+	}
+
+	return &PayrollSimulationResult{
+		GrossSalary:   grossSalary,
+		Dependents:    dependents,
+		CNAPSBase:     cnapsBase,
+		CNAPSEmployee: cnapsEmployee,
+		CNAPSEmployer: cnapsEmployer,
+		OSTIEBase:     ostieBase,
+		OSTIEEmployee: ostieEmployee,
+		OSTIEEmployer: ostieEmployer,
+		IRSA:          irsaBreakdown.Tax,
+		IRSABracket:   irsaBracket,
+		IRSABreakdown: irsaBreakdown,
+		NetSalary:     grossSalary - cnapsEmployee - ostieEmployee - irsaBreakdown.Tax,
+		EmployerCost:  grossSalary + cnapsEmployer + ostieEmployer,
+	}, nil
+}
+
+// simulateNet solves for the lowest gross salary, to the cent, whose net salary reaches the target.
+// Net never exceeds gross, so the search starts at the target and doubles the upper bound until it is reached.
+func (r *Repo) simulateNet(ctx context.Context, rules *ruleSet, targetNet float64, dependents int) (*PayrollSimulationResult, error) {
+	netAt := func(gross float64) (float64, error) {
+		result, err := r.simulateGross(ctx, rules, gross, dependents)
+		if err != nil {
+			return 0, err
+		}
+		return result.NetSalary, nil
+	}
+
+	low, high := targetNet, targetNet*2
+	for i := 0; ; i++ {
+		net, err := netAt(high)
+		if err != nil {
+			return nil, err
+		}
+		if net >= targetNet {
+			break
+		}
+		if i == simulationMaxIterations {
+			return nil, fmt.Errorf("no gross salary gives a net salary of %.2f", targetNet)
+		}
+		low, high = high, high*2
+	}
+
+	for i := 0; i < simulationMaxIterations && high-low > simulationPrecision; i++ {
+		mid := (low + high) / 2
+		net, err := netAt(mid)
+		if err != nil {
+			return nil, err
+		}
+		if net >= targetNet {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+
+	// Round up so the rounded gross still reaches the target
+	gross := math.Ceil(high*100) / 100
+	result, err := r.simulateGross(ctx, rules, gross, dependents)
+	if err != nil {
+		return nil, err
+	}
+	result.TargetNetSalary = &targetNet
+	return result, nil
+}
+
+// simulateBatch compares current and raised pay for the requested employees and those of the requested department
+func (r *Repo) simulateBatch(ctx context.Context, rules *ruleSet, input *PayrollSimulationRequest) (*PayrollSimulationBatch, error) {
+	scenarios := make([]PayrollSimulationEmployee, 0, len(input.Employees))
+	listed := make(map[uuid.UUID]bool, len(input.Employees))
+	for _, e := range input.Employees {
+		if listed[e.EmployeeID] {
+			continue
+		}
+		listed[e.EmployeeID] = true
+		scenarios = append(scenarios, e)
+	}
+
+	if input.Department != "" {
+		var members []employee.Employee
+		if err := r.db.WithContext(ctx).Where("department = ? AND status <> ?", input.Department, "terminated").
+			Order("last_name ASC, first_name ASC").Find(&members).Error; err != nil {
+			return nil, fmt.Errorf("get department employees: %w", err)
+		}
+		for _, m := range members {
+			if !listed[m.ID] {
+				listed[m.ID] = true
+				scenarios = append(scenarios, PayrollSimulationEmployee{EmployeeID: m.ID})
+			}
+		}
+	}
+
+	batch := &PayrollSimulationBatch{Employees: make([]PayrollSimulationEmployeeResult, 0, len(scenarios))}
+	for _, scenario := range scenarios {
+		result := r.simulateEmployee(ctx, rules, input, scenario)
+		if result.Error != "" {
+			batch.Failed++
+			batch.Employees = append(batch.Employees, result)
+			continue
+		}
+
+		batch.Simulated++
+		batch.TotalCurrentGross += result.Current.GrossSalary
+		batch.TotalSimulatedGross += result.Simulated.GrossSalary
+		batch.TotalCurrentNet += result.Current.NetSalary
+		batch.TotalSimulatedNet += result.Simulated.NetSalary
+		batch.TotalCurrentEmployerCost += result.Current.EmployerCost
+		batch.TotalSimulatedEmployerCost += result.Simulated.EmployerCost
+		batch.Employees = append(batch.Employees, result)
+	}
+	batch.TotalEmployerCostIncrease = batch.TotalSimulatedEmployerCost - batch.TotalCurrentEmployerCost
+
+	return batch, nil
+}
+
+// simulateEmployee prices one employee's current salary and the salary after the scenario's raise
+func (r *Repo) simulateEmployee(ctx context.Context, rules *ruleSet, input *PayrollSimulationRequest, scenario PayrollSimulationEmployee) PayrollSimulationEmployeeResult {
+	result := PayrollSimulationEmployeeResult{EmployeeID: scenario.EmployeeID}
+
+	var emp employee.Employee
+	if err := r.db.WithContext(ctx).Where("id = ?", scenario.EmployeeID).First(&emp).Error; err != nil {
+		result.Error = "employee not found"
+		return result
+	}
+	result.EmployeeName = emp.FirstName + " " + emp.LastName
+	result.Department = emp.Department
+
+	current, err := r.simulateGross(ctx, rules, emp.GrossSalary, emp.Dependents)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	newGross := emp.GrossSalary
+	if scenario.GrossSalary != nil {
+		newGross = *scenario.GrossSalary
+	} else {
+		raisePercent, raiseAmount := input.RaisePercent, input.RaiseAmount
+		if scenario.RaisePercent != nil || scenario.RaiseAmount != nil {
+			raisePercent, raiseAmount = scenario.RaisePercent, scenario.RaiseAmount
+		}
+		if raisePercent != nil {
+			newGross += emp.GrossSalary * *raisePercent / 100
+		}
+		if raiseAmount != nil {
+			newGross += *raiseAmount
+		}
+		newGross = math.Round(newGross*100) / 100
+	}
+	if newGross <= 0 {
+		result.Error = fmt.Sprintf("simulated gross salary of %.2f must be positive", newGross)
+		return result
+	}
+
+	simulated, err := r.simulateGross(ctx, rules, newGross, emp.Dependents)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Current = current
+	result.Simulated = simulated
+	result.GrossIncrease = simulated.GrossSalary - current.GrossSalary
+	result.NetIncrease = simulated.NetSalary - current.NetSalary
+	result.EmployerCostIncrease = simulated.EmployerCost - current.EmployerCost
+	return result
+}
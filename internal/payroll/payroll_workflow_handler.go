@@ -0,0 +1,247 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SubmitDraft handles submission of a payroll draft for accountant review (HR only)
+func (h *Handler) SubmitDraft(c *gin.Context) {
+	var input SubmitPayrollRequest
+	if !bindOptionalJSON(c, &input) {
+		return
+	}
+	h.transitionDraft(c, StatusSubmitted, input.Comment, []string{"hr", "admin"}, "Only HR can submit payroll drafts")
+}
+
+// RejectDraft handles rejection of a submitted payroll draft (Accountant only)
+func (h *Handler) RejectDraft(c *gin.Context) {
+	var input RejectPayrollRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.transitionDraft(c, StatusRejected, input.Reason, []string{"accountant", "admin"}, "Only Accountant can reject payroll drafts")
+}
+
+// ReopenDraft handles reopening of a rejected payroll draft for correction (HR only)
+func (h *Handler) ReopenDraft(c *gin.Context) {
+	var input ReopenPayrollRequest
+	if !bindOptionalJSON(c, &input) {
+		return
+	}
+	h.transitionDraft(c, StatusReopened, input.Comment, []string{"hr", "admin"}, "Only HR can reopen payroll drafts")
+}
+
+// GetDraftHistory retrieves the approval workflow history of a payroll draft
+func (h *Handler) GetDraftHistory(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+
+	if _, err := h.repo.GetDraftByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll draft not found"})
+		return
+	}
+
+	history, err := h.repo.GetStatusHistory(c.Request.Context(), &id, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payroll draft history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+// SubmitRun handles submission of every editable draft in a payroll run (HR only)
+func (h *Handler) SubmitRun(c *gin.Context) {
+	var input SubmitPayrollRequest
+	if !bindOptionalJSON(c, &input) {
+		return
+	}
+	h.transitionRun(c, StatusSubmitted, input.Comment, []string{"hr", "admin"}, "Only HR can submit payroll runs")
+}
+
+// ApproveRun handles approval of every submitted draft in a payroll run (Accountant only)
+func (h *Handler) ApproveRun(c *gin.Context) {
+	var input ApprovePayrollDraftRequest
+	if !bindOptionalJSON(c, &input) {
+		return
+	}
+	h.transitionRun(c, StatusApproved, input.Comment, []string{"accountant", "admin"}, "Only Accountant can approve payroll runs")
+}
+
+// RejectRun handles rejection of a submitted payroll run (Accountant only)
+func (h *Handler) RejectRun(c *gin.Context) {
+	var input RejectPayrollRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.transitionRun(c, StatusRejected, input.Reason, []string{"accountant", "admin"}, "Only Accountant can reject payroll runs")
+}
+
+// ReopenRun handles reopening of a rejected payroll run for correction (HR only)
+func (h *Handler) ReopenRun(c *gin.Context) {
+	var input ReopenPayrollRequest
+	if !bindOptionalJSON(c, &input) {
+		return
+	}
+	h.transitionRun(c, StatusReopened, input.Comment, []string{"hr", "admin"}, "Only HR can reopen payroll runs")
+}
+
+// GetRunHistory retrieves the approval workflow history of a payroll run
+func (h *Handler) GetRunHistory(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid run ID"})
+		return
+	}
+
+	if _, err := h.repo.GetRunByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll run not found"})
+		return
+	}
+
+	history, err := h.repo.GetStatusHistory(c.Request.Context(), nil, &id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payroll run history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+// transitionDraft applies a workflow status change to a single draft and notifies its creator
+func (h *Handler) transitionDraft(c *gin.Context, toStatus, comment string, roles []string, forbiddenMsg string) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft ID"})
+		return
+	}
+
+	userID, ok := requireWorkflowRole(c, roles, forbiddenMsg)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.GetDraftByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll draft not found"})
+		return
+	}
+
+	draft, err := h.repo.TransitionDraft(c.Request.Context(), id, toStatus, userID, comment)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.notifyStatusChange(c.Request.Context(), draft.CreatedBy, "Payroll draft", toStatus, comment, "/payroll/drafts/"+draft.ID.String())
+
+	c.JSON(http.StatusOK, draft)
+}
+
+// transitionRun applies a workflow status change to a run and its drafts and notifies its creator
+func (h *Handler) transitionRun(c *gin.Context, toStatus, comment string, roles []string, forbiddenMsg string) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid run ID"})
+		return
+	}
+
+	userID, ok := requireWorkflowRole(c, roles, forbiddenMsg)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.GetRunByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll run not found"})
+		return
+	}
+
+	run, err := h.repo.TransitionRun(c.Request.Context(), id, toStatus, userID, comment)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.notifyStatusChange(c.Request.Context(), run.CreatedBy, "Payroll run", toStatus, comment, "/payroll/runs/"+run.ID.String())
+
+	drafts, err := h.repo.GetRunDrafts(c.Request.Context(), run.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payroll run drafts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"run":    run,
+		"drafts": drafts,
+	})
+}
+
+// notifyStatusChange tells the HR user who prepared the payroll about a workflow decision.
+// Notification failures are not fatal to the status change itself.
+func (h *Handler) notifyStatusChange(ctx context.Context, userID uuid.UUID, subject, toStatus, comment, link string) {
+	if h.notificationsRepo == nil {
+		return
+	}
+
+	notificationType := "info"
+	switch toStatus {
+	case StatusApproved:
+		notificationType = "success"
+	case StatusRejected:
+		notificationType = "warning"
+	}
+
+	title := fmt.Sprintf("%s %s", subject, toStatus)
+	message := fmt.Sprintf("%s has been %s.", subject, toStatus)
+	if comment != "" {
+		message += " Comment: " + comment
+	}
+
+	_ = h.notificationsRepo.CreateForUser(ctx, userID, title, message, notificationType, &link)
+}
+
+// requireWorkflowRole verifies the caller holds one of the roles allowed to perform a transition
+func requireWorkflowRole(c *gin.Context, roles []string, forbiddenMsg string) (uuid.UUID, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return uuid.Nil, false
+	}
+
+	userRole, _ := middleware.GetUserRole(c)
+	for _, role := range roles {
+		if userRole == role {
+			return userID, true
+		}
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{"error": forbiddenMsg})
+	return uuid.Nil, false
+}
+
+// bindOptionalJSON binds a request body that may be omitted entirely
+func bindOptionalJSON(c *gin.Context, input interface{}) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,335 @@
+package payroll
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-server/internal/ledger"
+	"go-server/internal/sequence"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TransitionDraft moves a single draft to the submitted, rejected or reopened status
+func (r *Repo) TransitionDraft(ctx context.Context, id uuid.UUID, toStatus string, actorID uuid.UUID, comment string) (*PayrollDraft, error) {
+	if toStatus == StatusApproved {
+		return nil, fmt.Errorf("use ApproveDraft to approve a payroll draft")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var draft PayrollDraft
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDraft(tx, id, &draft); err != nil {
+			return err
+		}
+		return transitionDraftTx(tx, &draft, toStatus, actorID, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if draft.RunID != nil {
+		if err := r.refreshRunTotalsByID(ctx, *draft.RunID); err != nil {
+			return nil, err
+		}
+	}
+	return &draft, nil
+}
+
+// ApproveDraft approves a submitted draft, creating the official approved payroll record
+func (r *Repo) ApproveDraft(ctx context.Context, id uuid.UUID, accountantID uuid.UUID, comment string) (*PayrollApproved, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var approved *PayrollApproved
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var draft PayrollDraft
+		if err := lockDraft(tx, id, &draft); err != nil {
+			return err
+		}
+
+		var err error
+		approved, err = approveDraftTx(tx, &draft, accountantID, comment)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return approved, nil
+}
+
+// TransitionRun moves a run and its drafts through the approval workflow in one transaction.
+// Approving a run approves every submitted draft it contains.
+func (r *Repo) TransitionRun(ctx context.Context, id uuid.UUID, toStatus string, actorID uuid.UUID, comment string) (*PayrollRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var run PayrollRun
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&run).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("payroll run not found")
+			}
+			return fmt.Errorf("get payroll run: %w", err)
+		}
+
+		if !canTransition(run.Status, toStatus) {
+			return fmt.Errorf("cannot move payroll run from %s to %s", run.Status, toStatus)
+		}
+
+		var drafts []PayrollDraft
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("run_id = ? AND deleted_at IS NULL", run.ID).Find(&drafts).Error; err != nil {
+			return fmt.Errorf("get payroll run drafts: %w", err)
+		}
+
+		changed := 0
+		for i := range drafts {
+			draft := &drafts[i]
+			switch toStatus {
+			case StatusSubmitted:
+				if !isEditable(draft.Status) {
+					continue
+				}
+			case StatusApproved:
+				if isEditable(draft.Status) {
+					return fmt.Errorf("payroll run has drafts that are not submitted")
+				}
+				if draft.Status != StatusSubmitted {
+					continue
+				}
+				if _, err := approveDraftTx(tx, draft, actorID, comment); err != nil {
+					return fmt.Errorf("approve draft %s: %w", draft.ID, err)
+				}
+				changed++
+				continue
+			case StatusRejected:
+				if draft.Status != StatusSubmitted {
+					continue
+				}
+			case StatusReopened:
+				if draft.Status != StatusRejected {
+					continue
+				}
+			}
+
+			if err := transitionDraftTx(tx, draft, toStatus, actorID, comment); err != nil {
+				return fmt.Errorf("update draft %s: %w", draft.ID, err)
+			}
+			changed++
+		}
+
+		if toStatus == StatusSubmitted && changed == 0 {
+			return fmt.Errorf("payroll run has no drafts to submit")
+		}
+
+		change := &PayrollStatusChange{
+			RunID:      &run.ID,
+			FromStatus: run.Status,
+			ToStatus:   toStatus,
+			Comment:    comment,
+			ChangedBy:  actorID,
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return fmt.Errorf("record payroll run status change: %w", err)
+		}
+
+		run.Status = toStatus
+		run.UpdatedAt = time.Now()
+		if err := tx.Save(&run).Error; err != nil {
+			return fmt.Errorf("update payroll run: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.RefreshRunTotals(ctx, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// GetStatusHistory retrieves the workflow history of a draft or run, oldest first
+func (r *Repo) GetStatusHistory(ctx context.Context, draftID, runID *uuid.UUID) ([]PayrollStatusChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db := r.db.WithContext(ctx).Model(&PayrollStatusChange{})
+	if draftID != nil {
+		db = db.Where("draft_id = ?", *draftID)
+	}
+	if runID != nil {
+		db = db.Where("run_id = ?", *runID)
+	}
+
+	var history []PayrollStatusChange
+	if err := db.Order("created_at ASC").Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("get payroll status history: %w", err)
+	}
+	return history, nil
+}
+
+// refreshRunTotalsByID loads a run and recomputes its aggregate totals
+func (r *Repo) refreshRunTotalsByID(ctx context.Context, runID uuid.UUID) error {
+	run, err := r.GetRunByID(ctx, runID)
+	if err != nil {
+		return err
+	}
+	return r.RefreshRunTotals(ctx, run)
+}
+
+// lockDraft loads a draft with a row lock for the duration of the transaction
+func lockDraft(tx *gorm.DB, id uuid.UUID, draft *PayrollDraft) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", id).First(draft).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("payroll draft not found")
+		}
+		return fmt.Errorf("get payroll draft: %w", err)
+	}
+	return nil
+}
+
+// transitionDraftTx validates and applies a status change to a locked draft
+func transitionDraftTx(tx *gorm.DB, draft *PayrollDraft, toStatus string, actorID uuid.UUID, comment string) error {
+	if !canTransition(draft.Status, toStatus) {
+		return fmt.Errorf("cannot move payroll draft from %s to %s", draft.Status, toStatus)
+	}
+
+	change := &PayrollStatusChange{
+		DraftID:    &draft.ID,
+		FromStatus: draft.Status,
+		ToStatus:   toStatus,
+		Comment:    comment,
+		ChangedBy:  actorID,
+	}
+	if err := tx.Create(change).Error; err != nil {
+		return fmt.Errorf("record payroll status change: %w", err)
+	}
+
+	now := time.Now()
+	switch toStatus {
+	case StatusSubmitted:
+		draft.SubmittedAt = &now
+		draft.RejectionReason = ""
+	case StatusApproved, StatusRejected:
+		draft.ReviewedBy = &actorID
+		draft.ReviewedAt = &now
+		if toStatus == StatusRejected {
+			draft.RejectionReason = comment
+		}
+	case StatusReopened:
+		draft.SubmittedAt = nil
+		draft.ReviewedBy = nil
+		draft.ReviewedAt = nil
+	}
+	draft.Status = toStatus
+	draft.UpdatedAt = now
+
+	if err := tx.Omit(clause.Associations).Save(draft).Error; err != nil {
+		return fmt.Errorf("update payroll draft status: %w", err)
+	}
+	return nil
+}
+
+// approveDraftTx approves a locked, submitted draft and creates its approved payroll record
+func approveDraftTx(tx *gorm.DB, draft *PayrollDraft, accountantID uuid.UUID, comment string) (*PayrollApproved, error) {
+	if draft.Status != StatusSubmitted {
+		return nil, fmt.Errorf("only submitted payroll drafts can be approved (current status: %s)", draft.Status)
+	}
+
+	var count int64
+	if err := tx.Model(&PayrollApproved{}).Where("draft_id = ?", draft.ID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("check existing approval: %w", err)
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("payroll draft is already approved")
+	}
+
+	// Line items are part of the GL entries and of the signed payslip
+	if err := orderLineItems(tx).Where("draft_id = ?", draft.ID).Find(&draft.Items).Error; err != nil {
+		return nil, fmt.Errorf("list payroll line items: %w", err)
+	}
+
+	// Generate OHADA-compliant GL entries
+	glEntries := generateGLEntries(draft)
+	glEntriesJSON, err := json.Marshal(glEntries)
+	if err != nil {
+		return nil, fmt.Errorf("generate GL entries: %w", err)
+	}
+
+	// Signatures cover the timestamp to the second, so store it at that precision
+	approvedAt := time.Now().Truncate(time.Second)
+	fiscalYear, seq, err := sequence.Next(tx, sequence.FichePaie, approvedAt)
+	if err != nil {
+		return nil, err
+	}
+	fichePaieNumber := sequence.Format("FDPAIE", fiscalYear, seq)
+
+	signature, keyID, err := signPayslip(fichePaieNumber, draft, accountantID, approvedAt)
+	if err != nil {
+		return nil, fmt.Errorf("sign payslip: %w", err)
+	}
+
+	approved := &PayrollApproved{
+		DraftID:            draft.ID,
+		FichePaieNumber:    fichePaieNumber,
+		AccountantID:       accountantID,
+		GLEntries:          string(glEntriesJSON),
+		ApprovedAt:         approvedAt,
+		DigitalSignature:   signature,
+		SignatureAlgorithm: SignatureAlgorithmEd25519,
+		SignatureKeyID:     keyID,
+	}
+	if err := tx.Create(approved).Error; err != nil {
+		return nil, fmt.Errorf("create approved payroll: %w", err)
+	}
+
+	// Post the GL entries to the payroll journal, dated the last day of the pay period
+	if err := ledger.Post(tx, payrollJournalEntry(draft, approved, glEntries)); err != nil {
+		return nil, fmt.Errorf("post payroll journal entry: %w", err)
+	}
+
+	if err := transitionDraftTx(tx, draft, StatusApproved, accountantID, comment); err != nil {
+		return nil, err
+	}
+	return approved, nil
+}
+
+// payrollJournalEntry builds the payroll journal entry of an approved draft from its GL entries.
+// Zero amounts are left out and a negative amount is posted on the opposite side.
+func payrollJournalEntry(draft *PayrollDraft, approved *PayrollApproved, glEntries []GLEntry) *ledger.JournalEntry {
+	entry := &ledger.JournalEntry{
+		Journal:     ledger.JournalPayroll,
+		EntryDate:   draft.PeriodEnd,
+		Description: fmt.Sprintf("Paie %s - %s", draft.PeriodStart.Format("01/2006"), approved.FichePaieNumber),
+		SourceType:  ledger.SourcePayrollApproved,
+		SourceID:    &approved.ID,
+		PostedBy:    approved.AccountantID,
+	}
+	for _, gl := range glEntries {
+		debit, credit := gl.Debit-gl.Credit, 0.0
+		if debit < 0 {
+			debit, credit = 0, -debit
+		}
+		if debit == 0 && credit == 0 {
+			continue
+		}
+		entry.Lines = append(entry.Lines, ledger.JournalLine{
+			AccountCode: gl.AccountCode,
+			AccountName: gl.AccountName,
+			Debit:       debit,
+			Credit:      credit,
+			Description: gl.Description,
+		})
+	}
+	return entry
+}
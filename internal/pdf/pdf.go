@@ -0,0 +1,256 @@
+// Package pdf provides a minimal PDF writer for generated documents such as payslips.
+// It supports A4 pages, the standard Helvetica fonts, text, lines and a diagonal watermark,
+// which is all the generated documents need, without pulling in an external dependency.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// A4 page size in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document is a PDF document under construction. Coordinates are in points
+// with the origin at the top-left corner of the page.
+type Document struct {
+	pages     []*bytes.Buffer
+	current   *bytes.Buffer
+	watermark string
+	title     string
+}
+
+// New creates an empty document
+func New() *Document {
+	return &Document{}
+}
+
+// SetTitle sets the document title shown by PDF viewers
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// SetWatermark draws the given text diagonally across every page
+func (d *Document) SetWatermark(text string) {
+	d.watermark = text
+}
+
+// AddPage starts a new page; subsequent drawing goes to it
+func (d *Document) AddPage() {
+	d.current = &bytes.Buffer{}
+	d.pages = append(d.pages, d.current)
+}
+
+// Text draws text with its baseline starting at (x, y)
+func (d *Document) Text(x, y, size float64, bold bool, s string) {
+	d.ensurePage()
+	fmt.Fprintf(d.current, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", fontName(bold), size, x, PageHeight-y, escape(s))
+}
+
+// TextRight draws text so that it ends at x
+func (d *Document) TextRight(x, y, size float64, bold bool, s string) {
+	d.Text(x-TextWidth(s, size, bold), y, size, bold, s)
+}
+
+// Line draws a straight line between two points
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	d.ensurePage()
+	fmt.Fprintf(d.current, "%.2f w %.2f %.2f m %.2f %.2f l S\n", 0.5, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// Rect draws the outline of a rectangle whose top-left corner is (x, y)
+func (d *Document) Rect(x, y, w, h float64) {
+	d.ensurePage()
+	fmt.Fprintf(d.current, "%.2f w %.2f %.2f %.2f %.2f re S\n", 0.5, x, PageHeight-y-h, w, h)
+}
+
+// FillRect fills a rectangle with a gray level between 0 (black) and 1 (white)
+func (d *Document) FillRect(x, y, w, h, gray float64) {
+	d.ensurePage()
+	fmt.Fprintf(d.current, "q %.2f g %.2f %.2f %.2f %.2f re f Q\n", gray, x, PageHeight-y-h, w, h)
+}
+
+// Bytes serializes the document
+func (d *Document) Bytes() ([]byte, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	writeObject := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-5 are fixed; each page then uses a page object and a content stream object
+	pageCount := len(d.pages)
+	kids := make([]string, pageCount)
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+i*2)
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pageCount))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	writeObject(fmt.Sprintf("<< /Producer (PeopleDesk) /Title (%s) >>", escape(d.title)))
+
+	for i, page := range d.pages {
+		contentRef := 7 + i*2
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", PageWidth, PageHeight, contentRef))
+
+		var content bytes.Buffer
+		if d.watermark != "" {
+			content.WriteString(d.watermarkStream())
+		}
+		content.Write(page.Bytes())
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xrefOffset := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefOffset)
+
+	return out.Bytes(), nil
+}
+
+// watermarkStream draws the watermark in light gray, rotated 45 degrees across the page centre
+func (d *Document) watermarkStream() string {
+	size := 60.0
+	angle := math.Pi / 4
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	half := TextWidth(d.watermark, size, true) / 2
+	x := PageWidth/2 - half*cos
+	y := PageHeight/2 - half*sin
+	return fmt.Sprintf("q 0.85 g BT /F2 %.2f Tf %.4f %.4f %.4f %.4f %.2f %.2f Tm (%s) Tj ET Q\n",
+		size, cos, sin, -sin, cos, x, y, escape(d.watermark))
+}
+
+func (d *Document) ensurePage() {
+	if d.current == nil {
+		d.AddPage()
+	}
+}
+
+func fontName(bold bool) string {
+	if bold {
+		return "F2"
+	}
+	return "F1"
+}
+
+// TextWidth approximates the rendered width of s using Helvetica metrics
+func TextWidth(s string, size float64, bold bool) float64 {
+	total := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			total += helveticaWidths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	width := float64(total) * size / 1000
+	if bold {
+		width *= 1.06
+	}
+	return width
+}
+
+// FormatAmount formats an amount with space-separated thousands and a decimal comma
+func FormatAmount(v float64) string {
+	negative := v < 0
+	cents := int64(math.Round(math.Abs(v) * 100))
+	whole := fmt.Sprintf("%d", cents/100)
+
+	var b strings.Builder
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(c)
+	}
+
+	s := fmt.Sprintf("%s,%02d", b.String(), cents%100)
+	if negative {
+		s = "-" + s
+	}
+	return s
+}
+
+// escape converts s to WinAnsi bytes and escapes PDF string delimiters
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		c, ok := winAnsi(r)
+		if !ok {
+			c = '?'
+		}
+		switch c {
+		case '\\', '(', ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n', '\r', '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// winAnsi maps a rune to its WinAnsiEncoding byte
+func winAnsi(r rune) (byte, bool) {
+	switch {
+	case r < 0x80:
+		return byte(r), true
+	case r >= 0xA0 && r <= 0xFF:
+		return byte(r), true
+	}
+	switch r {
+	case '€':
+		return 0x80, true
+	case '…':
+		return 0x85, true
+	case 'Œ':
+		return 0x8C, true
+	case '‘':
+		return 0x91, true
+	case '’':
+		return 0x92, true
+	case '“':
+		return 0x93, true
+	case '”':
+		return 0x94, true
+	case '•':
+		return 0x95, true
+	case '–':
+		return 0x96, true
+	case '—':
+		return 0x97, true
+	case 'œ':
+		return 0x9C, true
+	}
+	return 0, false
+}
+
+// helveticaWidths holds Helvetica glyph widths for ASCII 32-126, in thousandths of an em
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
@@ -0,0 +1,57 @@
+// Package scheduler runs background jobs inside the API process at a fixed time of day.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a task run once a day at Hour:Minute
+type Job struct {
+	Name   string
+	Hour   int
+	Minute int
+	Run    func(ctx context.Context) error
+}
+
+// Start runs each job every day at its time in loc until ctx is cancelled. A failed run is logged and the job
+// runs again the next day; runs of the same job never overlap.
+func Start(ctx context.Context, loc *time.Location, jobs ...Job) {
+	for _, job := range jobs {
+		go runDaily(ctx, loc, job)
+	}
+}
+
+// runDaily waits for each next run time of a job and runs it
+func runDaily(ctx context.Context, loc *time.Location, job Job) {
+	for {
+		next := NextRun(time.Now(), job.Hour, job.Minute, loc)
+		log.Printf("scheduler: %s next runs at %s", job.Name, next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		started := time.Now()
+		if err := job.Run(ctx); err != nil {
+			log.Printf("scheduler: %s failed after %s: %v", job.Name, time.Since(started).Round(time.Millisecond), err)
+			continue
+		}
+		log.Printf("scheduler: %s completed in %s", job.Name, time.Since(started).Round(time.Millisecond))
+	}
+}
+
+// NextRun returns the first time after now that is hour:minute in loc
+func NextRun(now time.Time, hour, minute int, loc *time.Location) time.Time {
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !next.After(local) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, loc)
+	}
+	return next
+}
@@ -0,0 +1,89 @@
+// Package sequence allocates gap-free, per-fiscal-year document numbers
+// (payslips, declarations, journal entries, salary payment batches, support tickets) from the document_sequences table.
+package sequence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sequence names
+const (
+	FichePaie     = "fiche_paie"
+	PaymentBatch  = "payment_batch"
+	SupportTicket = "support_ticket"
+)
+
+// Declaration returns the sequence name for a declaration type, e.g. declaration_cnaps
+func Declaration(declarationType string) string {
+	return "declaration_" + declarationType
+}
+
+// Journal returns the sequence name for a ledger journal, e.g. journal_paie
+func Journal(journal string) string {
+	return "journal_" + strings.ToLower(journal)
+}
+
+// Next allocates the next number of a sequence for the company fiscal year containing t.
+// It must run inside the transaction that stores the numbered document: the sequence row stays
+// locked until that transaction ends, and a rollback releases the number, so numbering is gap-free.
+func Next(tx *gorm.DB, name string, t time.Time) (fiscalYear int, value int64, err error) {
+	fiscalYearStart, err := companyFiscalYearStart(tx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fiscalYear, err = FiscalYear(t, fiscalYearStart)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = tx.Raw(`
+		INSERT INTO document_sequences (name, fiscal_year, last_value, updated_at)
+		VALUES (?, ?, 1, NOW())
+		ON CONFLICT (name, fiscal_year)
+		DO UPDATE SET last_value = document_sequences.last_value + 1, updated_at = NOW()
+		RETURNING last_value`, name, fiscalYear).Scan(&value).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("allocate %s number: %w", name, err)
+	}
+	return fiscalYear, value, nil
+}
+
+// Format renders a document number such as FDPAIE-2026-00123
+func Format(prefix string, fiscalYear int, value int64) string {
+	return fmt.Sprintf("%s-%04d-%05d", prefix, fiscalYear, value)
+}
+
+// FiscalYear returns the fiscal year containing t, named after the calendar year it starts in.
+// fiscalYearStart is the company setting in MM-DD form; an empty value means January 1st.
+func FiscalYear(t time.Time, fiscalYearStart string) (int, error) {
+	if fiscalYearStart == "" {
+		return t.Year(), nil
+	}
+
+	start, err := time.Parse("01-02", fiscalYearStart)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fiscal year start %q: %w", fiscalYearStart, err)
+	}
+
+	if t.Month() < start.Month() || (t.Month() == start.Month() && t.Day() < start.Day()) {
+		return t.Year() - 1, nil
+	}
+	return t.Year(), nil
+}
+
+// companyFiscalYearStart reads the fiscal year start from company settings, if configured
+func companyFiscalYearStart(tx *gorm.DB) (string, error) {
+	var starts []*string
+	if err := tx.Table("company_settings").Limit(1).Pluck("fiscal_year_start", &starts).Error; err != nil {
+		return "", fmt.Errorf("get fiscal year start: %w", err)
+	}
+	if len(starts) == 0 || starts[0] == nil {
+		return "", nil
+	}
+	return *starts[0], nil
+}
@@ -10,10 +10,13 @@ import (
 	"go-server/internal/employee"
 	"go-server/internal/kpi"
 	"go-server/internal/leave"
+	"go-server/internal/ledger"
 	"go-server/internal/notifications"
+	"go-server/internal/organization"
 	"go-server/internal/payroll"
 	"go-server/internal/support"
 	"go-server/internal/support_tickets"
+	"go-server/internal/taxrules"
 	"net/http"
 	"os"
 	"strings"
@@ -64,12 +67,15 @@ func NewRouter(gormDB *gorm.DB) *gin.Engine {
 		audit.RegisterRoutes(api, gormDB)
 		support.RegisterRoutes(api, gormDB)
 		payroll.RegisterRoutes(api, gormDB)
+		taxrules.RegisterRoutes(api, gormDB)
 		kpi.RegisterRoutes(api, gormDB)
+		ledger.RegisterRoutes(api, gormDB)
 		declarations.RegisterRoutes(api, gormDB)
 		dashboard.RegisterRoutes(api, gormDB)
 		notifications.RegisterRoutes(api, gormDB)
 		support_tickets.RegisterRoutes(api, gormDB)
 		company.RegisterRoutes(api, gormDB)
+		organization.RegisterRoutes(api, gormDB)
 	}
 
 	return r
@@ -0,0 +1,131 @@
+// Package signing holds the server Ed25519 key used to sign official documents such as approved payslips
+// and declaration summaries, and the public keys accepted when verifying them.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// AlgorithmEd25519 identifies documents signed with the server Ed25519 key
+const AlgorithmEd25519 = "ed25519"
+
+// Signer holds the server key pair used to sign documents
+type Signer struct {
+	privateKey ed25519.PrivateKey
+	keyID      string
+	// trusted maps key IDs to public keys accepted for verification, including retired keys
+	trusted map[string]ed25519.PublicKey
+}
+
+var (
+	signerOnce sync.Once
+	signer     *Signer
+	signerErr  error
+)
+
+// Default loads the signing key from PAYROLL_SIGNING_KEY (base64 Ed25519 seed). The key is required unless
+// APP_ENV is development, where a well-known development key is used instead.
+// Public keys of retired signing keys can be listed in PAYROLL_VERIFY_KEYS (comma separated, base64)
+// so documents signed before a key rotation still verify.
+func Default() (*Signer, error) {
+	signerOnce.Do(func() {
+		var seed []byte
+		encoded := os.Getenv("PAYROLL_SIGNING_KEY")
+		if encoded == "" {
+			if os.Getenv("APP_ENV") != "development" {
+				signerErr = errors.New("PAYROLL_SIGNING_KEY is required unless APP_ENV=development")
+				return
+			}
+			// Default for development only: anyone can derive this key and forge signed documents
+			log.Println("WARNING: PAYROLL_SIGNING_KEY not set, using development document signing key")
+			sum := sha256.Sum256([]byte("peopledesk-development-payslip-signing-key"))
+			seed = sum[:]
+		} else {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+			if err != nil {
+				signerErr = fmt.Errorf("decode PAYROLL_SIGNING_KEY: %w", err)
+				return
+			}
+			if len(decoded) != ed25519.SeedSize {
+				signerErr = fmt.Errorf("PAYROLL_SIGNING_KEY must be a %d byte Ed25519 seed", ed25519.SeedSize)
+				return
+			}
+			seed = decoded
+		}
+
+		privateKey := ed25519.NewKeyFromSeed(seed)
+		publicKey := privateKey.Public().(ed25519.PublicKey)
+		s := &Signer{
+			privateKey: privateKey,
+			keyID:      keyID(publicKey),
+			trusted:    map[string]ed25519.PublicKey{},
+		}
+		s.trusted[s.keyID] = publicKey
+
+		for _, item := range strings.Split(os.Getenv("PAYROLL_VERIFY_KEYS"), ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			decoded, err := base64.StdEncoding.DecodeString(item)
+			if err != nil || len(decoded) != ed25519.PublicKeySize {
+				signerErr = fmt.Errorf("PAYROLL_VERIFY_KEYS contains an invalid Ed25519 public key")
+				return
+			}
+			s.trusted[keyID(decoded)] = ed25519.PublicKey(decoded)
+		}
+
+		signer = s
+	})
+	return signer, signerErr
+}
+
+// keyID derives a short stable identifier from a public key
+func keyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// KeyID returns the identifier of the current signing key
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// Sign signs a payload, returning the base64 signature and the signing key ID
+func (s *Signer) Sign(payload []byte) (signature, keyID string) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.privateKey, payload)), s.keyID
+}
+
+// Verification failures returned by Verify
+var (
+	ErrUnknownKey         = errors.New("signed with an unknown key")
+	ErrMalformedSignature = errors.New("signature is malformed")
+	ErrSignatureMismatch  = errors.New("data does not match its signature")
+)
+
+// Verify checks a base64 signature of a payload made with the trusted key keyID
+func (s *Signer) Verify(keyID string, payload []byte, signature string) error {
+	publicKey, ok := s.trusted[keyID]
+	if !ok {
+		return ErrUnknownKey
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrMalformedSignature
+	}
+
+	if !ed25519.Verify(publicKey, payload, decoded) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 is a local stand-in for an S3 bucket addressed by path. It keeps objects in memory under their
+// escaped request path and rejects requests that are not signed for the test credentials.
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+	fail    bool
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		f.t.Errorf("read request body: %v", err)
+	}
+	sum := sha256.Sum256(body)
+	if got := r.Header.Get("X-Amz-Content-Sha256"); got != hex.EncodeToString(sum[:]) {
+		f.t.Errorf("%s %s: X-Amz-Content-Sha256 = %q, want the payload hash", r.Method, r.URL.Path, got)
+	}
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=test-key/") ||
+		!strings.Contains(auth, "/eu-west-3/s3/aws4_request") || !strings.Contains(auth, "Signature=") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if f.fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, "<Error><Code>InternalError</Code></Error>")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := r.URL.EscapedPath()
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		content, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Write(content)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestS3(t *testing.T) (*S3, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{t: t, objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	s, err := NewS3(S3Config{
+		Endpoint:        server.URL,
+		Region:          "eu-west-3",
+		Bucket:          "peopledesk",
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("NewS3: %v", err)
+	}
+	return s, fake
+}
+
+func TestS3RoundTrip(t *testing.T) {
+	s, fake := newTestS3(t)
+	ctx := context.Background()
+	key := "employees/42/documents/7/contrat signé.pdf"
+	content := []byte("%PDF-1.7 contract")
+
+	if err := s.Put(ctx, key, content, "application/pdf"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	stored := "/peopledesk/employees/42/documents/7/contrat%20sign%C3%A9.pdf"
+	if _, ok := fake.objects[stored]; !ok {
+		t.Fatalf("object not stored under %s: have %v", stored, fake.objects)
+	}
+	if got := fake.types[stored]; got != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", got)
+	}
+
+	got, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("Get = %q, want %q", got, content)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of a missing object: %v", err)
+	}
+}
+
+func TestS3GetMissing(t *testing.T) {
+	s, _ := newTestS3(t)
+
+	if _, err := s.Get(context.Background(), "company/logo.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestS3Errors(t *testing.T) {
+	s, fake := newTestS3(t)
+	fake.fail = true
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "company/logo.png", []byte("png"), "image/png"); err == nil || !strings.Contains(err.Error(), "InternalError") {
+		t.Errorf("Put: err = %v, want the S3 error document", err)
+	}
+	if _, err := s.Get(ctx, "company/logo.png"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get: err = %v, want a server error", err)
+	}
+	if err := s.Delete(ctx, "company/logo.png"); err == nil {
+		t.Error("Delete: want a server error")
+	}
+}
@@ -0,0 +1,109 @@
+// Package storage keeps uploaded files, such as employee documents and the company logo, on the local
+// filesystem or in an S3-compatible object store, as configured in the environment.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Storage stores files under slash-separated keys such as "employees/<id>/documents/<id>/v1.pdf"
+type Storage interface {
+	// Put stores content under a key, replacing any file already there
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	// Get returns the content stored under a key, or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the file stored under a key; deleting a missing file is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// ErrNotFound is returned when no file is stored under a key
+var ErrNotFound = errors.New("file not found")
+
+// ErrTooLarge is returned when an upload exceeds the maximum upload size
+var ErrTooLarge = errors.New("file is too large")
+
+// defaultMaxUploadSize is the largest upload accepted when MAX_UPLOAD_SIZE is not set: 10 MiB
+const defaultMaxUploadSize = 10 << 20
+
+var (
+	storageOnce sync.Once
+	store       Storage
+	storeErr    error
+)
+
+// Default configures the storage from STORAGE_BACKEND: "local" (the default) keeps files under UPLOAD_PATH
+// (default ./uploads); "s3" keeps them in the bucket S3_BUCKET, see NewS3 for the other S3_* variables.
+func Default() (Storage, error) {
+	storageOnce.Do(func() {
+		switch backend := strings.ToLower(os.Getenv("STORAGE_BACKEND")); backend {
+		case "", "local":
+			root := os.Getenv("UPLOAD_PATH")
+			if root == "" {
+				root = "./uploads"
+			}
+			store, storeErr = NewLocal(root)
+		case "s3":
+			store, storeErr = NewS3(S3Config{
+				Endpoint:        os.Getenv("S3_ENDPOINT"),
+				Region:          os.Getenv("S3_REGION"),
+				Bucket:          os.Getenv("S3_BUCKET"),
+				AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+				SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			})
+		default:
+			storeErr = fmt.Errorf("unknown STORAGE_BACKEND %q, use local or s3", backend)
+		}
+	})
+	return store, storeErr
+}
+
+// MaxUploadSize returns the largest upload accepted, in bytes, from MAX_UPLOAD_SIZE (default 10 MiB)
+func MaxUploadSize() int64 {
+	if size, err := strconv.ParseInt(os.Getenv("MAX_UPLOAD_SIZE"), 10, 64); err == nil && size > 0 {
+		return size
+	}
+	return defaultMaxUploadSize
+}
+
+// ReadUpload reads an uploaded file of at most MaxUploadSize bytes and returns its content with the content
+// type detected from it, ignoring the type the client claims
+func ReadUpload(header *multipart.FileHeader) ([]byte, string, error) {
+	max := MaxUploadSize()
+	if header.Size > max {
+		return nil, "", ErrTooLarge
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, "", fmt.Errorf("open upload: %w", err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, max+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("read upload: %w", err)
+	}
+	if int64(len(content)) > max {
+		return nil, "", ErrTooLarge
+	}
+	return content, http.DetectContentType(content), nil
+}
+
+// checkKey rejects keys that are empty, absolute or that could escape the storage root
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	return nil
+}
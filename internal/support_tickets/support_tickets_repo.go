@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"go-server/internal/sequence"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -20,24 +22,13 @@ func NewRepo(database *gorm.DB) *Repo {
 	return &Repo{db: database}
 }
 
-// generateTicketNumber generates a unique ticket number
-func (r *Repo) generateTicketNumber(ctx context.Context) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	year := time.Now().Year()
-
-	// Get the count of tickets for this year
-	var count int64
-	if err := r.db.WithContext(ctx).Model(&SupportTicket{}).
-		Where("EXTRACT(YEAR FROM created_at) = ?", year).
-		Count(&count).Error; err != nil {
-		return "", fmt.Errorf("count tickets for year: %w", err)
+// generateTicketNumber allocates the next gap-free ticket number for the fiscal year, e.g. TICKET-2026-00042
+func generateTicketNumber(tx *gorm.DB, createdAt time.Time) (string, error) {
+	fiscalYear, seq, err := sequence.Next(tx, sequence.SupportTicket, createdAt)
+	if err != nil {
+		return "", err
 	}
-
-	// Generate ticket number: TICKET-YYYY-NNN (padded with leading zeros)
-	ticketNumber := fmt.Sprintf("TICKET-%d-%03d", year, count+1)
-	return ticketNumber, nil
+	return sequence.Format("TICKET", fiscalYear, seq), nil
 }
 
 // Create creates a new support ticket
@@ -45,17 +36,19 @@ func (r *Repo) Create(ctx context.Context, ticket *SupportTicket) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	// Generate ticket number
-	ticketNumber, err := r.generateTicketNumber(ctx)
-	if err != nil {
-		return fmt.Errorf("generate ticket number: %w", err)
-	}
-	ticket.TicketNumber = ticketNumber
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Generate ticket number
+		ticketNumber, err := generateTicketNumber(tx, time.Now())
+		if err != nil {
+			return fmt.Errorf("generate ticket number: %w", err)
+		}
+		ticket.TicketNumber = ticketNumber
 
-	if err := r.db.WithContext(ctx).Create(ticket).Error; err != nil {
-		return fmt.Errorf("create support ticket: %w", err)
-	}
-	return nil
+		if err := tx.Create(ticket).Error; err != nil {
+			return fmt.Errorf("create support ticket: %w", err)
+		}
+		return nil
+	})
 }
 
 // GetByID retrieves a support ticket by ID with user info
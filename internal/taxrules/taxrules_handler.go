@@ -0,0 +1,295 @@
+package taxrules
+
+import (
+	"net/http"
+	"time"
+
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler handles tax rule requests
+type Handler struct {
+	repo *Repo
+}
+
+// NewHandler creates a new tax rules handler
+func NewHandler(repo *Repo) *Handler {
+	return &Handler{repo: repo}
+}
+
+// CreateIRSABracket creates a new IRSA tax bracket (Admin only)
+func (h *Handler) CreateIRSABracket(c *gin.Context) {
+	var input CreateIRSATaxBracketRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Admin role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Admin can create IRSA tax brackets"})
+		return
+	}
+
+	bracket := &IRSATaxBracket{
+		MinIncome:     input.MinIncome,
+		MaxIncome:     input.MaxIncome,
+		TaxRate:       input.TaxRate,
+		MinTax:        input.MinTax,
+		BracketName:   input.BracketName,
+		IsActive:      true,
+		SortOrder:     input.SortOrder,
+		EffectiveDate: input.EffectiveDate,
+		CreatedBy:     userID,
+	}
+
+	if err := h.repo.CreateIRSABracket(c.Request.Context(), bracket); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, bracket)
+}
+
+// ReplaceIRSAScale replaces every IRSA bracket from an effective date with a new scale (Admin only)
+func (h *Handler) ReplaceIRSAScale(c *gin.Context) {
+	var input ReplaceIRSAScaleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Admin role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Admin can replace the IRSA scale"})
+		return
+	}
+
+	brackets := make([]IRSATaxBracket, len(input.Brackets))
+	for i, b := range input.Brackets {
+		brackets[i] = IRSATaxBracket{
+			MinIncome:   b.MinIncome,
+			MaxIncome:   b.MaxIncome,
+			TaxRate:     b.TaxRate,
+			MinTax:      b.MinTax,
+			BracketName: b.BracketName,
+		}
+	}
+
+	if err := h.repo.ReplaceIRSAScale(c.Request.Context(), input.EffectiveDate, brackets, userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"effective_date": dateOnly(input.EffectiveDate),
+		"brackets":       brackets,
+	})
+}
+
+// GetIRSABracketByID retrieves an IRSA tax bracket by ID
+func (h *Handler) GetIRSABracketByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid IRSA tax bracket ID"})
+		return
+	}
+
+	bracket, err := h.repo.GetIRSABracketByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "IRSA tax bracket not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, bracket)
+}
+
+// UpdateIRSABracket updates an IRSA tax bracket (Admin only)
+func (h *Handler) UpdateIRSABracket(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid IRSA tax bracket ID"})
+		return
+	}
+
+	var input UpdateIRSATaxBracketRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user info from context
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Verify Admin role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Admin can update IRSA tax brackets"})
+		return
+	}
+
+	bracket, err := h.repo.GetIRSABracketByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "IRSA tax bracket not found"})
+		return
+	}
+
+	// Update fields if provided
+	revised := *bracket
+	if input.MinIncome != nil {
+		revised.MinIncome = *input.MinIncome
+	}
+	if input.MaxIncome != nil {
+		revised.MaxIncome = input.MaxIncome
+	}
+	if input.TaxRate != nil {
+		revised.TaxRate = *input.TaxRate
+	}
+	if input.MinTax != nil {
+		revised.MinTax = *input.MinTax
+	}
+	if input.BracketName != nil {
+		revised.BracketName = *input.BracketName
+	}
+	if input.SortOrder != nil {
+		revised.SortOrder = *input.SortOrder
+	}
+	if input.IsActive != nil {
+		revised.IsActive = *input.IsActive
+	}
+
+	// New amounts never overwrite a version: they start a new one so past periods keep their brackets
+	if bracketAmountsChanged(bracket, &revised) {
+		revised.EffectiveDate = time.Now()
+		if input.EffectiveDate != nil {
+			revised.EffectiveDate = *input.EffectiveDate
+		}
+		revised.CreatedBy = userID
+		revised.UpdatedBy = nil
+		if err := h.repo.ReviseIRSABracket(c.Request.Context(), bracket, &revised); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, revised)
+		return
+	}
+	if input.EffectiveDate != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "effective_date can only be given with a new income range, rate or minimum tax"})
+		return
+	}
+
+	revised.UpdatedBy = &userID
+	if err := h.repo.UpdateIRSABracket(c.Request.Context(), &revised); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, revised)
+}
+
+// bracketAmountsChanged reports whether an update changes the figures an IRSA bracket taxes with
+func bracketAmountsChanged(current, revised *IRSATaxBracket) bool {
+	if current.MinIncome != revised.MinIncome || current.TaxRate != revised.TaxRate || current.MinTax != revised.MinTax {
+		return true
+	}
+	if (current.MaxIncome == nil) != (revised.MaxIncome == nil) {
+		return true
+	}
+	return current.MaxIncome != nil && *current.MaxIncome != *revised.MaxIncome
+}
+
+// DeleteIRSABracket deletes an IRSA tax bracket (Admin only)
+func (h *Handler) DeleteIRSABracket(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid IRSA tax bracket ID"})
+		return
+	}
+
+	// Verify Admin role
+	userRole, _ := middleware.GetUserRole(c)
+	if userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only Admin can delete IRSA tax brackets"})
+		return
+	}
+
+	if err := h.repo.DeleteIRSABracket(c.Request.Context(), id); err != nil {
+		if err.Error() == "IRSA bracket not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "IRSA tax bracket deleted successfully"})
+}
+
+// ListIRSABrackets lists IRSA tax brackets with filtering
+func (h *Handler) ListIRSABrackets(c *gin.Context) {
+	var query IRSATaxBracketListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	brackets, total, err := h.repo.ListIRSABrackets(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list IRSA tax brackets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"brackets": brackets,
+		"total":    total,
+		"limit":    query.Limit,
+		"offset":   query.Offset,
+	})
+}
+
+// GetIRSAScale retrieves the IRSA scale in force on a date (default today)
+func (h *Handler) GetIRSAScale(c *gin.Context) {
+	at := time.Now()
+	if date := c.Query("date"); date != "" {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format (use YYYY-MM-DD)"})
+			return
+		}
+		at = parsed
+	}
+
+	brackets, err := h.repo.GetIRSABracketsInForce(c.Request.Context(), at)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get IRSA scale"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":     dateOnly(at),
+		"brackets": brackets,
+	})
+}
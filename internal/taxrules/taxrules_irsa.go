@@ -0,0 +1,98 @@
+package taxrules
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ComputeIRSA applies the progressive scale to taxable income: each bracket's rate is charged only on the
+// slice of income inside it. The reduction per dependent is then deducted, but the result cannot fall
+// below the minimum tax once any tax is due. A bracket's MinTax is the tax already due at its start and
+// is not a floor. Income entirely in the exempt bracket pays nothing.
+func ComputeIRSA(brackets []IRSATaxBracket, taxableIncome float64, dependents int, reductionPerDependent, minimumTax float64) (*IRSABreakdown, string, error) {
+	if len(brackets) == 0 {
+		return nil, "", fmt.Errorf("no IRSA brackets in force")
+	}
+
+	breakdown := &IRSABreakdown{
+		TaxableIncome: taxableIncome,
+		Brackets:      []IRSABracketTax{},
+		Dependents:    dependents,
+	}
+
+	topBracket := brackets[0]
+	for i, b := range brackets {
+		if taxableIncome <= b.MinIncome && i > 0 {
+			break
+		}
+
+		upper := taxableIncome
+		if b.MaxIncome != nil && *b.MaxIncome < upper {
+			upper = *b.MaxIncome
+		}
+		slice := math.Max(upper-b.MinIncome, 0)
+		tax := math.Round(slice*b.TaxRate*100) / 100
+
+		breakdown.Brackets = append(breakdown.Brackets, IRSABracketTax{
+			BracketName:   b.BracketName,
+			From:          b.MinIncome,
+			To:            b.MaxIncome,
+			Rate:          b.TaxRate,
+			TaxableAmount: slice,
+			Tax:           tax,
+		})
+		breakdown.GrossTax += tax
+		topBracket = b
+	}
+
+	if breakdown.GrossTax <= 0 {
+		return breakdown, topBracket.BracketName, nil
+	}
+
+	breakdown.DependentReduction = float64(dependents) * reductionPerDependent
+	breakdown.MinimumTax = minimumTax
+	breakdown.Tax = math.Max(breakdown.GrossTax-breakdown.DependentReduction, breakdown.MinimumTax)
+	return breakdown, topBracket.BracketName, nil
+}
+
+// ValidateScale checks that brackets form a complete IRSA scale: the first starts at zero, each starts
+// exactly where the previous one ends, and only the last is open-ended. An empty scale is valid.
+func ValidateScale(brackets []IRSATaxBracket) error {
+	sorted := make([]IRSATaxBracket, len(brackets))
+	copy(sorted, brackets)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MinIncome < sorted[j].MinIncome })
+
+	for i, b := range sorted {
+		if b.TaxRate < 0 || b.TaxRate > 1 {
+			return fmt.Errorf("bracket '%s': tax_rate must be a fraction between 0 and 1", b.BracketName)
+		}
+		if b.MaxIncome != nil && *b.MaxIncome <= b.MinIncome {
+			return fmt.Errorf("bracket '%s': max_income must be greater than min_income", b.BracketName)
+		}
+
+		if i == 0 {
+			if b.MinIncome != 0 {
+				return fmt.Errorf("the IRSA scale must start at 0, but its lowest bracket '%s' starts at %.2f", b.BracketName, b.MinIncome)
+			}
+		} else {
+			prev := sorted[i-1]
+			if prev.MaxIncome == nil {
+				return fmt.Errorf("bracket '%s' overlaps the open-ended bracket '%s'", b.BracketName, prev.BracketName)
+			}
+			if b.MinIncome < *prev.MaxIncome {
+				return fmt.Errorf("bracket '%s' overlaps bracket '%s': it starts at %.2f, before %.2f",
+					b.BracketName, prev.BracketName, b.MinIncome, *prev.MaxIncome)
+			}
+			if b.MinIncome > *prev.MaxIncome {
+				return fmt.Errorf("gap between bracket '%s' ending at %.2f and bracket '%s' starting at %.2f",
+					prev.BracketName, *prev.MaxIncome, b.BracketName, b.MinIncome)
+			}
+		}
+
+		if i == len(sorted)-1 && b.MaxIncome != nil {
+			return fmt.Errorf("the top bracket '%s' must have no max_income", b.BracketName)
+		}
+	}
+	return nil
+}
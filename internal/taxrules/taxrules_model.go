@@ -0,0 +1,120 @@
+package taxrules
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IRSATaxBracket represents one version of an IRSA tax bracket, in force from EffectiveDate to EffectiveTo inclusive.
+// The brackets in force on a date form the IRSA scale: they start at zero, each begins where the previous one ends,
+// and only the top bracket is open-ended. TaxRate is a fraction (0.05 for 5%).
+type IRSATaxBracket struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MinIncome     float64    `gorm:"type:numeric(15,2);not null;default:0;index" json:"min_income"`
+	MaxIncome     *float64   `gorm:"type:numeric(15,2)" json:"max_income,omitempty"`
+	TaxRate       float64    `gorm:"type:numeric(7,4);not null;default:0" json:"tax_rate"`
+	MinTax        float64    `gorm:"type:numeric(15,2);not null;default:0" json:"min_tax"`
+	BracketName   string     `gorm:"type:varchar(100);not null" json:"bracket_name"`
+	IsActive      bool       `gorm:"not null;default:true;index" json:"is_active"`
+	SortOrder     int        `gorm:"not null;default:0;index" json:"sort_order"`
+	EffectiveDate time.Time  `gorm:"type:date;not null" json:"effective_date"`
+	EffectiveTo   *time.Time `gorm:"type:date" json:"effective_to,omitempty"`
+	CreatedAt     time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"default:now()" json:"updated_at"`
+	CreatedBy     uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	UpdatedBy     *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+}
+
+// CreateIRSATaxBracketRequest represents request to create an IRSA tax bracket
+type CreateIRSATaxBracketRequest struct {
+	MinIncome     float64   `json:"min_income" binding:"min=0"`
+	MaxIncome     *float64  `json:"max_income" binding:"omitempty,gt=0"`
+	TaxRate       float64   `json:"tax_rate" binding:"min=0,max=1"`
+	MinTax        float64   `json:"min_tax" binding:"min=0"`
+	BracketName   string    `json:"bracket_name" binding:"required,max=100"`
+	SortOrder     int       `json:"sort_order"`
+	EffectiveDate time.Time `json:"effective_date" binding:"required"`
+}
+
+// UpdateIRSATaxBracketRequest represents request to update an IRSA tax bracket.
+// Changing the income range, rate or minimum tax creates a new version in force from EffectiveDate (today when omitted).
+type UpdateIRSATaxBracketRequest struct {
+	MinIncome     *float64   `json:"min_income" binding:"omitempty,min=0"`
+	MaxIncome     *float64   `json:"max_income" binding:"omitempty,gt=0"`
+	TaxRate       *float64   `json:"tax_rate" binding:"omitempty,min=0,max=1"`
+	MinTax        *float64   `json:"min_tax" binding:"omitempty,min=0"`
+	BracketName   *string    `json:"bracket_name" binding:"omitempty,max=100"`
+	SortOrder     *int       `json:"sort_order"`
+	EffectiveDate *time.Time `json:"effective_date" binding:"omitempty"`
+	IsActive      *bool      `json:"is_active"`
+}
+
+// IRSAScaleBracket is one bracket of a scale replacement
+type IRSAScaleBracket struct {
+	MinIncome   float64  `json:"min_income" binding:"min=0"`
+	MaxIncome   *float64 `json:"max_income" binding:"omitempty,gt=0"`
+	TaxRate     float64  `json:"tax_rate" binding:"min=0,max=1"`
+	MinTax      float64  `json:"min_tax" binding:"min=0"`
+	BracketName string   `json:"bracket_name" binding:"required,max=100"`
+}
+
+// ReplaceIRSAScaleRequest represents request to replace every bracket of the IRSA scale from a date
+type ReplaceIRSAScaleRequest struct {
+	EffectiveDate time.Time          `json:"effective_date" binding:"required"`
+	Brackets      []IRSAScaleBracket `json:"brackets" binding:"required,min=1,max=20,dive"`
+}
+
+// IRSATaxBracketListQuery represents query parameters for listing IRSA tax brackets
+type IRSATaxBracketListQuery struct {
+	IsActive    *bool      `form:"is_active"`
+	EffectiveAt *time.Time `form:"effective_at"`
+	Limit       int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset      int        `form:"offset" binding:"omitempty,min=0"`
+}
+
+// IRSABracketTax is the tax on the slice of taxable income falling in one bracket
+type IRSABracketTax struct {
+	BracketName   string   `json:"bracket_name"`
+	From          float64  `json:"from"`
+	To            *float64 `json:"to,omitempty"`
+	Rate          float64  `json:"rate"`
+	TaxableAmount float64  `json:"taxable_amount"`
+	Tax           float64  `json:"tax"`
+}
+
+// IRSABreakdown details how an employee's IRSA was computed
+type IRSABreakdown struct {
+	TaxableIncome      float64          `json:"taxable_income"`
+	Brackets           []IRSABracketTax `json:"brackets"`
+	GrossTax           float64          `json:"gross_tax"`
+	Dependents         int              `json:"dependents"`
+	DependentReduction float64          `json:"dependent_reduction"`
+	MinimumTax         float64          `json:"minimum_tax"`
+	Tax                float64          `json:"tax"`
+}
+
+// Value stores the breakdown as JSON
+func (b IRSABreakdown) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+// Scan reads a breakdown stored as JSON
+func (b *IRSABreakdown) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, b)
+	case string:
+		return json.Unmarshal([]byte(v), b)
+	default:
+		return fmt.Errorf("unsupported IRSA breakdown type %T", value)
+	}
+}
+
+// TableName specifies the table name for IRSATaxBracket model
+func (IRSATaxBracket) TableName() string {
+	return "irsa_tax_brackets"
+}
@@ -0,0 +1,297 @@
+package taxrules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repo handles database operations for tax rules
+type Repo struct {
+	db *gorm.DB
+}
+
+// NewRepo creates a new tax rules repository
+func NewRepo(database *gorm.DB) *Repo {
+	return &Repo{db: database}
+}
+
+// dateOnly truncates a time to midnight UTC of its calendar day
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// inForceAt restricts a query to bracket versions whose validity range contains at
+func inForceAt(db *gorm.DB, at time.Time) *gorm.DB {
+	day := dateOnly(at)
+	return db.Where("effective_date <= ? AND (effective_to IS NULL OR effective_to >= ?)", day, day)
+}
+
+// scaleAt retrieves the active brackets in force on a date, lowest income first
+func scaleAt(db *gorm.DB, at time.Time) ([]IRSATaxBracket, error) {
+	var brackets []IRSATaxBracket
+	if err := inForceAt(db, at).Where("is_active = ?", true).
+		Order("min_income ASC, sort_order ASC").Find(&brackets).Error; err != nil {
+		return nil, fmt.Errorf("get IRSA brackets: %w", err)
+	}
+	return brackets, nil
+}
+
+// validateScales checks the IRSA scale on every date from `from` to `to` inclusive (open-ended when nil).
+// The scale can only change where a bracket version starts or ends, so those dates are the ones checked.
+func validateScales(tx *gorm.DB, from time.Time, to *time.Time) error {
+	from = dateOnly(from)
+	dates := []time.Time{from}
+
+	var starts []time.Time
+	db := tx.Model(&IRSATaxBracket{}).Where("effective_date > ?", from)
+	if to != nil {
+		db = db.Where("effective_date <= ?", dateOnly(*to))
+	}
+	if err := db.Distinct().Pluck("effective_date", &starts).Error; err != nil {
+		return fmt.Errorf("get IRSA scale changes: %w", err)
+	}
+	dates = append(dates, starts...)
+
+	var ends []time.Time
+	db = tx.Model(&IRSATaxBracket{}).Where("effective_to >= ?", from)
+	if to != nil {
+		db = db.Where("effective_to < ?", dateOnly(*to))
+	}
+	if err := db.Distinct().Pluck("effective_to", &ends).Error; err != nil {
+		return fmt.Errorf("get IRSA scale changes: %w", err)
+	}
+	for _, end := range ends {
+		dates = append(dates, end.AddDate(0, 0, 1))
+	}
+
+	for _, at := range dates {
+		brackets, err := scaleAt(tx, at)
+		if err != nil {
+			return err
+		}
+		if err := ValidateScale(brackets); err != nil {
+			return fmt.Errorf("IRSA scale on %s: %w", at.Format("2006-01-02"), err)
+		}
+	}
+	return nil
+}
+
+// GetIRSABracketsInForce retrieves the IRSA scale in force on a date, lowest income first
+func (r *Repo) GetIRSABracketsInForce(ctx context.Context, at time.Time) ([]IRSATaxBracket, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return scaleAt(r.db.WithContext(ctx), at)
+}
+
+// CreateIRSABracket creates a new IRSA tax bracket; the scale must stay contiguous from its effective date
+func (r *Repo) CreateIRSABracket(ctx context.Context, bracket *IRSATaxBracket) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	bracket.EffectiveDate = dateOnly(bracket.EffectiveDate)
+	bracket.CreatedAt = time.Now()
+	bracket.UpdatedAt = time.Now()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(bracket).Error; err != nil {
+			return fmt.Errorf("create IRSA bracket: %w", err)
+		}
+		return validateScales(tx, bracket.EffectiveDate, nil)
+	})
+}
+
+// UpdateIRSABracket updates an IRSA tax bracket in place; the scale must stay contiguous while it is in force
+func (r *Repo) UpdateIRSABracket(ctx context.Context, bracket *IRSATaxBracket) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	bracket.UpdatedAt = time.Now()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(bracket).Error; err != nil {
+			return fmt.Errorf("update IRSA bracket: %w", err)
+		}
+		return validateScales(tx, bracket.EffectiveDate, bracket.EffectiveTo)
+	})
+}
+
+// ReviseIRSABracket closes the current version of a bracket the day before revised.EffectiveDate
+// and creates revised as its new version
+func (r *Repo) ReviseIRSABracket(ctx context.Context, current *IRSATaxBracket, revised *IRSATaxBracket) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	revised.ID = uuid.Nil
+	revised.EffectiveDate = dateOnly(revised.EffectiveDate)
+	revised.EffectiveTo = nil
+	revised.CreatedAt = time.Now()
+	revised.UpdatedAt = time.Now()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked IRSATaxBracket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", current.ID).First(&locked).Error; err != nil {
+			return fmt.Errorf("lock IRSA bracket: %w", err)
+		}
+		if locked.EffectiveTo != nil {
+			return fmt.Errorf("only the current version of an IRSA bracket can be changed")
+		}
+		if !revised.EffectiveDate.After(dateOnly(locked.EffectiveDate)) {
+			return fmt.Errorf("effective_date must be after %s, when the current version took effect",
+				locked.EffectiveDate.Format("2006-01-02"))
+		}
+
+		closedOn := revised.EffectiveDate.AddDate(0, 0, -1)
+		if err := tx.Model(&locked).Updates(map[string]interface{}{
+			"effective_to": closedOn,
+			"updated_by":   revised.CreatedBy,
+			"updated_at":   time.Now(),
+		}).Error; err != nil {
+			return fmt.Errorf("close IRSA bracket version: %w", err)
+		}
+
+		if err := tx.Create(revised).Error; err != nil {
+			return fmt.Errorf("create IRSA bracket version: %w", err)
+		}
+		return validateScales(tx, revised.EffectiveDate, nil)
+	})
+}
+
+// ReplaceIRSAScale closes every bracket in force on effectiveDate the day before and creates brackets
+// as the scale from that date. A scale starting on a future effectiveDate is replaced outright.
+func (r *Repo) ReplaceIRSAScale(ctx context.Context, effectiveDate time.Time, brackets []IRSATaxBracket, createdBy uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	effectiveDate = dateOnly(effectiveDate)
+	if err := ValidateScale(brackets); err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var later IRSATaxBracket
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("effective_date > ?", effectiveDate).
+			Order("effective_date ASC").First(&later).Error
+		if err == nil {
+			return fmt.Errorf("a later IRSA scale starts on %s; replace that one instead", later.EffectiveDate.Format("2006-01-02"))
+		}
+		if err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("check later IRSA brackets: %w", err)
+		}
+
+		var sameDay int64
+		if err := tx.Model(&IRSATaxBracket{}).Where("effective_date = ?", effectiveDate).Count(&sameDay).Error; err != nil {
+			return fmt.Errorf("check IRSA brackets: %w", err)
+		}
+		if sameDay > 0 {
+			if !effectiveDate.After(dateOnly(time.Now())) {
+				return fmt.Errorf("an IRSA scale already took effect on %s; use a later effective_date", effectiveDate.Format("2006-01-02"))
+			}
+			if err := tx.Where("effective_date = ?", effectiveDate).Delete(&IRSATaxBracket{}).Error; err != nil {
+				return fmt.Errorf("delete future IRSA brackets: %w", err)
+			}
+		}
+
+		if err := tx.Model(&IRSATaxBracket{}).
+			Where("effective_date < ? AND (effective_to IS NULL OR effective_to >= ?)", effectiveDate, effectiveDate).
+			Updates(map[string]interface{}{
+				"effective_to": effectiveDate.AddDate(0, 0, -1),
+				"updated_by":   createdBy,
+				"updated_at":   time.Now(),
+			}).Error; err != nil {
+			return fmt.Errorf("close IRSA scale: %w", err)
+		}
+
+		for i := range brackets {
+			brackets[i].ID = uuid.Nil
+			brackets[i].IsActive = true
+			brackets[i].SortOrder = i + 1
+			brackets[i].EffectiveDate = effectiveDate
+			brackets[i].EffectiveTo = nil
+			brackets[i].CreatedBy = createdBy
+			brackets[i].CreatedAt = time.Now()
+			brackets[i].UpdatedAt = time.Now()
+		}
+		if err := tx.Create(&brackets).Error; err != nil {
+			return fmt.Errorf("create IRSA scale: %w", err)
+		}
+		return validateScales(tx, effectiveDate, nil)
+	})
+}
+
+// GetIRSABracketByID retrieves an IRSA tax bracket by ID
+func (r *Repo) GetIRSABracketByID(ctx context.Context, id uuid.UUID) (*IRSATaxBracket, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var bracket IRSATaxBracket
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&bracket).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("IRSA bracket not found")
+		}
+		return nil, fmt.Errorf("get IRSA bracket: %w", err)
+	}
+	return &bracket, nil
+}
+
+// ListIRSABrackets retrieves IRSA tax brackets with filtering
+func (r *Repo) ListIRSABrackets(ctx context.Context, query IRSATaxBracketListQuery) ([]IRSATaxBracket, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var brackets []IRSATaxBracket
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&IRSATaxBracket{})
+
+	// Apply filters
+	if query.IsActive != nil {
+		db = db.Where("is_active = ?", *query.IsActive)
+	}
+	if query.EffectiveAt != nil {
+		db = inForceAt(db, *query.EffectiveAt)
+	}
+
+	// Count total
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count IRSA brackets: %w", err)
+	}
+
+	// Apply pagination
+	limit := query.Limit
+	if limit == 0 {
+		limit = 50
+	}
+
+	if err := db.Limit(limit).Offset(query.Offset).Order("effective_date DESC, min_income ASC").Find(&brackets).Error; err != nil {
+		return nil, 0, fmt.Errorf("list IRSA brackets: %w", err)
+	}
+
+	return brackets, total, nil
+}
+
+// DeleteIRSABracket deletes an IRSA tax bracket; the scale must stay contiguous while it was in force
+func (r *Repo) DeleteIRSABracket(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var bracket IRSATaxBracket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&bracket).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("IRSA bracket not found")
+			}
+			return fmt.Errorf("get IRSA bracket: %w", err)
+		}
+
+		if err := tx.Delete(&bracket).Error; err != nil {
+			return fmt.Errorf("delete IRSA bracket: %w", err)
+		}
+		return validateScales(tx, bracket.EffectiveDate, bracket.EffectiveTo)
+	})
+}
@@ -0,0 +1,27 @@
+package taxrules
+
+import (
+	"go-server/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterRoutes registers tax rule routes
+func RegisterRoutes(rg *gin.RouterGroup, gormDB *gorm.DB) {
+	repo := NewRepo(gormDB)
+	handler := NewHandler(repo)
+
+	// IRSA tax bracket routes, shared by payroll and declarations (Admin only for write)
+	brackets := rg.Group("/irsa-brackets")
+	brackets.Use(middleware.AuthMiddleware())
+	{
+		brackets.POST("", middleware.RequireRole("admin"), handler.CreateIRSABracket)
+		brackets.GET("", middleware.RequireRole("admin", "hr", "accountant"), handler.ListIRSABrackets)
+		brackets.GET("/scale", middleware.RequireRole("admin", "hr", "accountant"), handler.GetIRSAScale)
+		brackets.PUT("/scale", middleware.RequireRole("admin"), handler.ReplaceIRSAScale)
+		brackets.GET("/:id", middleware.RequireRole("admin", "hr", "accountant"), handler.GetIRSABracketByID)
+		brackets.PUT("/:id", middleware.RequireRole("admin"), handler.UpdateIRSABracket)
+		brackets.DELETE("/:id", middleware.RequireRole("admin"), handler.DeleteIRSABracket)
+	}
+}